@@ -12,10 +12,12 @@ import (
 
 	"log"
 
+	"github.com/YelzhanWeb/uno-spicchio/internal/adapters/eventbus"
 	minio "github.com/YelzhanWeb/uno-spicchio/internal/adapters/minIO"
 	"github.com/YelzhanWeb/uno-spicchio/internal/adapters/postgre"
 	"github.com/YelzhanWeb/uno-spicchio/internal/config"
 	httpAdapter "github.com/YelzhanWeb/uno-spicchio/internal/controller/http"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 	"github.com/YelzhanWeb/uno-spicchio/internal/usecase"
 	"github.com/YelzhanWeb/uno-spicchio/pkg/jwt"
 	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
@@ -78,10 +80,25 @@ func main() {
 	tableRepo := postgre.NewTableRepository(db)
 	categoryRepo := postgre.NewCategoryRepository(db)
 	dishRepo := postgre.NewDishRepository(db)
+	modifierRepo := postgre.NewModifierRepository(db)
+	comboRepo := postgre.NewComboRepository(db)
+	menuRepo := postgre.NewMenuRepository(db)
 	ingredientRepo := postgre.NewIngredientRepository(db)
 	orderRepo := postgre.NewOrderRepository(db)
 	supplyRepo := postgre.NewSupplyRepository(db)
 	analyticsRepo := postgre.NewAnalyticsRepository(db)
+	voidRepo := postgre.NewVoidRepository(db)
+	paymentRepo := postgre.NewPaymentRepository(db)
+	promotionRepo := postgre.NewPromotionRepository(db)
+	reservationRepo := postgre.NewReservationRepository(db)
+	sectionRepo := postgre.NewSectionRepository(db)
+	waitlistRepo := postgre.NewWaitlistRepository(db)
+	prepRepo := postgre.NewPrepRepository(db)
+	stocktakeRepo := postgre.NewStocktakeRepository(db)
+	wasteRepo := postgre.NewWasteRepository(db)
+	supplierRepo := postgre.NewSupplierRepository(db)
+	purchaseOrderRepo := postgre.NewPurchaseOrderRepository(db)
+	txManager := postgre.NewTxManager(db)
 	logger.Success("✓ Repositories initialized")
 
 	// Initialize JWT token manager
@@ -89,17 +106,42 @@ func main() {
 	tokenManager := jwt.NewTokenManager(cfg.JWT.Secret, cfg.JWT.ExpirationDuration())
 	logger.Success("✓ JWT token manager initialized")
 
+	// Initialize event bus
+	eventBus := eventbus.New()
+
 	// Initialize services
 	logger.Info("Initializing services...")
 	authService := usecase.NewAuthService(userRepo, tokenManager)
 	userService := usecase.NewUserService(userRepo)
-	orderService := usecase.NewOrderService(orderRepo, dishRepo, ingredientRepo, tableRepo)
-	dishService := usecase.NewDishService(dishRepo)
-	ingredientService := usecase.NewIngredientService(ingredientRepo)
-	supplyService := usecase.NewSupplyService(supplyRepo)
-	tableService := usecase.NewTableService(tableRepo)
+	orderService := usecase.NewOrderService(orderRepo, dishRepo, modifierRepo, comboRepo, menuRepo, ingredientRepo, prepRepo, tableRepo, voidRepo, wasteRepo, paymentRepo, promotionRepo, sectionRepo, txManager, eventBus, domain.ServiceChargePolicy{
+		Rate:      cfg.Pricing.ServiceChargeRate,
+		MinGuests: cfg.Pricing.ServiceChargeMinGuests,
+	})
+	paymentService := usecase.NewPaymentService(orderRepo, paymentRepo, tableRepo, sectionRepo, txManager, eventBus)
+	promotionService := usecase.NewPromotionService(promotionRepo)
+	dishService := usecase.NewDishService(dishRepo, modifierRepo, menuRepo, ingredientRepo, txManager, eventBus)
+	menuService := usecase.NewMenuService(menuRepo, dishRepo)
+	comboService := usecase.NewComboService(comboRepo, dishRepo, txManager)
+	ingredientService := usecase.NewIngredientService(ingredientRepo, txManager, eventBus)
+	prepService := usecase.NewPrepService(prepRepo, ingredientRepo, txManager, eventBus)
+	stocktakeService := usecase.NewStocktakeService(stocktakeRepo, ingredientRepo, txManager, eventBus)
+	wasteService := usecase.NewWasteService(wasteRepo, dishRepo, ingredientRepo, prepRepo, txManager, eventBus)
+	supplyService := usecase.NewSupplyService(supplyRepo, supplierRepo, ingredientRepo, txManager, eventBus)
+	supplierService := usecase.NewSupplierService(supplierRepo, ingredientRepo)
+	purchaseOrderService := usecase.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, supplyRepo, ingredientRepo, txManager, eventBus)
+	tableService := usecase.NewTableService(tableRepo, sectionRepo, txManager, eventBus)
+	waitlistService := usecase.NewWaitlistService(waitlistRepo, tableRepo, analyticsRepo, txManager, eventBus, domain.WaitlistPolicy{
+		Lookback:    time.Duration(cfg.Waitlist.LookbackDays) * 24 * time.Hour,
+		DefaultTurn: time.Duration(cfg.Waitlist.DefaultTurnMinutes) * time.Minute,
+	})
+	sectionService := usecase.NewSectionService(sectionRepo, tableRepo, userRepo, orderRepo, txManager)
+	reservationService := usecase.NewReservationService(reservationRepo, tableRepo, txManager, eventBus, domain.ReservationPolicy{
+		Duration:    time.Duration(cfg.Reservations.DurationMinutes) * time.Minute,
+		HoldBefore:  time.Duration(cfg.Reservations.HoldMinutes) * time.Minute,
+		NoShowAfter: time.Duration(cfg.Reservations.NoShowMinutes) * time.Minute,
+	})
 	categoryService := usecase.NewCategoryService(categoryRepo)
-	analyticsService := usecase.NewAnalyticsService(analyticsRepo)
+	analyticsService := usecase.NewAnalyticsService(analyticsRepo, dishRepo, prepRepo)
 	logger.Success("✓ Services initialized")
 
 	// Setup router
@@ -108,13 +150,26 @@ func main() {
 		authService,
 		userService,
 		orderService,
+		paymentService,
+		promotionService,
 		dishService,
+		comboService,
+		menuService,
 		ingredientService,
+		prepService,
+		stocktakeService,
+		wasteService,
 		supplyService,
+		supplierService,
+		purchaseOrderService,
 		tableService,
+		reservationService,
+		waitlistService,
+		sectionService,
 		categoryService,
 		analyticsService,
 		storage, // MinIO как ports.FileStorage
+		eventBus,
 		tokenManager,
 	)
 
@@ -146,6 +201,9 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// SSE-потоки живут, пока клиент не отключится; при остановке закрываем
+	// подписки, чтобы Shutdown не ждал их до таймаута
+	server.RegisterOnShutdown(eventBus.Close)
 
 	// Start server in a goroutine
 	go func() {
@@ -162,12 +220,21 @@ func main() {
 		}
 	}()
 
+	// Background jobs: hold tables before bookings and release no-shows,
+	// offer freed tables to the walk-in waitlist
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go reservationService.Run(schedulerCtx, cfg.Reservations.CheckInterval())
+	go waitlistService.Run(schedulerCtx, eventBus)
+	go dishService.Run(schedulerCtx, eventBus)
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Warning("⚠ Shutting down server...")
+	stopScheduler()
 
 	// Graceful shutdown
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
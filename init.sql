@@ -19,14 +19,95 @@ CREATE TABLE users (
 CREATE TABLE tables (
     id SERIAL PRIMARY KEY,
     name VARCHAR(20) NOT NULL UNIQUE,
+    -- количество посадочных мест
+    capacity INT NOT NULL DEFAULT 4 CHECK (capacity > 0),
     status VARCHAR(20) NOT NULL CHECK (
         status IN ('busy', 'reserve', 'free')
-    ) DEFAULT 'free'
+    ) DEFAULT 'free',
+    -- расположение на плане зала
+    zone VARCHAR(20) NOT NULL DEFAULT 'hall' CHECK (
+        zone IN ('hall', 'terrace', 'bar')
+    ),
+    shape VARCHAR(20) NOT NULL DEFAULT 'square' CHECK (
+        shape IN ('square', 'round', 'rectangle')
+    ),
+    pos_x NUMERIC(8, 2) NOT NULL DEFAULT 0 CHECK (pos_x >= 0),
+    pos_y NUMERIC(8, 2) NOT NULL DEFAULT 0 CHECK (pos_y >= 0),
+    width NUMERIC(8, 2) NOT NULL DEFAULT 80 CHECK (width > 0),
+    height NUMERIC(8, 2) NOT NULL DEFAULT 80 CHECK (height > 0),
+    rotation INT NOT NULL DEFAULT 0 CHECK (
+        rotation >= 0
+        AND rotation < 360
+    )
+);
+-- Бронирования столов
+CREATE TABLE reservations (
+    id SERIAL PRIMARY KEY,
+    table_id INT NOT NULL REFERENCES tables (id) ON DELETE CASCADE,
+    guest_name VARCHAR(100) NOT NULL,
+    guest_phone VARCHAR(30),
+    party_size INT NOT NULL CHECK (party_size > 0),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (
+        status IN (
+            'booked',
+            'seated',
+            'cancelled',
+            'no_show'
+        )
+    ) DEFAULT 'booked',
+    notes TEXT,
+    created_by INT REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+-- Очередь гостей без брони (walk-in)
+CREATE TABLE waitlist (
+    id SERIAL PRIMARY KEY,
+    guest_name VARCHAR(100) NOT NULL,
+    guest_phone VARCHAR(30),
+    party_size INT NOT NULL CHECK (party_size > 0),
+    status VARCHAR(20) NOT NULL CHECK (
+        status IN (
+            'waiting',
+            'notified',
+            'seated',
+            'cancelled'
+        )
+    ) DEFAULT 'waiting',
+    -- обещанное ожидание в минутах на момент постановки в очередь
+    quoted_wait INT NOT NULL DEFAULT 0,
+    -- стол, освободившийся для гостей (notified) или за который их посадили (seated)
+    table_id INT REFERENCES tables (id) ON DELETE SET NULL,
+    notes TEXT,
+    created_by INT REFERENCES users (id),
+    notified_at TIMESTAMP,
+    seated_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Закрепление столов за официантами на смену (секции)
+CREATE TABLE table_assignments (
+    shift_date DATE NOT NULL,
+    table_id INT NOT NULL REFERENCES tables (id) ON DELETE CASCADE,
+    waiter_id INT NOT NULL REFERENCES users (id),
+    assigned_by INT REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shift_date, table_id)
 );
 -- Категории блюд
 CREATE TABLE categories (
     id SERIAL PRIMARY KEY,
-    name VARCHAR(100) NOT NULL UNIQUE
+    name VARCHAR(100) NOT NULL UNIQUE,
+    -- кухонная станция (горячий цех, холодный цех, бар...)
+    station VARCHAR(30) NOT NULL DEFAULT 'kitchen',
+    -- ставка НДС в процентах для блюд категории
+    vat_rate NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (
+        vat_rate >= 0
+        AND vat_rate <= 100
+    )
 );
 -- Блюда
 CREATE TABLE dishes (
@@ -36,7 +117,14 @@ CREATE TABLE dishes (
     description TEXT,
     price NUMERIC(10, 2) NOT NULL CHECK (price >= 0),
     photo_url TEXT,
-    is_active BOOLEAN DEFAULT true
+    is_active BOOLEAN DEFAULT true,
+    -- собственная ставка НДС; NULL — берётся ставка категории
+    vat_rate NUMERIC(5, 2) CHECK (
+        vat_rate >= 0
+        AND vat_rate <= 100
+    ),
+    -- снято с продажи кухней вручную (86), независимо от остатков
+    sold_out BOOLEAN NOT NULL DEFAULT false
 );
 -- Ингредиенты на складе
 CREATE TABLE ingredients (
@@ -45,7 +133,11 @@ CREATE TABLE ingredients (
     unit VARCHAR(20) NOT NULL,
     -- кг, литр, шт
     qty NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (qty >= 0),
-    min_qty NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (min_qty >= 0)
+    -- зарезервировано под заказы в статусе new
+    reserved_qty NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (reserved_qty >= 0),
+    min_qty NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (min_qty >= 0),
+    -- себестоимость единицы: средневзвешенная цена закупок
+    unit_cost NUMERIC(12, 4) NOT NULL DEFAULT 0 CHECK (unit_cost >= 0)
 );
 -- Связь блюд и ингредиентов
 CREATE TABLE dish_ingredients (
@@ -54,6 +146,126 @@ CREATE TABLE dish_ingredients (
     qty_per_dish NUMERIC(10, 2) NOT NULL CHECK (qty_per_dish > 0),
     PRIMARY KEY (dish_id, ingredient_id)
 );
+-- Заготовки (тесто, соус, песто): ингредиент, который кухня делает сама партиями.
+-- made_to_order — не хранится на складе, заказы списывают сразу его состав
+CREATE TABLE prep_items (
+    ingredient_id INT PRIMARY KEY REFERENCES ingredients (id) ON DELETE CASCADE,
+    batch_yield NUMERIC(10, 2) NOT NULL CHECK (batch_yield > 0),
+    made_to_order BOOLEAN NOT NULL DEFAULT false
+);
+-- Состав одной партии заготовки
+CREATE TABLE prep_ingredients (
+    prep_id INT NOT NULL REFERENCES prep_items (ingredient_id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    qty_per_batch NUMERIC(10, 2) NOT NULL CHECK (qty_per_batch > 0),
+    PRIMARY KEY (prep_id, ingredient_id),
+    CHECK (prep_id <> ingredient_id)
+);
+-- Меню (завтрак, ланч, ужин, сезонное) с расписанием по дням недели и времени
+CREATE TABLE menus (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    -- битовая маска дней недели (бит 0 — воскресенье), 0 — каждый день
+    days_mask INT NOT NULL DEFAULT 0 CHECK (
+        days_mask >= 0
+        AND days_mask < 128
+    ),
+    -- окно в формате HH:MM, может переходить через полночь
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    -- сезонное меню
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Блюда меню; price — цена блюда в этом меню, NULL — обычная цена блюда
+CREATE TABLE menu_dishes (
+    menu_id INT NOT NULL REFERENCES menus (id) ON DELETE CASCADE,
+    dish_id INT NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    price NUMERIC(10, 2) CHECK (price >= 0),
+    PRIMARY KEY (menu_id, dish_id)
+);
+-- Группы модификаторов блюда (размер, добавки, "без ...")
+CREATE TABLE modifier_groups (
+    id SERIAL PRIMARY KEY,
+    dish_id INT NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    is_required BOOLEAN NOT NULL DEFAULT false,
+    min_select INT NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    -- 0 — без ограничения
+    max_select INT NOT NULL DEFAULT 0 CHECK (max_select >= 0),
+    position INT NOT NULL DEFAULT 0
+);
+-- Варианты внутри группы
+CREATE TABLE modifier_options (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES modifier_groups (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    position INT NOT NULL DEFAULT 0
+);
+-- Изменение рецепта при выборе варианта: > 0 — добавка, < 0 — убрать из блюда
+CREATE TABLE modifier_option_ingredients (
+    option_id INT NOT NULL REFERENCES modifier_options (id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    qty_delta NUMERIC(10, 2) NOT NULL CHECK (qty_delta <> 0),
+    PRIMARY KEY (option_id, ingredient_id)
+);
+-- Комбо и сет-меню: несколько блюд по цене набора
+CREATE TABLE combos (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    price NUMERIC(10, 2) NOT NULL CHECK (price >= 0),
+    photo_url VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Слоты комбо ("Пицца", "Напиток", "Десерт"); в каждом гость выбирает одно блюдо
+CREATE TABLE combo_slots (
+    id SERIAL PRIMARY KEY,
+    combo_id INT NOT NULL REFERENCES combos (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    position INT NOT NULL DEFAULT 0
+);
+-- Блюда, доступные в слоте; price_delta — доплата за выбор (например, стейк вместо курицы)
+CREATE TABLE combo_slot_choices (
+    slot_id INT NOT NULL REFERENCES combo_slots (id) ON DELETE CASCADE,
+    dish_id INT NOT NULL REFERENCES dishes (id) ON DELETE CASCADE,
+    price_delta NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (price_delta >= 0),
+    PRIMARY KEY (slot_id, dish_id)
+);
+-- Акции и скидки (в том числе промокоды и happy hour)
+CREATE TABLE promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(30) NOT NULL CHECK (
+        type IN (
+            'order_percent',
+            'order_fixed',
+            'category_percent',
+            'buy_x_get_y'
+        )
+    ),
+    value NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (value >= 0),
+    category_id INT REFERENCES categories (id) ON DELETE CASCADE,
+    dish_id INT REFERENCES dishes (id) ON DELETE CASCADE,
+    buy_qty INT NOT NULL DEFAULT 0 CHECK (buy_qty >= 0),
+    get_qty INT NOT NULL DEFAULT 0 CHECK (get_qty >= 0),
+    min_subtotal NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (min_subtotal >= 0),
+    -- без кода акция применяется автоматически
+    code VARCHAR(50) UNIQUE,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    -- окно happy hour в формате HH:MM, может переходить через полночь
+    happy_hour_start VARCHAR(5),
+    happy_hour_end VARCHAR(5),
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
 -- Заказы
 CREATE TABLE orders (
     id SERIAL PRIMARY KEY,
@@ -64,22 +276,220 @@ CREATE TABLE orders (
             'new',
             'in_progress',
             'ready',
-            'paid'
+            'paid',
+            'cancelled',
+            'merged'
         )
     ) DEFAULT 'new',
+    guests INT CHECK (guests > 0),
+    -- сумма позиций до скидок, скидка, налог, сервисный сбор и итог к оплате
+    subtotal NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (subtotal >= 0),
+    discount NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (discount >= 0),
+    tax NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (tax >= 0),
+    service_charge_rate NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (
+        service_charge_rate >= 0
+        AND service_charge_rate <= 100
+    ),
+    service_charge NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (service_charge >= 0),
     total NUMERIC(10, 2) DEFAULT 0 CHECK (total >= 0),
+    promo_code VARCHAR(50),
     notes TEXT,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+-- Комбо в заказе — одна строка счёта; название и цена набора фиксируются при добавлении
+CREATE TABLE order_combos (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    combo_id INT REFERENCES combos (id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    qty INT NOT NULL CHECK (qty > 0),
+    -- цена одного набора с доплатами за выбор и модификаторы
+    price NUMERIC(10, 2) NOT NULL CHECK (price >= 0),
+    seat INT CHECK (seat > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
 -- Позиции в заказе
 CREATE TABLE order_items (
     id SERIAL PRIMARY KEY,
     order_id INT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
     dish_id INT NOT NULL REFERENCES dishes (id),
+    -- блюдо из комбо: цена позиции — его доля в цене набора
+    combo_line_id INT REFERENCES order_combos (id) ON DELETE CASCADE,
     qty INT NOT NULL CHECK (qty > 0),
     price NUMERIC(10, 2) NOT NULL CHECK (price >= 0),
-    notes TEXT
+    -- скидка на всю строку (qty * price)
+    discount NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (discount >= 0),
+    -- ставка НДС на момент добавления позиции и налог по строке
+    vat_rate NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    tax NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (tax >= 0),
+    notes TEXT,
+    -- место гостя за столом (для разделения счёта)
+    seat INT CHECK (seat > 0),
+    status VARCHAR(20) NOT NULL CHECK (
+        status IN (
+            'queued',
+            'cooking',
+            'done',
+            'served'
+        )
+    ) DEFAULT 'queued',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Выбранные модификаторы позиции; название и надбавка фиксируются при добавлении
+CREATE TABLE order_item_modifiers (
+    id SERIAL PRIMARY KEY,
+    order_item_id INT NOT NULL REFERENCES order_items (id) ON DELETE CASCADE,
+    option_id INT REFERENCES modifier_options (id) ON DELETE SET NULL,
+    group_name VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    price_delta NUMERIC(10, 2) NOT NULL DEFAULT 0
+);
+-- Резерв ингредиентов под заказ в статусе new: ровно то, что прибавлено к
+-- ingredients.reserved_qty, чтобы снять его даже после смены рецепта
+CREATE TABLE order_reservations (
+    order_id INT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES ingredients (id) ON DELETE RESTRICT,
+    qty NUMERIC(10, 2) NOT NULL CHECK (qty >= 0),
+    PRIMARY KEY (order_id, ingredient_id)
+);
+-- Разделение счёта между гостями
+CREATE TABLE bill_splits (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Какие позиции входят в часть счёта
+CREATE TABLE bill_split_items (
+    split_id INT NOT NULL REFERENCES bill_splits (id) ON DELETE CASCADE,
+    order_item_id INT NOT NULL REFERENCES order_items (id) ON DELETE CASCADE,
+    qty INT NOT NULL CHECK (qty > 0),
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount >= 0),
+    PRIMARY KEY (split_id, order_item_id)
+);
+-- Оплаты (заказ может оплачиваться несколькими платежами)
+CREATE TABLE payments (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    split_id INT REFERENCES bill_splits (id) ON DELETE SET NULL,
+    method VARCHAR(20) NOT NULL CHECK (
+        method IN ('cash', 'card', 'transfer')
+    ),
+    -- amount идёт в счёт заказа; чаевые и сервисный сбор платятся сверх него
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+    tip NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (tip >= 0),
+    service_charge NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (service_charge >= 0),
+    tendered NUMERIC(10, 2) NOT NULL CHECK (tendered >= 0),
+    change NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (change >= 0),
+    created_by INT NOT NULL REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Применённые к заказу скидки: по акциям (пересчитываются) и comp от менеджера
+CREATE TABLE order_discounts (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    -- NULL — скидка на весь заказ
+    order_item_id INT REFERENCES order_items (id) ON DELETE CASCADE,
+    promotion_id INT REFERENCES promotions (id) ON DELETE SET NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('promotion', 'comp')),
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percent', 'fixed')),
+    value NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (value >= 0),
+    amount NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    reason TEXT,
+    created_by INT REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Отмены заказов и позиций (void)
+CREATE TABLE order_voids (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    -- NULL = отмена всего заказа; без FK, т.к. позиция удаляется после полной отмены
+    order_item_id INT,
+    dish_id INT REFERENCES dishes (id),
+    qty INT NOT NULL DEFAULT 0 CHECK (qty >= 0),
+    amount NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    reason_code VARCHAR(30) NOT NULL CHECK (
+        reason_code IN (
+            'customer_request',
+            'wrong_order',
+            'quality_issue',
+            'long_wait',
+            'duplicate',
+            'other'
+        )
+    ),
+    reason_text TEXT,
+    stock_action VARCHAR(10) NOT NULL CHECK (
+        stock_action IN ('return', 'waste')
+    ),
+    status VARCHAR(20) NOT NULL CHECK (
+        status IN (
+            'pending',
+            'approved',
+            'rejected'
+        )
+    ) DEFAULT 'pending',
+    voided_by INT NOT NULL REFERENCES users (id),
+    approved_by INT REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+-- Поставщики
+CREATE TABLE suppliers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    contact_name VARCHAR(100),
+    phone VARCHAR(30),
+    email VARCHAR(100),
+    address TEXT,
+    notes TEXT,
+    -- сколько дней от заказа до поставки
+    lead_time_days INT NOT NULL DEFAULT 1 CHECK (lead_time_days >= 0),
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Каталог поставщика: что он возит и по какой цене за единицу ингредиента
+CREATE TABLE supplier_ingredients (
+    supplier_id INT NOT NULL REFERENCES suppliers (id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    unit_price NUMERIC(12, 2) NOT NULL CHECK (unit_price >= 0),
+    sku VARCHAR(50),
+    min_order_qty NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (min_order_qty >= 0),
+    PRIMARY KEY (supplier_id, ingredient_id)
+);
+-- Заказы поставщикам: draft → sent → partially_received → received
+CREATE TABLE purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers (id),
+    status VARCHAR(20) NOT NULL CHECK (
+        status IN (
+            'draft',
+            'sent',
+            'partially_received',
+            'received',
+            'cancelled'
+        )
+    ) DEFAULT 'draft',
+    expected_at DATE,
+    notes TEXT,
+    created_by INT REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP,
+    received_at TIMESTAMP
+);
+
+CREATE TABLE purchase_order_items (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES ingredients (id),
+    qty_ordered NUMERIC(10, 2) NOT NULL CHECK (qty_ordered > 0),
+    qty_received NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (qty_received >= 0),
+    unit_price NUMERIC(12, 2) CHECK (unit_price >= 0),
+    UNIQUE (purchase_order_id, ingredient_id)
 );
 -- Поставки ингредиентов
 CREATE TABLE supplies (
@@ -87,8 +497,113 @@ CREATE TABLE supplies (
     ingredient_id INT NOT NULL REFERENCES ingredients (id),
     qty NUMERIC(10, 2) NOT NULL CHECK (qty > 0),
     supplier_name VARCHAR(100) NOT NULL,
+    supplier_id INT REFERENCES suppliers (id) ON DELETE SET NULL,
+    -- заказ поставщику, по которому пришла поставка
+    purchase_order_id INT REFERENCES purchase_orders (id) ON DELETE SET NULL,
+    -- цена закупки за единицу ингредиента, в валюте накладной
+    unit_price NUMERIC(12, 2) CHECK (unit_price >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'KZT',
+    invoice_number VARCHAR(50),
+    -- скан или PDF накладной в файловом хранилище
+    invoice_url TEXT,
+    received_by INT REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Выпуск партий заготовок: списывает состав, приходует заготовку
+CREATE TABLE production_batches (
+    id SERIAL PRIMARY KEY,
+    -- ссылка на ингредиент, а не на prep_items: история выпуска остаётся,
+    -- даже если заготовку снова сделали обычным ингредиентом
+    prep_id INT NOT NULL REFERENCES ingredients (id) ON DELETE RESTRICT,
+    batches NUMERIC(10, 2) NOT NULL CHECK (batches > 0),
+    yield_qty NUMERIC(10, 2) NOT NULL CHECK (yield_qty > 0),
+    -- себестоимость всей партии по средним ценам состава
+    cost NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    user_id INT REFERENCES users (id) ON DELETE SET NULL,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- Журнал движения остатков: каждая операция, меняющая qty ингредиента.
+-- Только добавление записей; qty со знаком, ref_id — заказ, поставка, партия или инвентаризация.
+-- Ингредиент с историей движения удалить нельзя (RESTRICT), чтобы журнал не терялся
+CREATE TABLE stock_movements (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INT NOT NULL REFERENCES ingredients (id) ON DELETE RESTRICT,
+    type VARCHAR(20) NOT NULL CHECK (
+        type IN (
+            'supply',
+            'order_consumption',
+            'void_return',
+            'waste',
+            'count_adjustment',
+            'transfer',
+            'production'
+        )
+    ),
+    qty NUMERIC(10, 2) NOT NULL,
+    qty_before NUMERIC(10, 2) NOT NULL,
+    qty_after NUMERIC(10, 2) NOT NULL,
+    ref_id INT,
+    user_id INT REFERENCES users (id) ON DELETE SET NULL,
+    note TEXT,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+-- Инвентаризация: сессия пересчёта склада. Пока open — вносятся подсчёты,
+-- при проведении расхождения пишутся в журнал и сессия закрывается
+CREATE TABLE stocktakes (
+    id SERIAL PRIMARY KEY,
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'posted', 'cancelled')),
+    started_by INT REFERENCES users (id) ON DELETE SET NULL,
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_by INT REFERENCES users (id) ON DELETE SET NULL,
+    closed_at TIMESTAMP
+);
+-- Подсчитанные остатки; system_qty и unit_cost — остаток и себестоимость
+-- в момент подсчёта, расхождение считается от них
+CREATE TABLE stocktake_counts (
+    stocktake_id INT NOT NULL REFERENCES stocktakes (id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES ingredients (id) ON DELETE RESTRICT,
+    counted_qty NUMERIC(10, 2) NOT NULL CHECK (counted_qty >= 0),
+    counted_by INT REFERENCES users (id) ON DELETE SET NULL,
+    counted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    system_qty NUMERIC(10, 2) NOT NULL,
+    unit_cost NUMERIC(12, 4) NOT NULL,
+    PRIMARY KEY (stocktake_id, ingredient_id)
+);
+-- Списания: испорченные или уроненные ингредиенты и готовые блюда.
+-- Списывается либо ингредиент, либо блюдо (по рецепту)
+CREATE TABLE waste_entries (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INT REFERENCES ingredients (id) ON DELETE RESTRICT,
+    dish_id INT REFERENCES dishes (id) ON DELETE RESTRICT,
+    qty NUMERIC(10, 2) NOT NULL CHECK (qty > 0),
+    reason VARCHAR(20) NOT NULL CHECK (
+        reason IN (
+            'spoiled',
+            'expired',
+            'dropped',
+            'burnt',
+            'returned',
+            'other'
+        )
+    ),
+    note TEXT,
+    photo_url TEXT,
+    -- себестоимость списанного по средним ценам на момент списания
+    cost NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    user_id INT REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((ingredient_id IS NULL) <> (dish_id IS NULL))
+);
+-- Что фактически списано со склада по каждой записи
+CREATE TABLE waste_items (
+    waste_id INT NOT NULL REFERENCES waste_entries (id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES ingredients (id) ON DELETE RESTRICT,
+    qty NUMERIC(10, 2) NOT NULL,
+    unit_cost NUMERIC(12, 4) NOT NULL DEFAULT 0,
+    PRIMARY KEY (waste_id, ingredient_id)
+);
 -- Индексы для производительности
 CREATE INDEX idx_orders_status ON orders (status);
 
@@ -102,14 +617,73 @@ CREATE INDEX idx_order_items_order_id ON order_items (order_id);
 
 CREATE INDEX idx_order_items_dish_id ON order_items (dish_id);
 
+CREATE INDEX idx_order_items_status ON order_items (status);
+
 CREATE INDEX idx_dishes_category_id ON dishes (category_id);
 
 CREATE INDEX idx_dishes_is_active ON dishes (is_active);
 
+CREATE INDEX idx_menu_dishes_dish_id ON menu_dishes (dish_id);
+
+CREATE INDEX idx_modifier_groups_dish_id ON modifier_groups (dish_id);
+
+CREATE INDEX idx_modifier_options_group_id ON modifier_options (group_id);
+
+CREATE INDEX idx_order_item_modifiers_item_id ON order_item_modifiers (order_item_id);
+
+CREATE INDEX idx_combo_slots_combo_id ON combo_slots (combo_id);
+
+CREATE INDEX idx_order_combos_order_id ON order_combos (order_id);
+
+CREATE INDEX idx_order_items_combo_line_id ON order_items (combo_line_id);
+
+CREATE INDEX idx_bill_splits_order_id ON bill_splits (order_id);
+
+CREATE INDEX idx_payments_order_id ON payments (order_id);
+
+CREATE INDEX idx_payments_created_at ON payments (created_at);
+
+CREATE INDEX idx_order_discounts_order_id ON order_discounts (order_id);
+
+CREATE INDEX idx_order_discounts_promotion_id ON order_discounts (promotion_id);
+
+CREATE INDEX idx_order_voids_order_id ON order_voids (order_id);
+
+CREATE INDEX idx_order_voids_status ON order_voids (status);
+
 CREATE INDEX idx_supplies_ingredient_id ON supplies (ingredient_id);
 
 CREATE INDEX idx_supplies_created_at ON supplies (created_at);
 
+CREATE INDEX idx_supplies_supplier_id ON supplies (supplier_id, ingredient_id, created_at);
+
+CREATE INDEX idx_supplier_ingredients_ingredient ON supplier_ingredients (ingredient_id);
+
+CREATE INDEX idx_purchase_orders_supplier ON purchase_orders (supplier_id, created_at);
+
+CREATE INDEX idx_purchase_orders_status ON purchase_orders (status);
+
+CREATE INDEX idx_prep_ingredients_ingredient_id ON prep_ingredients (ingredient_id);
+
+CREATE INDEX idx_production_batches_prep_id ON production_batches (prep_id, created_at);
+
+CREATE INDEX idx_stock_movements_ingredient ON stock_movements (ingredient_id, created_at);
+
+CREATE INDEX idx_stock_movements_type ON stock_movements (type, created_at);
+
+CREATE INDEX idx_waste_entries_created ON waste_entries (created_at);
+
+-- одновременно открыта только одна инвентаризация
+CREATE UNIQUE INDEX idx_stocktakes_one_open ON stocktakes (status) WHERE status = 'open';
+
+CREATE INDEX idx_table_assignments_waiter ON table_assignments (waiter_id, shift_date);
+
+CREATE INDEX idx_reservations_table_time ON reservations (table_id, starts_at);
+
+CREATE INDEX idx_reservations_status_time ON reservations (status, starts_at);
+
+CREATE INDEX idx_waitlist_status ON waitlist (status, created_at);
+
 -- === USERS TABLE SEED DATA ===
 INSERT INTO
     users (
@@ -161,21 +735,31 @@ VALUES
 
 -- === TABLES SEED DATA ===
 INSERT INTO
-    tables (name, status)
-VALUES ('Table 1', 'free'),
-    ('Table 2', 'busy'),
-    ('Table 3', 'reserve'),
-    ('Table 4', 'free'),
-    ('Table 5', 'free');
+    tables (
+        name,
+        capacity,
+        status,
+        zone,
+        shape,
+        pos_x,
+        pos_y,
+        width,
+        height
+    )
+VALUES ('Table 1', 2, 'free', 'hall', 'round', 40, 40, 80, 80),
+    ('Table 2', 4, 'busy', 'hall', 'square', 160, 40, 80, 80),
+    ('Table 3', 4, 'reserve', 'hall', 'square', 280, 40, 80, 80),
+    ('Table 4', 6, 'free', 'terrace', 'rectangle', 40, 40, 160, 80),
+    ('Table 5', 8, 'free', 'terrace', 'rectangle', 240, 40, 200, 80);
 
 -- === CATEGORIES SEED DATA ===
 INSERT INTO
-    categories (name)
-VALUES ('Salads'),
-    ('Soups'),
-    ('Main Dishes'),
-    ('Desserts'),
-    ('Drinks');
+    categories (name, station)
+VALUES ('Salads', 'cold'),
+    ('Soups', 'hot'),
+    ('Main Dishes', 'hot'),
+    ('Desserts', 'pastry'),
+    ('Drinks', 'bar');
 
 -- === DISHES SEED DATA ===
 INSERT INTO
@@ -374,6 +958,7 @@ INSERT INTO
         waiter_id,
         table_number,
         status,
+        subtotal,
         total,
         notes
     )
@@ -382,14 +967,16 @@ VALUES (
         2,
         'in_progress',
         9000,
+        9000,
         'Customer allergic to nuts'
     ),
-    (3, 3, 'new', 6500, NULL),
+    (3, 3, 'new', 6500, 6500, NULL),
     (
         3,
         1,
         'paid',
         12000,
+        12000,
         'VIP guest'
     );
 
@@ -400,30 +987,184 @@ INSERT INTO
         dish_id,
         qty,
         price,
-        notes
+        notes,
+        status
     )
-VALUES (1, 1, 1, 2500, NULL),
-    (1, 4, 1, 7500, 'Medium rare'),
-    (2, 5, 1, 6500, 'Less spicy'),
-    (3, 4, 1, 7500, NULL),
+VALUES (1, 1, 1, 2500, NULL, 'done'),
+    (1, 4, 1, 7500, 'Medium rare', 'cooking'),
+    (2, 5, 1, 6500, 'Less spicy', 'queued'),
+    (3, 4, 1, 7500, NULL, 'served'),
     (
         3,
         6,
         1,
         3000,
-        'Extra topping'
+        'Extra topping',
+        'served'
     );
 
+-- === SUPPLIERS SEED DATA ===
+INSERT INTO
+    suppliers (
+        name,
+        contact_name,
+        phone,
+        lead_time_days
+    )
+VALUES ('FreshMeat Co', 'Arman', '+77010000001', 1),
+    ('GreenFarm', 'Dana', '+77010000002', 1),
+    ('VeggieWorld', 'Timur', '+77010000003', 2),
+    ('DairyBest', 'Aliya', '+77010000004', 1),
+    ('CoffeePlanet', 'Marat', '+77010000005', 5),
+    ('CitrusHouse', 'Saule', '+77010000006', 3);
+
+INSERT INTO
+    supplier_ingredients (
+        supplier_id,
+        ingredient_id,
+        unit_price
+    )
+VALUES (1, 1, 2200),
+    (1, 2, 3800),
+    (2, 4, 1500),
+    (2, 6, 900),
+    (3, 5, 1100),
+    (3, 6, 850),
+    (4, 7, 4200),
+    (4, 10, 450),
+    (5, 11, 9500),
+    (6, 12, 1300);
+
 -- === SUPPLIES SEED DATA ===
 INSERT INTO
     supplies (
         ingredient_id,
         qty,
-        supplier_name
+        supplier_name,
+        supplier_id
+    )
+VALUES (1, 5, 'FreshMeat Co', 1),
+    (4, 3, 'GreenFarm', 2),
+    (5, 4, 'VeggieWorld', 3),
+    (10, 10, 'DairyBest', 4),
+    (11, 2, 'CoffeePlanet', 5),
+    (12, 5, 'CitrusHouse', 6);
+-- === MODIFIERS SEED DATA ===
+INSERT INTO
+    modifier_groups (
+        dish_id,
+        name,
+        is_required,
+        min_select,
+        max_select,
+        position
     )
-VALUES (1, 5, 'FreshMeat Co'),
-    (4, 3, 'GreenFarm'),
-    (5, 4, 'VeggieWorld'),
-    (10, 10, 'DairyBest'),
-    (11, 2, 'CoffeePlanet'),
-    (12, 5, 'CitrusHouse');
\ No newline at end of file
+SELECT d.id, v.name, v.is_required, v.min_select, v.max_select, v.position
+FROM (
+        VALUES ('Cappuccino', 'Size', true, 1, 1, 1),
+            ('Caesar Salad', 'Extras', false, 0, 2, 1),
+            ('Caesar Salad', 'Remove', false, 0, 0, 2)
+    ) AS v(dish_name, name, is_required, min_select, max_select, position)
+    JOIN dishes d ON d.name = v.dish_name;
+
+INSERT INTO
+    modifier_options (group_id, name, price_delta, position)
+SELECT g.id, v.name, v.price_delta, v.position
+FROM (
+        VALUES ('Cappuccino', 'Size', 'Regular', 0, 1),
+            ('Cappuccino', 'Size', 'Large', 400, 2),
+            ('Caesar Salad', 'Extras', 'Extra cheese', 300, 1),
+            ('Caesar Salad', 'Extras', 'Extra chicken', 900, 2),
+            ('Caesar Salad', 'Remove', 'No cheese', 0, 1)
+    ) AS v(dish_name, group_name, name, price_delta, position)
+    JOIN dishes d ON d.name = v.dish_name
+    JOIN modifier_groups g ON g.dish_id = d.id AND g.name = v.group_name;
+
+INSERT INTO
+    modifier_option_ingredients (option_id, ingredient_id, qty_delta)
+SELECT o.id, i.id, v.qty_delta
+FROM (
+        VALUES ('Size', 'Large', 'Milk', 0.10),
+            ('Size', 'Large', 'Coffee Beans', 0.01),
+            ('Extras', 'Extra cheese', 'Cheese', 0.05),
+            ('Extras', 'Extra chicken', 'Chicken Breast', 0.10),
+            ('Remove', 'No cheese', 'Cheese', -0.05)
+    ) AS v(group_name, option_name, ingredient_name, qty_delta)
+    JOIN modifier_groups g ON g.name = v.group_name
+    JOIN modifier_options o ON o.group_id = g.id AND o.name = v.option_name
+    JOIN ingredients i ON i.name = v.ingredient_name;
+
+-- === COMBOS SEED DATA ===
+INSERT INTO
+    combos (name, description, price)
+VALUES (
+        'Lunch Set',
+        'Soup or salad, a main dish and a drink',
+        9000
+    );
+
+INSERT INTO
+    combo_slots (combo_id, name, position)
+SELECT c.id, v.name, v.position
+FROM (
+        VALUES ('Starter', 1),
+            ('Main', 2),
+            ('Drink', 3)
+    ) AS v(name, position)
+    JOIN combos c ON c.name = 'Lunch Set';
+
+INSERT INTO
+    combo_slot_choices (slot_id, dish_id, price_delta)
+SELECT s.id, d.id, v.price_delta
+FROM (
+        VALUES ('Starter', 'Tomato Soup', 0),
+            ('Starter', 'Greek Salad', 0),
+            ('Main', 'Chicken Curry', 0),
+            ('Main', 'Beef Steak', 1000),
+            ('Drink', 'Orange Juice', 0),
+            ('Drink', 'Cappuccino', 0)
+    ) AS v(slot_name, dish_name, price_delta)
+    JOIN combo_slots s ON s.name = v.slot_name
+    JOIN dishes d ON d.name = v.dish_name;
+
+-- === MENUS SEED DATA ===
+-- Основное меню весь день и ланч по будням 12:00–16:00 с ценами ниже обычных
+INSERT INTO
+    menus (
+        name,
+        description,
+        days_mask,
+        start_time,
+        end_time
+    )
+VALUES (
+        'Main Menu',
+        'All day, every day',
+        0,
+        NULL,
+        NULL
+    ),
+    (
+        'Weekday Lunch',
+        'Lunch prices on weekdays',
+        62,
+        '12:00',
+        '16:00'
+    );
+
+INSERT INTO
+    menu_dishes (menu_id, dish_id)
+SELECT m.id, d.id
+FROM menus m
+    CROSS JOIN dishes d
+WHERE m.name = 'Main Menu';
+
+INSERT INTO
+    menu_dishes (menu_id, dish_id, price)
+SELECT m.id, d.id, v.price
+FROM (
+        VALUES ('Tomato Soup', 1500),
+            ('Chicken Curry', 5500)
+    ) AS v(dish_name, price)
+    JOIN menus m ON m.name = 'Weekday Lunch'
+    JOIN dishes d ON d.name = v.dish_name;
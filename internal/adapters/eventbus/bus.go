@@ -0,0 +1,89 @@
+package eventbus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+// subscriberBuffer is how many events a slow client may lag behind before drops
+const subscriberBuffer = 64
+
+// Bus is an in-process publish/subscribe hub. Events are fanned out to every
+// subscriber without blocking the publisher.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[int]chan domain.Event
+	nextID int
+	closed bool
+	logger *logger.Logger
+}
+
+func New() *Bus {
+	return &Bus{
+		subs:   make(map[int]chan domain.Event),
+		logger: logger.New("EventBus"),
+	}
+}
+
+func (b *Bus) Publish(event domain.Event) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for id, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			b.logger.Warning("Subscriber #%d is too slow, dropping %s", id, event.Type)
+		}
+	}
+}
+
+func (b *Bus) Subscribe() (<-chan domain.Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	ch := make(chan domain.Event, subscriberBuffer)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subs[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			// Close мог уже закрыть канал
+			if _, ok := b.subs[id]; ok {
+				delete(b.subs, id)
+				close(ch)
+			}
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Close closes every subscription so that long-lived listeners (SSE streams)
+// return; later subscriptions get an already closed channel
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for id, ch := range b.subs {
+		delete(b.subs, id)
+		close(ch)
+	}
+}
@@ -21,6 +21,10 @@ func NewAnalyticsRepository(db *sql.DB) *AnalyticsRepository {
 func (r *AnalyticsRepository) GetSalesSummary(ctx context.Context, from, to time.Time) (*domain.SalesSummary, error) {
 	query := `
 		SELECT 
+			COALESCE(SUM(subtotal), 0) as subtotal,
+			COALESCE(SUM(discount), 0) as discounts,
+			COALESCE(SUM(tax), 0) as tax,
+			COALESCE(SUM(service_charge), 0) as service_charge,
 			COALESCE(SUM(total), 0) as total_revenue,
 			COUNT(*) as total_orders,
 			COALESCE(AVG(total), 0) as average_order_value
@@ -28,7 +32,11 @@ func (r *AnalyticsRepository) GetSalesSummary(ctx context.Context, from, to time
 		WHERE status = 'paid' AND created_at >= $1 AND created_at < $2`
 
 	summary := &domain.SalesSummary{}
-	err := r.db.QueryRowContext(ctx, query, from, to).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, from, to).Scan(
+		&summary.Subtotal,
+		&summary.Discounts,
+		&summary.Tax,
+		&summary.ServiceCharge,
 		&summary.TotalRevenue,
 		&summary.TotalOrders,
 		&summary.AverageOrderValue,
@@ -59,7 +67,7 @@ func (r *AnalyticsRepository) GetSalesByCategory(ctx context.Context, from, to t
 		GROUP BY c.id, c.name
 		ORDER BY revenue DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, from, to)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +96,8 @@ func (r *AnalyticsRepository) GetSalesByCategory(ctx context.Context, from, to t
 	return sales, rows.Err()
 }
 
+// GetPopularDishes counts dishes sold on their own and inside combos;
+// a combo dish brings in its share of the bundle price.
 func (r *AnalyticsRepository) GetPopularDishes(ctx context.Context, from, to time.Time, limit int) ([]domain.PopularDish, error) {
 	query := `
 		SELECT 
@@ -103,7 +113,7 @@ func (r *AnalyticsRepository) GetPopularDishes(ctx context.Context, from, to tim
 		ORDER BY revenue DESC
 		LIMIT $3`
 
-	rows, err := r.db.QueryContext(ctx, query, from, to, limit)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +146,7 @@ func (r *AnalyticsRepository) GetWaiterPerformance(ctx context.Context, from, to
 		GROUP BY u.id, u.username
 		ORDER BY revenue DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, from, to)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +164,134 @@ func (r *AnalyticsRepository) GetWaiterPerformance(ctx context.Context, from, to
 	return performance, rows.Err()
 }
 
+func (r *AnalyticsRepository) GetRevenueByPaymentMethod(ctx context.Context, from, to time.Time) ([]domain.PaymentMethodRevenue, error) {
+	query := `
+		SELECT 
+			method,
+			COUNT(*) as payment_count,
+			COALESCE(SUM(amount), 0) as revenue,
+			COALESCE(SUM(tip), 0) as tips,
+			COALESCE(SUM(service_charge), 0) as service_charges
+		FROM payments
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY method
+		ORDER BY revenue DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var methods []domain.PaymentMethodRevenue
+	var totalRevenue float64
+
+	for rows.Next() {
+		var m domain.PaymentMethodRevenue
+		if err := rows.Scan(&m.Method, &m.PaymentCount, &m.Revenue, &m.Tips, &m.ServiceCharges); err != nil {
+			return nil, err
+		}
+		totalRevenue += m.Revenue
+		methods = append(methods, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Calculate percentages
+	for i := range methods {
+		if totalRevenue > 0 {
+			methods[i].Percentage = (methods[i].Revenue / totalRevenue) * 100
+		}
+	}
+
+	return methods, nil
+}
+
+// GetTipsByWaiter считает чаевые по официанту заказа, а не по тому, кто провёл оплату
+func (r *AnalyticsRepository) GetTipsByWaiter(ctx context.Context, from, to time.Time) ([]domain.WaiterTips, error) {
+	query := `
+		SELECT 
+			u.id,
+			u.username,
+			COUNT(DISTINCT p.order_id) FILTER (WHERE p.tip > 0) as order_count,
+			COALESCE(SUM(p.tip), 0) as tips
+		FROM users u
+		LEFT JOIN orders o ON o.waiter_id = u.id
+		LEFT JOIN payments p ON p.order_id = o.id
+			AND p.created_at >= $1 AND p.created_at < $2
+		WHERE u.role = 'waiter' AND u.is_active = true
+		GROUP BY u.id, u.username
+		ORDER BY tips DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tips []domain.WaiterTips
+	for rows.Next() {
+		var t domain.WaiterTips
+		if err := rows.Scan(&t.WaiterID, &t.WaiterName, &t.OrderCount, &t.Tips); err != nil {
+			return nil, err
+		}
+		if t.OrderCount > 0 {
+			t.AvgTip = t.Tips / float64(t.OrderCount)
+		}
+		tips = append(tips, t)
+	}
+
+	return tips, rows.Err()
+}
+
+// GetDiscountCost группирует скидки оплаченных заказов: акции — по акции, comp — все вместе
+func (r *AnalyticsRepository) GetDiscountCost(ctx context.Context, from, to time.Time) ([]domain.DiscountCost, error) {
+	query := `
+		SELECT 
+			CASE WHEN d.source = 'comp' THEN NULL ELSE d.promotion_id END as promotion_id,
+			CASE WHEN d.source = 'comp' THEN 'Manager comps' ELSE MAX(d.name) END as name,
+			d.source,
+			COUNT(*) as times_applied,
+			COUNT(DISTINCT d.order_id) as order_count,
+			COALESCE(SUM(d.amount), 0) as amount
+		FROM order_discounts d
+		JOIN orders o ON o.id = d.order_id
+		WHERE o.status = 'paid' AND o.created_at >= $1 AND o.created_at < $2
+		GROUP BY d.source, CASE WHEN d.source = 'comp' THEN NULL ELSE d.promotion_id END
+		ORDER BY amount DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var costs []domain.DiscountCost
+	var totalAmount float64
+
+	for rows.Next() {
+		var c domain.DiscountCost
+		if err := rows.Scan(&c.PromotionID, &c.Name, &c.Source, &c.TimesApplied, &c.OrderCount, &c.Amount); err != nil {
+			return nil, err
+		}
+		totalAmount += c.Amount
+		costs = append(costs, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Calculate percentages
+	for i := range costs {
+		if totalAmount > 0 {
+			costs[i].Percentage = (costs[i].Amount / totalAmount) * 100
+		}
+	}
+
+	return costs, nil
+}
+
 func (r *AnalyticsRepository) GetOrderStats(
 	ctx context.Context,
 	from, to time.Time,
@@ -163,22 +301,25 @@ func (r *AnalyticsRepository) GetOrderStats(
 	query := `
         SELECT 
             COUNT(*) AS total_orders,
-            COALESCE(SUM(CASE WHEN status = 'paid' THEN 1 ELSE 0 END), 0) AS completed_orders
+            COALESCE(SUM(CASE WHEN status = 'paid' THEN 1 ELSE 0 END), 0) AS completed_orders,
+            -- средняя длительность оплаченного заказа, от открытия до закрытия
+            COALESCE(AVG(CASE WHEN status = 'paid' THEN EXTRACT(EPOCH FROM (updated_at - created_at)) / 60 END), 0) AS average_time
         FROM orders
         WHERE created_at >= $1 AND created_at < $2;
     `
 
 	stats := &domain.OrderStats{}
 
-	err := r.db.QueryRowContext(ctx, query, from, to).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, from, to).Scan(
 		&stats.TotalOrders,     // все заказы (любой статус)
 		&stats.CompletedOrders, // только paid
+		&stats.AverageTime,
 	)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
 
-	// Если нужны PeakHour и т.п. — можно потом отдельным запросом посчитать.
+	// Если нужен PeakHour — можно потом отдельным запросом посчитать.
 	// Пока просто 0, чтобы структура была валидной.
 	stats.PeakHour = 0
 
@@ -201,7 +342,7 @@ func (r *AnalyticsRepository) GetIngredientTurnover(ctx context.Context, from, t
 		GROUP BY i.id, i.name, i.unit, i.qty
 		ORDER BY used DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, from, to)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +376,7 @@ func (r *AnalyticsRepository) GetTableUtilization(ctx context.Context, from, to
 		GROUP BY t.id, t.name
 		ORDER BY times_used DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, from, to)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -273,7 +414,7 @@ func (r *AnalyticsRepository) GetHourlyRevenue(ctx context.Context, date time.Ti
 		GROUP BY EXTRACT(HOUR FROM created_at)
 		ORDER BY hour`
 
-	rows, err := r.db.QueryContext(ctx, query, startOfDay, endOfDay)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, startOfDay, endOfDay)
 	if err != nil {
 		return nil, err
 	}
@@ -302,7 +443,7 @@ func (r *AnalyticsRepository) GetDishAvailability(ctx context.Context) ([]domain
 					MIN(
 						CASE 
 							WHEN di.qty_per_dish > 0 
-							THEN (i.qty / di.qty_per_dish)
+							THEN ((i.qty - i.reserved_qty) / di.qty_per_dish)
 							ELSE NULL
 						END
 					)
@@ -317,7 +458,7 @@ func (r *AnalyticsRepository) GetDishAvailability(ctx context.Context) ([]domain
 		ORDER BY d.id;
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -334,3 +475,226 @@ func (r *AnalyticsRepository) GetDishAvailability(ctx context.Context) ([]domain
 
 	return result, rows.Err()
 }
+
+// GetWasteByReason returns the number and cost of waste entries per reason
+func (r *AnalyticsRepository) GetWasteByReason(ctx context.Context, from, to time.Time) ([]domain.WasteByReason, error) {
+	query := `
+		SELECT reason, COUNT(*), COALESCE(SUM(cost), 0)
+		FROM waste_entries
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY reason
+		ORDER BY SUM(cost) DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.WasteByReason
+	for rows.Next() {
+		var w domain.WasteByReason
+		if err := rows.Scan(&w.Reason, &w.Entries, &w.Cost); err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+
+	return result, rows.Err()
+}
+
+// GetWasteByIngredient returns what was written off per ingredient; wasted
+// dishes count through the ingredients of their recipe
+func (r *AnalyticsRepository) GetWasteByIngredient(ctx context.Context, from, to time.Time) ([]domain.WasteByIngredient, error) {
+	query := `
+		SELECT i.id, i.name, i.unit,
+		       COALESCE(SUM(wi.qty), 0),
+		       COALESCE(ROUND(SUM(wi.qty * wi.unit_cost), 2), 0)
+		FROM waste_items wi
+		JOIN waste_entries w ON w.id = wi.waste_id
+		JOIN ingredients i ON i.id = wi.ingredient_id
+		WHERE w.created_at >= $1 AND w.created_at < $2
+		GROUP BY i.id, i.name, i.unit
+		ORDER BY 5 DESC, i.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.WasteByIngredient
+	for rows.Next() {
+		var w domain.WasteByIngredient
+		if err := rows.Scan(&w.IngredientID, &w.IngredientName, &w.Unit, &w.Qty, &w.Cost); err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+
+	return result, rows.Err()
+}
+
+// GetWasteByDay returns the cost of waste per day of the period
+func (r *AnalyticsRepository) GetWasteByDay(ctx context.Context, from, to time.Time) ([]domain.WasteByDay, error) {
+	query := `
+		SELECT TO_CHAR(DATE(created_at), 'YYYY-MM-DD'), COALESCE(SUM(cost), 0)
+		FROM waste_entries
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY DATE(created_at)
+		ORDER BY DATE(created_at)`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.WasteByDay
+	for rows.Next() {
+		var w domain.WasteByDay
+		if err := rows.Scan(&w.Date, &w.Cost); err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+
+	return result, rows.Err()
+}
+
+// GetSpendBySupplier returns what was paid per supplier and currency; free-text
+// suppliers are grouped by name
+func (r *AnalyticsRepository) GetSpendBySupplier(ctx context.Context, from, to time.Time) ([]domain.SupplierSpend, error) {
+	query := `
+		SELECT s.supplier_id, COALESCE(sp.name, s.supplier_name), s.currency,
+		       COUNT(*), ROUND(SUM(s.qty * s.unit_price), 2) AS total
+		FROM supplies s
+		LEFT JOIN suppliers sp ON sp.id = s.supplier_id
+		WHERE s.unit_price IS NOT NULL
+		  AND s.created_at >= $1 AND s.created_at < $2
+		GROUP BY s.supplier_id, COALESCE(sp.name, s.supplier_name), s.currency
+		ORDER BY s.currency, total DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.SupplierSpend
+	for rows.Next() {
+		var s domain.SupplierSpend
+		if err := rows.Scan(&s.SupplierID, &s.SupplierName, &s.Currency, &s.Supplies, &s.Total); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *AnalyticsRepository) GetSpendByIngredient(ctx context.Context, from, to time.Time) ([]domain.IngredientSpend, error) {
+	query := `
+		SELECT i.id, i.name, i.unit, s.currency,
+		       SUM(s.qty), ROUND(SUM(s.qty * s.unit_price), 2) AS total
+		FROM supplies s
+		JOIN ingredients i ON i.id = s.ingredient_id
+		WHERE s.unit_price IS NOT NULL
+		  AND s.created_at >= $1 AND s.created_at < $2
+		GROUP BY i.id, i.name, i.unit, s.currency
+		ORDER BY s.currency, total DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.IngredientSpend
+	for rows.Next() {
+		var s domain.IngredientSpend
+		if err := rows.Scan(&s.IngredientID, &s.IngredientName, &s.Unit, &s.Currency, &s.Qty, &s.Total); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+
+	return result, rows.Err()
+}
+
+// GetSpendByPeriod returns spend per day, week or month of the range
+func (r *AnalyticsRepository) GetSpendByPeriod(ctx context.Context, from, to time.Time, period domain.SpendPeriod) ([]domain.PeriodSpend, error) {
+	query := `
+		SELECT TO_CHAR(DATE_TRUNC($3, s.created_at), 'YYYY-MM-DD') AS period, s.currency,
+		       ROUND(SUM(s.qty * s.unit_price), 2)
+		FROM supplies s
+		WHERE s.unit_price IS NOT NULL
+		  AND s.created_at >= $1 AND s.created_at < $2
+		GROUP BY period, s.currency
+		ORDER BY period, s.currency`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to, string(period))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.PeriodSpend
+	for rows.Next() {
+		var s domain.PeriodSpend
+		if err := rows.Scan(&s.Period, &s.Currency, &s.Total); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *AnalyticsRepository) CountUnpricedSupplies(ctx context.Context, from, to time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM supplies
+		WHERE unit_price IS NULL AND created_at >= $1 AND created_at < $2`
+
+	var count int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, from, to).Scan(&count)
+	return count, err
+}
+
+// GetPriceHistory returns the priced supplies of an ingredient with the
+// previous price of the same supplier in the same currency
+func (r *AnalyticsRepository) GetPriceHistory(ctx context.Context, ingredientID int, supplierID *int) ([]domain.PricePoint, error) {
+	query := `
+		SELECT s.id, s.supplier_id, COALESCE(sp.name, s.supplier_name), s.currency, s.unit_price,
+		       LAG(s.unit_price) OVER (
+		           PARTITION BY COALESCE(s.supplier_id::text, s.supplier_name), s.currency
+		           ORDER BY s.created_at, s.id
+		       ),
+		       s.invoice_number, s.created_at
+		FROM supplies s
+		LEFT JOIN suppliers sp ON sp.id = s.supplier_id
+		WHERE s.ingredient_id = $1
+		  AND s.unit_price IS NOT NULL
+		  AND ($2::int IS NULL OR s.supplier_id = $2)
+		ORDER BY COALESCE(sp.name, s.supplier_name), s.currency, s.created_at, s.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, ingredientID, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.PricePoint
+	for rows.Next() {
+		var p domain.PricePoint
+		if err := rows.Scan(
+			&p.SupplyID, &p.SupplierID, &p.SupplierName, &p.Currency, &p.UnitPrice,
+			&p.PreviousPrice, &p.InvoiceNumber, &p.ReceivedAt,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+
+	return result, rows.Err()
+}
@@ -17,9 +17,9 @@ func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 }
 
 func (r *CategoryRepository) GetAll(ctx context.Context) ([]domain.Category, error) {
-	query := `SELECT id, name FROM categories ORDER BY name`
+	query := `SELECT id, name, station, vat_rate FROM categories ORDER BY name`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +28,7 @@ func (r *CategoryRepository) GetAll(ctx context.Context) ([]domain.Category, err
 	var categories []domain.Category
 	for rows.Next() {
 		var category domain.Category
-		if err := rows.Scan(&category.ID, &category.Name); err != nil {
+		if err := rows.Scan(&category.ID, &category.Name, &category.Station, &category.VatRate); err != nil {
 			return nil, err
 		}
 		categories = append(categories, category)
@@ -38,10 +38,12 @@ func (r *CategoryRepository) GetAll(ctx context.Context) ([]domain.Category, err
 }
 
 func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*domain.Category, error) {
-	query := `SELECT id, name FROM categories WHERE id = $1`
+	query := `SELECT id, name, station, vat_rate FROM categories WHERE id = $1`
 
 	category := &domain.Category{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&category.ID, &category.Name)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&category.ID, &category.Name, &category.Station, &category.VatRate,
+	)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -50,18 +52,20 @@ func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*domain.Categ
 }
 
 func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) error {
-	query := `INSERT INTO categories (name) VALUES ($1) RETURNING id`
-	return r.db.QueryRowContext(ctx, query, category.Name).Scan(&category.ID)
+	query := `INSERT INTO categories (name, station, vat_rate) VALUES ($1, $2, $3) RETURNING id`
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		category.Name, category.Station, category.VatRate,
+	).Scan(&category.ID)
 }
 
 func (r *CategoryRepository) Update(ctx context.Context, category *domain.Category) error {
-	query := `UPDATE categories SET name = $1 WHERE id = $2`
-	_, err := r.db.ExecContext(ctx, query, category.Name, category.ID)
+	query := `UPDATE categories SET name = $1, station = $2, vat_rate = $3 WHERE id = $4`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, category.Name, category.Station, category.VatRate, category.ID)
 	return err
 }
 
 func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM categories WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	return err
 }
@@ -0,0 +1,185 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type ComboRepository struct {
+	db *sql.DB
+}
+
+func NewComboRepository(db *sql.DB) *ComboRepository {
+	return &ComboRepository{db: db}
+}
+
+const comboColumns = `id, name, description, price, photo_url, is_active, created_at`
+
+func scanCombo(row rowScanner) (*domain.Combo, error) {
+	c := &domain.Combo{}
+	err := row.Scan(&c.ID, &c.Name, &c.Description, &c.Price, &c.PhotoURL, &c.IsActive, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *ComboRepository) GetAll(ctx context.Context, activeOnly bool) ([]domain.Combo, error) {
+	query := `SELECT ` + comboColumns + ` FROM combos`
+	if activeOnly {
+		query += ` WHERE is_active = true`
+	}
+	query += ` ORDER BY name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var combos []domain.Combo
+	for rows.Next() {
+		c, err := scanCombo(rows)
+		if err != nil {
+			return nil, err
+		}
+		combos = append(combos, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range combos {
+		combos[i].Slots, err = r.getSlots(ctx, combos[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return combos, nil
+}
+
+func (r *ComboRepository) GetByID(ctx context.Context, id int) (*domain.Combo, error) {
+	query := `SELECT ` + comboColumns + ` FROM combos WHERE id = $1`
+
+	c, err := scanCombo(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.Slots, err = r.getSlots(ctx, c.ID)
+	return c, err
+}
+
+// getSlots loads the slots of a combo with the dishes allowed in each
+func (r *ComboRepository) getSlots(ctx context.Context, comboID int) ([]domain.ComboSlot, error) {
+	query := `
+		SELECT s.id, s.combo_id, s.name, s.position,
+		       ch.dish_id, ch.price_delta, d.name, d.price, d.is_active
+		FROM combo_slots s
+		JOIN combo_slot_choices ch ON ch.slot_id = s.id
+		JOIN dishes d ON d.id = ch.dish_id
+		WHERE s.combo_id = $1
+		ORDER BY s.position, s.id, d.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, comboID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slots []domain.ComboSlot
+	for rows.Next() {
+		var slot domain.ComboSlot
+		choice := domain.ComboChoice{Dish: &domain.Dish{}}
+		if err := rows.Scan(
+			&slot.ID, &slot.ComboID, &slot.Name, &slot.Position,
+			&choice.DishID, &choice.PriceDelta, &choice.Dish.Name, &choice.Dish.Price, &choice.Dish.IsActive,
+		); err != nil {
+			return nil, err
+		}
+		choice.SlotID = slot.ID
+		choice.Dish.ID = choice.DishID
+
+		if n := len(slots); n == 0 || slots[n-1].ID != slot.ID {
+			slots = append(slots, slot)
+		}
+		last := &slots[len(slots)-1]
+		last.Choices = append(last.Choices, choice)
+	}
+
+	return slots, rows.Err()
+}
+
+func (r *ComboRepository) Create(ctx context.Context, c *domain.Combo) error {
+	query := `
+		INSERT INTO combos (name, description, price, photo_url, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		c.Name, c.Description, c.Price, c.PhotoURL, c.IsActive,
+	).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return r.insertSlots(ctx, c)
+}
+
+// Update replaces the combo with its slots; orders keep their own copy of the combo
+func (r *ComboRepository) Update(ctx context.Context, c *domain.Combo) error {
+	query := `
+		UPDATE combos
+		SET name = $1, description = $2, price = $3, photo_url = $4, is_active = $5
+		WHERE id = $6`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query,
+		c.Name, c.Description, c.Price, c.PhotoURL, c.IsActive, c.ID,
+	); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM combo_slots WHERE combo_id = $1`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, c.ID); err != nil {
+		return err
+	}
+	return r.insertSlots(ctx, c)
+}
+
+func (r *ComboRepository) insertSlots(ctx context.Context, c *domain.Combo) error {
+	slotQuery := `
+		INSERT INTO combo_slots (combo_id, name, position)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+	choiceQuery := `
+		INSERT INTO combo_slot_choices (slot_id, dish_id, price_delta)
+		VALUES ($1, $2, $3)`
+
+	for i := range c.Slots {
+		slot := &c.Slots[i]
+		slot.ComboID = c.ID
+		if err := conn(ctx, r.db).QueryRowContext(ctx, slotQuery, c.ID, slot.Name, slot.Position).Scan(&slot.ID); err != nil {
+			return err
+		}
+
+		for j := range slot.Choices {
+			choice := &slot.Choices[j]
+			choice.SlotID = slot.ID
+			if _, err := conn(ctx, r.db).ExecContext(ctx, choiceQuery, slot.ID, choice.DishID, choice.PriceDelta); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Delete takes the combo off the menu; orders that already have it are not touched
+func (r *ComboRepository) Delete(ctx context.Context, id int) error {
+	query := `UPDATE combos SET is_active = false WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
@@ -18,8 +18,8 @@ func NewDishRepository(db *sql.DB) *DishRepository {
 
 func (r *DishRepository) GetAll(ctx context.Context, activeOnly bool) ([]domain.Dish, error) {
 	query := `
-		SELECT d.id, d.category_id, d.name, d.description, d.price, d.photo_url, d.is_active,
-		       c.id, c.name
+		SELECT d.id, d.category_id, d.name, d.description, d.price, d.photo_url, d.is_active, d.vat_rate,
+		       d.sold_out, c.id, c.name, c.vat_rate
 		FROM dishes d
 		LEFT JOIN categories c ON d.category_id = c.id`
 
@@ -28,7 +28,7 @@ func (r *DishRepository) GetAll(ctx context.Context, activeOnly bool) ([]domain.
 	}
 	query += ` ORDER BY d.name`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -41,8 +41,8 @@ func (r *DishRepository) GetAll(ctx context.Context, activeOnly bool) ([]domain.
 
 		if err := rows.Scan(
 			&dish.ID, &dish.CategoryID, &dish.Name, &dish.Description,
-			&dish.Price, &dish.PhotoURL, &dish.IsActive,
-			&dish.Category.ID, &dish.Category.Name,
+			&dish.Price, &dish.PhotoURL, &dish.IsActive, &dish.VatRate,
+			&dish.SoldOut, &dish.Category.ID, &dish.Category.Name, &dish.Category.VatRate,
 		); err != nil {
 			return nil, err
 		}
@@ -54,17 +54,17 @@ func (r *DishRepository) GetAll(ctx context.Context, activeOnly bool) ([]domain.
 
 func (r *DishRepository) GetByID(ctx context.Context, id int) (*domain.Dish, error) {
 	query := `
-		SELECT d.id, d.category_id, d.name, d.description, d.price, d.photo_url, d.is_active,
-		       c.id, c.name
+		SELECT d.id, d.category_id, d.name, d.description, d.price, d.photo_url, d.is_active, d.vat_rate,
+		       d.sold_out, c.id, c.name, c.vat_rate
 		FROM dishes d
 		LEFT JOIN categories c ON d.category_id = c.id
 		WHERE d.id = $1`
 
 	dish := &domain.Dish{Category: &domain.Category{}}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&dish.ID, &dish.CategoryID, &dish.Name, &dish.Description,
-		&dish.Price, &dish.PhotoURL, &dish.IsActive,
-		&dish.Category.ID, &dish.Category.Name,
+		&dish.Price, &dish.PhotoURL, &dish.IsActive, &dish.VatRate,
+		&dish.SoldOut, &dish.Category.ID, &dish.Category.Name, &dish.Category.VatRate,
 	)
 
 	if err == sql.ErrNoRows {
@@ -75,11 +75,11 @@ func (r *DishRepository) GetByID(ctx context.Context, id int) (*domain.Dish, err
 
 func (r *DishRepository) GetByCategoryID(ctx context.Context, categoryID int) ([]domain.Dish, error) {
 	query := `
-		SELECT id, category_id, name, description, price, photo_url, is_active
+		SELECT id, category_id, name, description, price, photo_url, is_active, vat_rate, sold_out
 		FROM dishes WHERE category_id = $1 AND is_active = true
 		ORDER BY name`
 
-	rows, err := r.db.QueryContext(ctx, query, categoryID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, categoryID)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +90,7 @@ func (r *DishRepository) GetByCategoryID(ctx context.Context, categoryID int) ([
 		var dish domain.Dish
 		if err := rows.Scan(
 			&dish.ID, &dish.CategoryID, &dish.Name, &dish.Description,
-			&dish.Price, &dish.PhotoURL, &dish.IsActive,
+			&dish.Price, &dish.PhotoURL, &dish.IsActive, &dish.VatRate, &dish.SoldOut,
 		); err != nil {
 			return nil, err
 		}
@@ -102,35 +102,37 @@ func (r *DishRepository) GetByCategoryID(ctx context.Context, categoryID int) ([
 
 func (r *DishRepository) Create(ctx context.Context, dish *domain.Dish) error {
 	query := `
-		INSERT INTO dishes (category_id, name, description, price, photo_url, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO dishes (category_id, name, description, price, photo_url, is_active, vat_rate)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id`
 
-	return r.db.QueryRowContext(ctx, query,
-		dish.CategoryID, dish.Name, dish.Description, dish.Price, dish.PhotoURL, dish.IsActive,
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		dish.CategoryID, dish.Name, dish.Description, dish.Price, dish.PhotoURL, dish.IsActive, dish.VatRate,
 	).Scan(&dish.ID)
 }
 
 func (r *DishRepository) Update(ctx context.Context, dish *domain.Dish) error {
 	query := `
 		UPDATE dishes 
-		SET category_id = $1, name = $2, description = $3, price = $4, photo_url = $5, is_active = $6
-		WHERE id = $7`
+		SET category_id = $1, name = $2, description = $3, price = $4, photo_url = $5, is_active = $6,
+			vat_rate = $7
+		WHERE id = $8`
 
-	_, err := r.db.ExecContext(ctx, query,
-		dish.CategoryID, dish.Name, dish.Description, dish.Price, dish.PhotoURL, dish.IsActive, dish.ID,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		dish.CategoryID, dish.Name, dish.Description, dish.Price, dish.PhotoURL, dish.IsActive, dish.VatRate,
+		dish.ID,
 	)
 	return err
 }
 func (r *DishRepository) Delete(ctx context.Context, id int) error {
 	// было:
 	// query := `DELETE FROM dishes WHERE id = $1`
-	// _, err := r.db.ExecContext(ctx, query, id)
+	// _, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	// return err
 
 	query := `UPDATE dishes SET is_active = false WHERE id = $1`
 
-	res, err := r.db.ExecContext(ctx, query, id)
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -143,15 +145,76 @@ func (r *DishRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// SetSoldOut marks the dish as 86'd by hand or puts it back on sale
+func (r *DishRepository) SetSoldOut(ctx context.Context, id int, soldOut bool) error {
+	query := `UPDATE dishes SET sold_out = $1 WHERE id = $2`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, soldOut, id)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return domain.ErrDishNotFound
+	}
+	return nil
+}
+
+// GetPortionsLeft returns how many portions of each dish with a recipe
+// the free stock (qty - reserved) is enough for. Made-to-order prep items
+// are resolved into their components, so the stock of those counts.
+func (r *DishRepository) GetPortionsLeft(ctx context.Context) (map[int]int, error) {
+	query := `
+		WITH RECURSIVE recipe (dish_id, ingredient_id, qty, depth) AS (
+			SELECT dish_id, ingredient_id, qty_per_dish::NUMERIC, 0
+			FROM dish_ingredients
+			UNION ALL
+			SELECT r.dish_id, pi.ingredient_id, r.qty * pi.qty_per_batch / p.batch_yield, r.depth + 1
+			FROM recipe r
+			JOIN prep_items p ON p.ingredient_id = r.ingredient_id AND p.made_to_order
+			JOIN prep_ingredients pi ON pi.prep_id = p.ingredient_id
+			WHERE r.depth < 8
+		),
+		needs AS (
+			SELECT r.dish_id, r.ingredient_id, SUM(r.qty) AS qty
+			FROM recipe r
+			LEFT JOIN prep_items p ON p.ingredient_id = r.ingredient_id AND p.made_to_order
+			WHERE p.ingredient_id IS NULL OR r.depth = 8
+			GROUP BY r.dish_id, r.ingredient_id
+		)
+		SELECT n.dish_id,
+		       GREATEST(FLOOR(MIN((i.qty - i.reserved_qty) / n.qty)), 0)::INT
+		FROM needs n
+		JOIN ingredients i ON i.id = n.ingredient_id
+		WHERE n.qty > 0
+		GROUP BY n.dish_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	portions := make(map[int]int)
+	for rows.Next() {
+		var dishID, left int
+		if err := rows.Scan(&dishID, &left); err != nil {
+			return nil, err
+		}
+		portions[dishID] = left
+	}
+
+	return portions, rows.Err()
+}
+
 func (r *DishRepository) GetIngredients(ctx context.Context, dishID int) ([]domain.DishIngredient, error) {
 	query := `
 		SELECT di.dish_id, di.ingredient_id, di.qty_per_dish,
-		       i.id, i.name, i.unit, i.qty, i.min_qty
+		       i.id, i.name, i.unit, i.qty, i.reserved_qty, i.min_qty, i.unit_cost
 		FROM dish_ingredients di
 		JOIN ingredients i ON di.ingredient_id = i.id
 		WHERE di.dish_id = $1`
 
-	rows, err := r.db.QueryContext(ctx, query, dishID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, dishID)
 	if err != nil {
 		return nil, err
 	}
@@ -165,7 +228,7 @@ func (r *DishRepository) GetIngredients(ctx context.Context, dishID int) ([]doma
 		if err := rows.Scan(
 			&di.DishID, &di.IngredientID, &di.QtyPerDish,
 			&di.Ingredient.ID, &di.Ingredient.Name, &di.Ingredient.Unit,
-			&di.Ingredient.Qty, &di.Ingredient.MinQty,
+			&di.Ingredient.Qty, &di.Ingredient.ReservedQty, &di.Ingredient.MinQty, &di.Ingredient.UnitCost,
 		); err != nil {
 			return nil, err
 		}
@@ -180,13 +243,13 @@ func (r *DishRepository) AddIngredient(ctx context.Context, di *domain.DishIngre
 		INSERT INTO dish_ingredients (dish_id, ingredient_id, qty_per_dish)
 		VALUES ($1, $2, $3)`
 
-	_, err := r.db.ExecContext(ctx, query, di.DishID, di.IngredientID, di.QtyPerDish)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, di.DishID, di.IngredientID, di.QtyPerDish)
 	return err
 }
 
 func (r *DishRepository) RemoveIngredient(ctx context.Context, dishID, ingredientID int) error {
 	query := `DELETE FROM dish_ingredients WHERE dish_id = $1 AND ingredient_id = $2`
-	_, err := r.db.ExecContext(ctx, query, dishID, ingredientID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, dishID, ingredientID)
 	return err
 }
 
@@ -196,6 +259,6 @@ func (r *DishRepository) UpdateIngredient(ctx context.Context, di *domain.DishIn
 		SET qty_per_dish = $1
 		WHERE dish_id = $2 AND ingredient_id = $3`
 
-	_, err := r.db.ExecContext(ctx, query, di.QtyPerDish, di.DishID, di.IngredientID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, di.QtyPerDish, di.DishID, di.IngredientID)
 	return err
 }
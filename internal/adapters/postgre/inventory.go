@@ -3,8 +3,10 @@ package postgre
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type IngredientRepository struct {
@@ -16,9 +18,9 @@ func NewIngredientRepository(db *sql.DB) *IngredientRepository {
 }
 
 func (r *IngredientRepository) GetAll(ctx context.Context) ([]domain.Ingredient, error) {
-	query := `SELECT id, name, unit, qty, min_qty FROM ingredients ORDER BY name`
+	query := `SELECT id, name, unit, qty, reserved_qty, min_qty, unit_cost FROM ingredients ORDER BY name`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +29,7 @@ func (r *IngredientRepository) GetAll(ctx context.Context) ([]domain.Ingredient,
 	var ingredients []domain.Ingredient
 	for rows.Next() {
 		var ing domain.Ingredient
-		if err := rows.Scan(&ing.ID, &ing.Name, &ing.Unit, &ing.Qty, &ing.MinQty); err != nil {
+		if err := rows.Scan(&ing.ID, &ing.Name, &ing.Unit, &ing.Qty, &ing.ReservedQty, &ing.MinQty, &ing.UnitCost); err != nil {
 			return nil, err
 		}
 		ingredients = append(ingredients, ing)
@@ -37,10 +39,23 @@ func (r *IngredientRepository) GetAll(ctx context.Context) ([]domain.Ingredient,
 }
 
 func (r *IngredientRepository) GetByID(ctx context.Context, id int) (*domain.Ingredient, error) {
-	query := `SELECT id, name, unit, qty, min_qty FROM ingredients WHERE id = $1`
+	query := `SELECT id, name, unit, qty, reserved_qty, min_qty, unit_cost FROM ingredients WHERE id = $1`
 
 	ing := &domain.Ingredient{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&ing.ID, &ing.Name, &ing.Unit, &ing.Qty, &ing.MinQty)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&ing.ID, &ing.Name, &ing.Unit, &ing.Qty, &ing.ReservedQty, &ing.MinQty, &ing.UnitCost)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return ing, err
+}
+
+// GetByIDForUpdate locks the ingredient row until the surrounding transaction ends
+func (r *IngredientRepository) GetByIDForUpdate(ctx context.Context, id int) (*domain.Ingredient, error) {
+	query := `SELECT id, name, unit, qty, reserved_qty, min_qty, unit_cost FROM ingredients WHERE id = $1 FOR UPDATE`
+
+	ing := &domain.Ingredient{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&ing.ID, &ing.Name, &ing.Unit, &ing.Qty, &ing.ReservedQty, &ing.MinQty, &ing.UnitCost)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -49,9 +64,9 @@ func (r *IngredientRepository) GetByID(ctx context.Context, id int) (*domain.Ing
 }
 
 func (r *IngredientRepository) GetLowStock(ctx context.Context) ([]domain.Ingredient, error) {
-	query := `SELECT id, name, unit, qty, min_qty FROM ingredients WHERE qty <= min_qty ORDER BY name`
+	query := `SELECT id, name, unit, qty, reserved_qty, min_qty, unit_cost FROM ingredients WHERE qty <= min_qty ORDER BY name`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +75,7 @@ func (r *IngredientRepository) GetLowStock(ctx context.Context) ([]domain.Ingred
 	var ingredients []domain.Ingredient
 	for rows.Next() {
 		var ing domain.Ingredient
-		if err := rows.Scan(&ing.ID, &ing.Name, &ing.Unit, &ing.Qty, &ing.MinQty); err != nil {
+		if err := rows.Scan(&ing.ID, &ing.Name, &ing.Unit, &ing.Qty, &ing.ReservedQty, &ing.MinQty, &ing.UnitCost); err != nil {
 			return nil, err
 		}
 		ingredients = append(ingredients, ing)
@@ -71,31 +86,105 @@ func (r *IngredientRepository) GetLowStock(ctx context.Context) ([]domain.Ingred
 
 func (r *IngredientRepository) Create(ctx context.Context, ing *domain.Ingredient) error {
 	query := `
-		INSERT INTO ingredients (name, unit, qty, min_qty)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO ingredients (name, unit, qty, min_qty, unit_cost)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id`
 
-	return r.db.QueryRowContext(ctx, query, ing.Name, ing.Unit, ing.Qty, ing.MinQty).Scan(&ing.ID)
+	return conn(ctx, r.db).QueryRowContext(ctx, query, ing.Name, ing.Unit, ing.Qty, ing.MinQty, ing.UnitCost).Scan(&ing.ID)
 }
 
+// Update changes the card of the ingredient; qty moves only through ApplyMovement
 func (r *IngredientRepository) Update(ctx context.Context, ing *domain.Ingredient) error {
 	query := `
 		UPDATE ingredients 
-		SET name = $1, unit = $2, qty = $3, min_qty = $4
+		SET name = $1, unit = $2, min_qty = $3, unit_cost = $4
 		WHERE id = $5`
 
-	_, err := r.db.ExecContext(ctx, query, ing.Name, ing.Unit, ing.Qty, ing.MinQty, ing.ID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, ing.Name, ing.Unit, ing.MinQty, ing.UnitCost, ing.ID)
+	return err
+}
+
+func (r *IngredientRepository) GetReservations(ctx context.Context, orderID int) (map[int]float64, error) {
+	query := `SELECT ingredient_id, qty FROM order_reservations WHERE order_id = $1`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	held := make(map[int]float64)
+	for rows.Next() {
+		var id int
+		var qty float64
+		if err := rows.Scan(&id, &qty); err != nil {
+			return nil, err
+		}
+		held[id] = qty
+	}
+
+	return held, rows.Err()
+}
+
+// Reserve shifts reserved_qty and the order's reservation row together; the
+// CHECKs on both reject releasing more than is held. A fully released row is removed.
+func (r *IngredientRepository) Reserve(ctx context.Context, orderID, ingredientID int, qty float64) error {
+	query := `
+		WITH reserved AS (
+			UPDATE ingredients SET reserved_qty = reserved_qty + ROUND($3::NUMERIC, 2)
+			WHERE id = $2
+			RETURNING id
+		)
+		INSERT INTO order_reservations (order_id, ingredient_id, qty)
+		SELECT $1, id, ROUND($3::NUMERIC, 2) FROM reserved
+		ON CONFLICT (order_id, ingredient_id) DO UPDATE
+		SET qty = order_reservations.qty + EXCLUDED.qty`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, orderID, ingredientID, qty)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return domain.ErrIngredientNotFound
+	}
+
+	cleanup := `DELETE FROM order_reservations WHERE order_id = $1 AND ingredient_id = $2 AND qty = 0`
+	_, err = conn(ctx, r.db).ExecContext(ctx, cleanup, orderID, ingredientID)
 	return err
 }
 
-func (r *IngredientRepository) UpdateQuantity(ctx context.Context, id int, qty float64) error {
-	query := `UPDATE ingredients SET qty = qty + $1 WHERE id = $2`
-	_, err := r.db.ExecContext(ctx, query, qty, id)
+func (r *IngredientRepository) MoveReservations(ctx context.Context, fromOrderID, toOrderID int) error {
+	query := `
+		WITH moved AS (
+			DELETE FROM order_reservations WHERE order_id = $1
+			RETURNING ingredient_id, qty
+		)
+		INSERT INTO order_reservations (order_id, ingredient_id, qty)
+		SELECT $2, ingredient_id, qty FROM moved
+		ON CONFLICT (order_id, ingredient_id) DO UPDATE
+		SET qty = order_reservations.qty + EXCLUDED.qty`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, fromOrderID, toOrderID)
 	return err
 }
 
+// SetUnitCost overwrites the average unit cost (prep items are costed by their components)
+func (r *IngredientRepository) SetUnitCost(ctx context.Context, id int, unitCost float64) error {
+	query := `UPDATE ingredients SET unit_cost = $1 WHERE id = $2`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, unitCost, id)
+	return err
+}
+
+// Delete removes an ingredient that has no history. The stock ledger, supplies,
+// waste and stocktake rows reference it with RESTRICT, so an ingredient that
+// has any of them yields ErrIngredientInUse.
 func (r *IngredientRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM ingredients WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+		return domain.ErrIngredientInUse
+	}
 	return err
 }
@@ -0,0 +1,159 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type MenuRepository struct {
+	db *sql.DB
+}
+
+func NewMenuRepository(db *sql.DB) *MenuRepository {
+	return &MenuRepository{db: db}
+}
+
+const menuColumns = `id, name, description, days_mask, start_time, end_time, starts_at, ends_at, is_active, created_at`
+
+func scanMenu(row rowScanner) (*domain.Menu, error) {
+	m := &domain.Menu{}
+	var mask int
+	err := row.Scan(
+		&m.ID, &m.Name, &m.Description, &mask, &m.StartTime, &m.EndTime,
+		&m.StartsAt, &m.EndsAt, &m.IsActive, &m.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	m.SetDaysMask(mask)
+	return m, nil
+}
+
+// GetAll returns menus with their dishes; with activeOnly, switched off menus are left out
+func (r *MenuRepository) GetAll(ctx context.Context, activeOnly bool) ([]domain.Menu, error) {
+	query := `SELECT ` + menuColumns + ` FROM menus`
+	if activeOnly {
+		query += ` WHERE is_active = true`
+	}
+	query += ` ORDER BY name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var menus []domain.Menu
+	for rows.Next() {
+		m, err := scanMenu(rows)
+		if err != nil {
+			return nil, err
+		}
+		menus = append(menus, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range menus {
+		menus[i].Dishes, err = r.getDishes(ctx, menus[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return menus, nil
+}
+
+func (r *MenuRepository) GetByID(ctx context.Context, id int) (*domain.Menu, error) {
+	query := `SELECT ` + menuColumns + ` FROM menus WHERE id = $1`
+
+	m, err := scanMenu(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.Dishes, err = r.getDishes(ctx, m.ID)
+	return m, err
+}
+
+func (r *MenuRepository) getDishes(ctx context.Context, menuID int) ([]domain.MenuDish, error) {
+	query := `
+		SELECT md.menu_id, md.dish_id, md.price,
+		       d.id, d.category_id, d.name, d.price, d.is_active, d.sold_out
+		FROM menu_dishes md
+		JOIN dishes d ON d.id = md.dish_id
+		WHERE md.menu_id = $1
+		ORDER BY d.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, menuID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dishes []domain.MenuDish
+	for rows.Next() {
+		md := domain.MenuDish{Dish: &domain.Dish{}}
+		if err := rows.Scan(
+			&md.MenuID, &md.DishID, &md.Price,
+			&md.Dish.ID, &md.Dish.CategoryID, &md.Dish.Name, &md.Dish.Price, &md.Dish.IsActive, &md.Dish.SoldOut,
+		); err != nil {
+			return nil, err
+		}
+		dishes = append(dishes, md)
+	}
+
+	return dishes, rows.Err()
+}
+
+func (r *MenuRepository) Create(ctx context.Context, m *domain.Menu) error {
+	query := `
+		INSERT INTO menus (name, description, days_mask, start_time, end_time, starts_at, ends_at, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		m.Name, m.Description, m.DaysMask(), m.StartTime, m.EndTime, m.StartsAt, m.EndsAt, m.IsActive,
+	).Scan(&m.ID, &m.CreatedAt)
+}
+
+func (r *MenuRepository) Update(ctx context.Context, m *domain.Menu) error {
+	query := `
+		UPDATE menus
+		SET name = $1, description = $2, days_mask = $3, start_time = $4, end_time = $5,
+		    starts_at = $6, ends_at = $7, is_active = $8
+		WHERE id = $9`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		m.Name, m.Description, m.DaysMask(), m.StartTime, m.EndTime, m.StartsAt, m.EndsAt, m.IsActive, m.ID,
+	)
+	return err
+}
+
+func (r *MenuRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM menus WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// SetDish puts the dish on the menu or changes its price there
+func (r *MenuRepository) SetDish(ctx context.Context, md *domain.MenuDish) error {
+	query := `
+		INSERT INTO menu_dishes (menu_id, dish_id, price)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (menu_id, dish_id) DO UPDATE SET price = EXCLUDED.price`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, md.MenuID, md.DishID, md.Price)
+	return err
+}
+
+func (r *MenuRepository) RemoveDish(ctx context.Context, menuID, dishID int) error {
+	query := `DELETE FROM menu_dishes WHERE menu_id = $1 AND dish_id = $2`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, menuID, dishID)
+	return err
+}
@@ -0,0 +1,235 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type ModifierRepository struct {
+	db *sql.DB
+}
+
+func NewModifierRepository(db *sql.DB) *ModifierRepository {
+	return &ModifierRepository{db: db}
+}
+
+// GetGroupsByDish returns the dish's modifier groups with their options and recipe changes.
+// With activeOnly, withdrawn options are left out.
+func (r *ModifierRepository) GetGroupsByDish(ctx context.Context, dishID int, activeOnly bool) ([]domain.ModifierGroup, error) {
+	query := `
+		SELECT id, dish_id, name, is_required, min_select, max_select, position
+		FROM modifier_groups
+		WHERE dish_id = $1
+		ORDER BY position, id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, dishID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []domain.ModifierGroup
+	for rows.Next() {
+		var g domain.ModifierGroup
+		if err := rows.Scan(&g.ID, &g.DishID, &g.Name, &g.Required, &g.MinSelect, &g.MaxSelect, &g.Position); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range groups {
+		groups[i].Options, err = r.getOptions(ctx, groups[i].ID, activeOnly)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+func (r *ModifierRepository) GetGroupByID(ctx context.Context, id int) (*domain.ModifierGroup, error) {
+	query := `
+		SELECT id, dish_id, name, is_required, min_select, max_select, position
+		FROM modifier_groups
+		WHERE id = $1`
+
+	g := &domain.ModifierGroup{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&g.ID, &g.DishID, &g.Name, &g.Required, &g.MinSelect, &g.MaxSelect, &g.Position,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	g.Options, err = r.getOptions(ctx, g.ID, false)
+	return g, err
+}
+
+func (r *ModifierRepository) CreateGroup(ctx context.Context, g *domain.ModifierGroup) error {
+	query := `
+		INSERT INTO modifier_groups (dish_id, name, is_required, min_select, max_select, position)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		g.DishID, g.Name, g.Required, g.MinSelect, g.MaxSelect, g.Position,
+	).Scan(&g.ID)
+}
+
+func (r *ModifierRepository) UpdateGroup(ctx context.Context, g *domain.ModifierGroup) error {
+	query := `
+		UPDATE modifier_groups
+		SET name = $1, is_required = $2, min_select = $3, max_select = $4, position = $5
+		WHERE id = $6`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, g.Name, g.Required, g.MinSelect, g.MaxSelect, g.Position, g.ID)
+	return err
+}
+
+func (r *ModifierRepository) DeleteGroup(ctx context.Context, id int) error {
+	query := `DELETE FROM modifier_groups WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *ModifierRepository) getOptions(ctx context.Context, groupID int, activeOnly bool) ([]domain.ModifierOption, error) {
+	query := `
+		SELECT id, group_id, name, price_delta, is_active, position
+		FROM modifier_options
+		WHERE group_id = $1`
+	if activeOnly {
+		query += ` AND is_active = true`
+	}
+	query += ` ORDER BY position, id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []domain.ModifierOption
+	for rows.Next() {
+		var o domain.ModifierOption
+		if err := rows.Scan(&o.ID, &o.GroupID, &o.Name, &o.PriceDelta, &o.IsActive, &o.Position); err != nil {
+			return nil, err
+		}
+		options = append(options, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range options {
+		options[i].Ingredients, err = r.GetOptionIngredients(ctx, options[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+func (r *ModifierRepository) GetOptionByID(ctx context.Context, id int) (*domain.ModifierOption, error) {
+	query := `
+		SELECT id, group_id, name, price_delta, is_active, position
+		FROM modifier_options
+		WHERE id = $1`
+
+	o := &domain.ModifierOption{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&o.ID, &o.GroupID, &o.Name, &o.PriceDelta, &o.IsActive, &o.Position,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	o.Ingredients, err = r.GetOptionIngredients(ctx, o.ID)
+	return o, err
+}
+
+func (r *ModifierRepository) CreateOption(ctx context.Context, o *domain.ModifierOption) error {
+	query := `
+		INSERT INTO modifier_options (group_id, name, price_delta, is_active, position)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		o.GroupID, o.Name, o.PriceDelta, o.IsActive, o.Position,
+	).Scan(&o.ID)
+}
+
+func (r *ModifierRepository) UpdateOption(ctx context.Context, o *domain.ModifierOption) error {
+	query := `
+		UPDATE modifier_options
+		SET name = $1, price_delta = $2, is_active = $3, position = $4
+		WHERE id = $5`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, o.Name, o.PriceDelta, o.IsActive, o.Position, o.ID)
+	return err
+}
+
+// DeleteOption withdraws the option; orders that already have it keep their recipe changes
+func (r *ModifierRepository) DeleteOption(ctx context.Context, id int) error {
+	query := `UPDATE modifier_options SET is_active = false WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *ModifierRepository) GetOptionIngredients(ctx context.Context, optionID int) ([]domain.ModifierIngredient, error) {
+	query := `
+		SELECT mi.option_id, mi.ingredient_id, mi.qty_delta,
+		       i.id, i.name, i.unit
+		FROM modifier_option_ingredients mi
+		JOIN ingredients i ON i.id = mi.ingredient_id
+		WHERE mi.option_id = $1
+		ORDER BY i.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, optionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ingredients []domain.ModifierIngredient
+	for rows.Next() {
+		var mi domain.ModifierIngredient
+		mi.Ingredient = &domain.Ingredient{}
+		if err := rows.Scan(
+			&mi.OptionID, &mi.IngredientID, &mi.QtyDelta,
+			&mi.Ingredient.ID, &mi.Ingredient.Name, &mi.Ingredient.Unit,
+		); err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, mi)
+	}
+
+	return ingredients, rows.Err()
+}
+
+// SetOptionIngredients replaces the recipe changes of the option
+func (r *ModifierRepository) SetOptionIngredients(ctx context.Context, optionID int, ingredients []domain.ModifierIngredient) error {
+	query := `DELETE FROM modifier_option_ingredients WHERE option_id = $1`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, optionID); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO modifier_option_ingredients (option_id, ingredient_id, qty_delta)
+		VALUES ($1, $2, $3)`
+	for _, mi := range ingredients {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, optionID, mi.IngredientID, mi.QtyDelta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,20 +18,24 @@ func NewOrderRepository(db *sql.DB) *OrderRepository {
 
 func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
 	query := `
-		INSERT INTO orders (waiter_id, table_number, status, total, notes)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO orders (
+			waiter_id, table_number, status, guests, subtotal, discount, tax,
+			service_charge_rate, service_charge, total, promo_code, notes
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id, created_at, updated_at`
 
-	return r.db.QueryRowContext(ctx, query,
-		order.WaiterID, order.TableNumber, order.Status, order.Total, order.Notes,
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		order.WaiterID, order.TableNumber, order.Status, order.Guests, order.Subtotal, order.Discount,
+		order.Tax, order.ServiceChargeRate, order.ServiceCharge, order.Total, order.PromoCode, order.Notes,
 	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
 }
 
 func (r *OrderRepository) GetByID(ctx context.Context, id int) (*domain.Order, error) {
 	query := `
 		SELECT 
-			o.id, o.waiter_id, o.table_number, o.status, o.total, o.notes, 
-			o.created_at, o.updated_at,
+			o.id, o.waiter_id, o.table_number, o.status, o.guests, o.subtotal, o.discount, o.tax,
+			o.service_charge_rate, o.service_charge, o.total, o.promo_code, o.notes, o.created_at, o.updated_at,
 			u.id, u.username, u.role, u.photokey, u.is_active, u.created_at,
 			t.id, t.name, t.status
 		FROM orders o
@@ -45,9 +49,9 @@ func (r *OrderRepository) GetByID(ctx context.Context, id int) (*domain.Order, e
 	}
 
 	var waiterCreatedAt time.Time
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&order.ID, &order.WaiterID, &order.TableNumber, &order.Status, &order.Total, &order.Notes,
-		&order.CreatedAt, &order.UpdatedAt,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&order.ID, &order.WaiterID, &order.TableNumber, &order.Status, &order.Guests, &order.Subtotal,
+		&order.Discount, &order.Tax, &order.ServiceChargeRate, &order.ServiceCharge, &order.Total, &order.PromoCode, &order.Notes, &order.CreatedAt, &order.UpdatedAt,
 		&order.Waiter.ID, &order.Waiter.Username, &order.Waiter.Role, &order.Waiter.PhotoKey,
 		&order.Waiter.IsActive, &waiterCreatedAt,
 		&order.Table.ID, &order.Table.Name, &order.Table.Status,
@@ -67,8 +71,8 @@ func (r *OrderRepository) GetByID(ctx context.Context, id int) (*domain.Order, e
 func (r *OrderRepository) GetAll(ctx context.Context, status *domain.OrderStatus) ([]domain.Order, error) {
 	query := `
 		SELECT 
-			o.id, o.waiter_id, o.table_number, o.status, o.total, o.notes, 
-			o.created_at, o.updated_at,
+			o.id, o.waiter_id, o.table_number, o.status, o.guests, o.subtotal, o.discount, o.tax,
+			o.service_charge_rate, o.service_charge, o.total, o.promo_code, o.notes, o.created_at, o.updated_at,
 			COALESCE(u.username, '') as waiter_username,
 			COALESCE(t.name, '') as table_name,
 			t.id as table_id
@@ -83,7 +87,7 @@ func (r *OrderRepository) GetAll(ctx context.Context, status *domain.OrderStatus
 	}
 	query += ` ORDER BY o.created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -96,8 +100,8 @@ func (r *OrderRepository) GetAll(ctx context.Context, status *domain.OrderStatus
 		var tableID int
 
 		if err := rows.Scan(
-			&order.ID, &order.WaiterID, &order.TableNumber, &order.Status, &order.Total,
-			&order.Notes, &order.CreatedAt, &order.UpdatedAt,
+			&order.ID, &order.WaiterID, &order.TableNumber, &order.Status, &order.Guests, &order.Subtotal,
+			&order.Discount, &order.Tax, &order.ServiceChargeRate, &order.ServiceCharge, &order.Total, &order.PromoCode, &order.Notes, &order.CreatedAt, &order.UpdatedAt,
 			&waiterUsername, &tableName, &tableID,
 		); err != nil {
 			return nil, err
@@ -116,53 +120,158 @@ func (r *OrderRepository) GetAll(ctx context.Context, status *domain.OrderStatus
 	return orders, rows.Err()
 }
 
+// GetOpenIDsByTable returns IDs of the orders still served at the table
+func (r *OrderRepository) GetOpenIDsByTable(ctx context.Context, tableID int) ([]int, error) {
+	query := `
+		SELECT id FROM orders
+		WHERE table_number = $1 AND status IN ('new', 'in_progress', 'ready')
+		ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, tableID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *OrderRepository) ReassignOpen(ctx context.Context, fromWaiterID, toWaiterID int, tableID *int) (int, error) {
+	query := `
+		UPDATE orders SET waiter_id = $2, updated_at = $4
+		WHERE waiter_id = $1 AND status IN ('new', 'in_progress', 'ready')
+		  AND ($3::int IS NULL OR table_number = $3)`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, fromWaiterID, toWaiterID, tableID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// Lock locks the order row until the end of the current transaction
+func (r *OrderRepository) Lock(ctx context.Context, id int) error {
+	query := `SELECT id FROM orders WHERE id = $1 FOR UPDATE`
+	var lockedID int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
 func (r *OrderRepository) UpdateStatus(ctx context.Context, id int, status domain.OrderStatus) error {
 	query := `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3`
-	_, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, status, time.Now(), id)
 	return err
 }
 
 func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error {
 	query := `
 		UPDATE orders 
-		SET waiter_id = $1, table_number = $2, status = $3, total = $4, notes = $5, updated_at = $6
-		WHERE id = $7`
-
-	_, err := r.db.ExecContext(ctx, query,
-		order.WaiterID, order.TableNumber, order.Status, order.Total, order.Notes, time.Now(), order.ID,
+		SET waiter_id = $1, table_number = $2, status = $3, guests = $4, subtotal = $5, discount = $6,
+			tax = $7, service_charge_rate = $8, service_charge = $9, total = $10,
+			promo_code = $11, notes = $12, updated_at = $13
+		WHERE id = $14`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		order.WaiterID, order.TableNumber, order.Status, order.Guests, order.Subtotal, order.Discount,
+		order.Tax, order.ServiceChargeRate, order.ServiceCharge, order.Total,
+		order.PromoCode, order.Notes, time.Now(), order.ID,
 	)
 	return err
 }
 
 func (r *OrderRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM orders WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	return err
 }
 
 func (r *OrderRepository) AddItem(ctx context.Context, item *domain.OrderItem) error {
+	if item.Status == "" {
+		item.Status = domain.ItemQueued
+	}
+
 	query := `
-		INSERT INTO order_items (order_id, dish_id, qty, price, notes)
+		INSERT INTO order_items (order_id, dish_id, combo_line_id, qty, price, vat_rate, notes, seat, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		item.OrderID, item.DishID, item.ComboLineID, item.Qty, item.Price, item.VatRate, item.Notes, item.Seat, item.Status,
+	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO order_item_modifiers (order_item_id, option_id, group_name, name, price_delta)
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id`
+	for i := range item.Modifiers {
+		m := &item.Modifiers[i]
+		m.OrderItemID = item.ID
+		if err := conn(ctx, r.db).QueryRowContext(ctx, query,
+			m.OrderItemID, m.OptionID, m.GroupName, m.Name, m.PriceDelta,
+		).Scan(&m.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getModifiers loads chosen modifiers of the items matched by where, keyed by item ID
+func (r *OrderRepository) getModifiers(ctx context.Context, where string, args ...interface{}) (map[int][]domain.OrderItemModifier, error) {
+	query := `
+		SELECT m.id, m.order_item_id, m.option_id, m.group_name, m.name, m.price_delta
+		FROM order_item_modifiers m
+		JOIN order_items oi ON oi.id = m.order_item_id
+		JOIN orders o ON o.id = oi.order_id
+		WHERE ` + where + `
+		ORDER BY m.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	modifiers := make(map[int][]domain.OrderItemModifier)
+	for rows.Next() {
+		var m domain.OrderItemModifier
+		if err := rows.Scan(&m.ID, &m.OrderItemID, &m.OptionID, &m.GroupName, &m.Name, &m.PriceDelta); err != nil {
+			return nil, err
+		}
+		modifiers[m.OrderItemID] = append(modifiers[m.OrderItemID], m)
+	}
 
-	return r.db.QueryRowContext(ctx, query,
-		item.OrderID, item.DishID, item.Qty, item.Price, item.Notes,
-	).Scan(&item.ID)
+	return modifiers, rows.Err()
 }
 
 func (r *OrderRepository) GetItems(ctx context.Context, orderID int) ([]domain.OrderItem, error) {
 	query := `
 		SELECT 
-			oi.id, oi.order_id, oi.dish_id, oi.qty, oi.price, 
-			COALESCE(oi.notes, '') as notes,
-			d.id, d.name, d.price, COALESCE(d.photo_url, '') as photo_url
+			oi.id, oi.order_id, oi.dish_id, oi.combo_line_id, oi.qty, oi.price, oi.discount, oi.vat_rate, oi.tax,
+			COALESCE(oi.notes, '') as notes, oi.seat,
+			oi.status, oi.created_at, oi.updated_at,
+			d.id, d.category_id, d.name, d.price, COALESCE(d.photo_url, '') as photo_url
 		FROM order_items oi
 		JOIN dishes d ON oi.dish_id = d.id
 		WHERE oi.order_id = $1
 		ORDER BY oi.id`
 
-	rows, err := r.db.QueryContext(ctx, query, orderID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
 	if err != nil {
 		return nil, err
 	}
@@ -175,9 +284,10 @@ func (r *OrderRepository) GetItems(ctx context.Context, orderID int) ([]domain.O
 		item.Dish = &domain.Dish{}
 
 		if err := rows.Scan(
-			&item.ID, &item.OrderID, &item.DishID, &item.Qty, &item.Price,
-			&notes,
-			&item.Dish.ID, &item.Dish.Name, &item.Dish.Price, &item.Dish.PhotoURL,
+			&item.ID, &item.OrderID, &item.DishID, &item.ComboLineID, &item.Qty, &item.Price, &item.Discount, &item.VatRate, &item.Tax,
+			&notes, &item.Seat,
+			&item.Status, &item.CreatedAt, &item.UpdatedAt,
+			&item.Dish.ID, &item.Dish.CategoryID, &item.Dish.Name, &item.Dish.Price, &item.Dish.PhotoURL,
 		); err != nil {
 			return nil, err
 		}
@@ -188,8 +298,18 @@ func (r *OrderRepository) GetItems(ctx context.Context, orderID int) ([]domain.O
 
 		items = append(items, item)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return items, rows.Err()
+	modifiers, err := r.getModifiers(ctx, `oi.order_id = $1`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Modifiers = modifiers[items[i].ID]
+	}
+	return items, nil
 }
 
 func (r *OrderRepository) UpdateItem(ctx context.Context, item *domain.OrderItem) error {
@@ -198,12 +318,143 @@ func (r *OrderRepository) UpdateItem(ctx context.Context, item *domain.OrderItem
 		SET dish_id = $1, qty = $2, price = $3, notes = $4
 		WHERE id = $5`
 
-	_, err := r.db.ExecContext(ctx, query, item.DishID, item.Qty, item.Price, item.Notes, item.ID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, item.DishID, item.Qty, item.Price, item.Notes, item.ID)
+	return err
+}
+
+func (r *OrderRepository) UpdateItemPricing(ctx context.Context, itemID int, discount, tax float64) error {
+	query := `UPDATE order_items SET discount = $1, tax = $2 WHERE id = $3`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, discount, tax, itemID)
+	return err
+}
+
+func (r *OrderRepository) UpdateItemStatus(ctx context.Context, itemID int, status domain.OrderItemStatus) error {
+	query := `UPDATE order_items SET status = $1, updated_at = $2 WHERE id = $3`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, status, time.Now(), itemID)
 	return err
 }
 
+// GetKitchenQueue returns queued and cooking items of open orders, oldest first
+func (r *OrderRepository) GetKitchenQueue(ctx context.Context) ([]domain.KitchenItem, error) {
+	query := `
+		SELECT 
+			oi.id, oi.order_id, o.table_number, COALESCE(t.name, '') as table_name,
+			oi.dish_id, d.name, oi.qty, COALESCE(oi.notes, '') as notes, oi.status,
+			COALESCE(c.station, 'kitchen') as station, oc.name, oi.created_at
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		JOIN dishes d ON d.id = oi.dish_id
+		LEFT JOIN categories c ON c.id = d.category_id
+		LEFT JOIN order_combos oc ON oc.id = oi.combo_line_id
+		LEFT JOIN tables t ON t.id = o.table_number
+		WHERE oi.status IN ('queued', 'cooking')
+			AND o.status IN ('new', 'in_progress', 'ready')
+		ORDER BY oi.created_at, oi.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.KitchenItem
+	for rows.Next() {
+		var item domain.KitchenItem
+		var notes string
+
+		if err := rows.Scan(
+			&item.ItemID, &item.OrderID, &item.TableNumber, &item.TableName,
+			&item.DishID, &item.DishName, &item.Qty, &notes, &item.Status,
+			&item.Station, &item.Combo, &item.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if notes != "" {
+			item.Notes = &notes
+		}
+
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	modifiers, err := r.getModifiers(ctx,
+		`oi.status IN ('queued', 'cooking') AND o.status IN ('new', 'in_progress', 'ready')`)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Modifiers = domain.ModifierNames(modifiers[items[i].ItemID])
+	}
+	return items, nil
+}
+
 func (r *OrderRepository) DeleteItem(ctx context.Context, itemID int) error {
 	query := `DELETE FROM order_items WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, itemID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, itemID)
 	return err
 }
+
+func (r *OrderRepository) AddCombo(ctx context.Context, line *domain.OrderCombo) error {
+	query := `
+		INSERT INTO order_combos (order_id, combo_id, name, qty, price, seat)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		line.OrderID, line.ComboID, line.Name, line.Qty, line.Price, line.Seat,
+	).Scan(&line.ID, &line.CreatedAt)
+}
+
+// GetCombos returns the combo lines of the order that still have components
+func (r *OrderRepository) GetCombos(ctx context.Context, orderID int) ([]domain.OrderCombo, error) {
+	query := `
+		SELECT oc.id, oc.order_id, oc.combo_id, oc.name, oc.qty, oc.price, oc.seat, oc.created_at
+		FROM order_combos oc
+		WHERE oc.order_id = $1
+			AND EXISTS (SELECT 1 FROM order_items oi WHERE oi.combo_line_id = oc.id)
+		ORDER BY oc.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []domain.OrderCombo
+	for rows.Next() {
+		var line domain.OrderCombo
+		if err := rows.Scan(
+			&line.ID, &line.OrderID, &line.ComboID, &line.Name, &line.Qty, &line.Price, &line.Seat, &line.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// DeleteCombo removes the combo line together with its component items
+func (r *OrderRepository) DeleteCombo(ctx context.Context, lineID int) error {
+	query := `DELETE FROM order_combos WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, lineID)
+	return err
+}
+
+func (r *OrderRepository) MergeInto(ctx context.Context, fromOrderID, toOrderID int) error {
+	queries := []string{
+		`UPDATE order_items SET order_id = $2 WHERE order_id = $1`,
+		`UPDATE order_combos SET order_id = $2 WHERE order_id = $1`,
+		`UPDATE order_discounts SET order_id = $2 WHERE order_id = $1 AND source = 'comp'`,
+		`UPDATE order_voids SET order_id = $2 WHERE order_id = $1 AND status <> 'pending'`,
+	}
+	for _, query := range queries {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, fromOrderID, toOrderID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
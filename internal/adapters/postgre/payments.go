@@ -0,0 +1,161 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type PaymentRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRepository(db *sql.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, p *domain.Payment) error {
+	query := `
+		INSERT INTO payments (
+			order_id, split_id, method, amount, tip, service_charge, tendered, change, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		p.OrderID, p.SplitID, p.Method, p.Amount, p.Tip, p.ServiceCharge, p.Tendered, p.Change, p.CreatedBy,
+	).Scan(&p.ID, &p.CreatedAt)
+}
+
+func (r *PaymentRepository) GetByOrderID(ctx context.Context, orderID int) ([]domain.Payment, error) {
+	query := `
+		SELECT
+			id, order_id, split_id, method, amount, tip, service_charge, tendered, change,
+			created_by, created_at
+		FROM payments
+		WHERE order_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []domain.Payment
+	for rows.Next() {
+		var p domain.Payment
+		if err := rows.Scan(
+			&p.ID, &p.OrderID, &p.SplitID, &p.Method, &p.Amount, &p.Tip, &p.ServiceCharge,
+			&p.Tendered, &p.Change, &p.CreatedBy, &p.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, rows.Err()
+}
+
+func (r *PaymentRepository) GetPaidTotal(ctx context.Context, orderID int) (float64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM payments WHERE order_id = $1`
+
+	var total float64
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, orderID).Scan(&total)
+	return total, err
+}
+
+func (r *PaymentRepository) CreateSplit(ctx context.Context, split *domain.BillSplit) error {
+	query := `
+		INSERT INTO bill_splits (order_id, label, amount)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		split.OrderID, split.Label, split.Amount,
+	).Scan(&split.ID, &split.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO bill_split_items (split_id, order_item_id, qty, amount)
+		VALUES ($1, $2, $3, $4)`
+
+	for i := range split.Items {
+		split.Items[i].SplitID = split.ID
+		if _, err := conn(ctx, r.db).ExecContext(ctx, itemQuery,
+			split.ID, split.Items[i].OrderItemID, split.Items[i].Qty, split.Items[i].Amount,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *PaymentRepository) GetSplits(ctx context.Context, orderID int) ([]domain.BillSplit, error) {
+	query := `
+		SELECT
+			s.id, s.order_id, s.label, s.amount, s.created_at,
+			COALESCE((SELECT SUM(p.amount) FROM payments p WHERE p.split_id = s.id), 0) as paid
+		FROM bill_splits s
+		WHERE s.order_id = $1
+		ORDER BY s.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var splits []domain.BillSplit
+	index := make(map[int]int)
+	for rows.Next() {
+		var split domain.BillSplit
+		if err := rows.Scan(
+			&split.ID, &split.OrderID, &split.Label, &split.Amount, &split.CreatedAt, &split.Paid,
+		); err != nil {
+			return nil, err
+		}
+		index[split.ID] = len(splits)
+		splits = append(splits, split)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	itemQuery := `
+		SELECT si.split_id, si.order_item_id, d.name, si.qty, si.amount
+		FROM bill_split_items si
+		JOIN bill_splits s ON s.id = si.split_id
+		JOIN order_items oi ON oi.id = si.order_item_id
+		JOIN dishes d ON d.id = oi.dish_id
+		WHERE s.order_id = $1
+		ORDER BY si.split_id, si.order_item_id`
+
+	itemRows, err := conn(ctx, r.db).QueryContext(ctx, itemQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item domain.BillSplitItem
+		if err := itemRows.Scan(&item.SplitID, &item.OrderItemID, &item.DishName, &item.Qty, &item.Amount); err != nil {
+			return nil, err
+		}
+		if i, ok := index[item.SplitID]; ok {
+			splits[i].Items = append(splits[i].Items, item)
+		}
+	}
+
+	return splits, itemRows.Err()
+}
+
+func (r *PaymentRepository) DeleteSplits(ctx context.Context, orderID int) error {
+	query := `DELETE FROM bill_splits WHERE order_id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, orderID)
+	return err
+}
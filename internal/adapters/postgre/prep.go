@@ -0,0 +1,194 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type PrepRepository struct {
+	db *sql.DB
+}
+
+func NewPrepRepository(db *sql.DB) *PrepRepository {
+	return &PrepRepository{db: db}
+}
+
+const prepColumns = `p.ingredient_id, p.batch_yield, p.made_to_order,
+	i.id, i.name, i.unit, i.qty, i.reserved_qty, i.min_qty, i.unit_cost`
+
+func scanPrep(row rowScanner) (*domain.PrepItem, error) {
+	p := &domain.PrepItem{Ingredient: &domain.Ingredient{}}
+	err := row.Scan(
+		&p.IngredientID, &p.BatchYield, &p.MadeToOrder,
+		&p.Ingredient.ID, &p.Ingredient.Name, &p.Ingredient.Unit, &p.Ingredient.Qty,
+		&p.Ingredient.ReservedQty, &p.Ingredient.MinQty, &p.Ingredient.UnitCost,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *PrepRepository) GetAll(ctx context.Context) ([]domain.PrepItem, error) {
+	query := `
+		SELECT ` + prepColumns + `
+		FROM prep_items p
+		JOIN ingredients i ON i.id = p.ingredient_id
+		ORDER BY i.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var preps []domain.PrepItem
+	for rows.Next() {
+		p, err := scanPrep(rows)
+		if err != nil {
+			return nil, err
+		}
+		preps = append(preps, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range preps {
+		preps[i].Components, err = r.getComponents(ctx, preps[i].IngredientID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return preps, nil
+}
+
+func (r *PrepRepository) GetByID(ctx context.Context, ingredientID int) (*domain.PrepItem, error) {
+	query := `
+		SELECT ` + prepColumns + `
+		FROM prep_items p
+		JOIN ingredients i ON i.id = p.ingredient_id
+		WHERE p.ingredient_id = $1`
+
+	p, err := scanPrep(conn(ctx, r.db).QueryRowContext(ctx, query, ingredientID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.Components, err = r.getComponents(ctx, p.IngredientID)
+	return p, err
+}
+
+func (r *PrepRepository) getComponents(ctx context.Context, prepID int) ([]domain.PrepComponent, error) {
+	query := `
+		SELECT pi.prep_id, pi.ingredient_id, pi.qty_per_batch,
+		       i.id, i.name, i.unit, i.qty, i.reserved_qty, i.min_qty, i.unit_cost
+		FROM prep_ingredients pi
+		JOIN ingredients i ON i.id = pi.ingredient_id
+		WHERE pi.prep_id = $1
+		ORDER BY i.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, prepID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var components []domain.PrepComponent
+	for rows.Next() {
+		c := domain.PrepComponent{Ingredient: &domain.Ingredient{}}
+		if err := rows.Scan(
+			&c.PrepID, &c.IngredientID, &c.QtyPerBatch,
+			&c.Ingredient.ID, &c.Ingredient.Name, &c.Ingredient.Unit, &c.Ingredient.Qty,
+			&c.Ingredient.ReservedQty, &c.Ingredient.MinQty, &c.Ingredient.UnitCost,
+		); err != nil {
+			return nil, err
+		}
+		components = append(components, c)
+	}
+
+	return components, rows.Err()
+}
+
+func (r *PrepRepository) Save(ctx context.Context, p *domain.PrepItem) error {
+	query := `
+		INSERT INTO prep_items (ingredient_id, batch_yield, made_to_order)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (ingredient_id) DO UPDATE
+		SET batch_yield = EXCLUDED.batch_yield, made_to_order = EXCLUDED.made_to_order`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, p.IngredientID, p.BatchYield, p.MadeToOrder); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM prep_ingredients WHERE prep_id = $1`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, p.IngredientID); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO prep_ingredients (prep_id, ingredient_id, qty_per_batch)
+		VALUES ($1, $2, $3)`
+	for i := range p.Components {
+		c := &p.Components[i]
+		c.PrepID = p.IngredientID
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, c.PrepID, c.IngredientID, c.QtyPerBatch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete turns the prep item back into a plain ingredient; its stock stays
+func (r *PrepRepository) Delete(ctx context.Context, ingredientID int) error {
+	query := `DELETE FROM prep_items WHERE ingredient_id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, ingredientID)
+	return err
+}
+
+func (r *PrepRepository) CreateBatch(ctx context.Context, b *domain.ProductionBatch) error {
+	query := `
+		INSERT INTO production_batches (prep_id, batches, yield_qty, cost, user_id, notes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		b.PrepID, b.Batches, b.YieldQty, b.Cost, b.UserID, b.Notes,
+	).Scan(&b.ID, &b.CreatedAt)
+}
+
+func (r *PrepRepository) GetBatches(ctx context.Context, prepID *int, limit int) ([]domain.ProductionBatch, error) {
+	query := `
+		SELECT b.id, b.prep_id, b.batches, b.yield_qty, b.cost, b.user_id, b.notes, b.created_at,
+		       i.id, i.name, i.unit
+		FROM production_batches b
+		JOIN ingredients i ON i.id = b.prep_id
+		WHERE ($1::int IS NULL OR b.prep_id = $1)
+		ORDER BY b.created_at DESC
+		LIMIT $2`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, prepID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []domain.ProductionBatch
+	for rows.Next() {
+		b := domain.ProductionBatch{Prep: &domain.Ingredient{}}
+		if err := rows.Scan(
+			&b.ID, &b.PrepID, &b.Batches, &b.YieldQty, &b.Cost, &b.UserID, &b.Notes, &b.CreatedAt,
+			&b.Prep.ID, &b.Prep.Name, &b.Prep.Unit,
+		); err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+
+	return batches, rows.Err()
+}
@@ -0,0 +1,179 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+const promotionColumns = `
+	id, name, type, value, category_id, dish_id, buy_qty, get_qty, min_subtotal, code,
+	starts_at, ends_at, happy_hour_start, happy_hour_end, is_active, created_at`
+
+func scanPromotion(row rowScanner, p *domain.Promotion) error {
+	return row.Scan(
+		&p.ID, &p.Name, &p.Type, &p.Value, &p.CategoryID, &p.DishID, &p.BuyQty, &p.GetQty, &p.MinSubtotal,
+		&p.Code, &p.StartsAt, &p.EndsAt, &p.HappyHourStart, &p.HappyHourEnd, &p.IsActive, &p.CreatedAt,
+	)
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, p *domain.Promotion) error {
+	query := `
+		INSERT INTO promotions (
+			name, type, value, category_id, dish_id, buy_qty, get_qty, min_subtotal, code,
+			starts_at, ends_at, happy_hour_start, happy_hour_end, is_active
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		p.Name, p.Type, p.Value, p.CategoryID, p.DishID, p.BuyQty, p.GetQty, p.MinSubtotal, p.Code,
+		p.StartsAt, p.EndsAt, p.HappyHourStart, p.HappyHourEnd, p.IsActive,
+	).Scan(&p.ID, &p.CreatedAt)
+}
+
+func (r *PromotionRepository) GetAll(ctx context.Context) ([]domain.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions ORDER BY created_at DESC`
+	return r.list(ctx, query)
+}
+
+// GetActive returns promotions switched on; dates and happy hours are checked by the caller
+func (r *PromotionRepository) GetActive(ctx context.Context) ([]domain.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE is_active = true ORDER BY id`
+	return r.list(ctx, query)
+}
+
+func (r *PromotionRepository) GetByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE id = $1`
+
+	p := &domain.Promotion{}
+	err := scanPromotion(conn(ctx, r.db).QueryRowContext(ctx, query, id), p)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return p, err
+}
+
+func (r *PromotionRepository) GetByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE LOWER(code) = LOWER($1)`
+
+	p := &domain.Promotion{}
+	err := scanPromotion(conn(ctx, r.db).QueryRowContext(ctx, query, code), p)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return p, err
+}
+
+func (r *PromotionRepository) Update(ctx context.Context, p *domain.Promotion) error {
+	query := `
+		UPDATE promotions
+		SET name = $1, type = $2, value = $3, category_id = $4, dish_id = $5, buy_qty = $6, get_qty = $7,
+			min_subtotal = $8, code = $9, starts_at = $10, ends_at = $11, happy_hour_start = $12,
+			happy_hour_end = $13, is_active = $14
+		WHERE id = $15`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		p.Name, p.Type, p.Value, p.CategoryID, p.DishID, p.BuyQty, p.GetQty,
+		p.MinSubtotal, p.Code, p.StartsAt, p.EndsAt, p.HappyHourStart,
+		p.HappyHourEnd, p.IsActive, p.ID,
+	)
+	return err
+}
+
+func (r *PromotionRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM promotions WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *PromotionRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.Promotion, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []domain.Promotion
+	for rows.Next() {
+		var p domain.Promotion
+		if err := scanPromotion(rows, &p); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+
+	return promotions, rows.Err()
+}
+
+const discountColumns = `
+	id, order_id, order_item_id, promotion_id, source, name, type, value, amount, reason,
+	created_by, created_at`
+
+func (r *PromotionRepository) CreateDiscount(ctx context.Context, d *domain.OrderDiscount) error {
+	query := `
+		INSERT INTO order_discounts (
+			order_id, order_item_id, promotion_id, source, name, type, value, amount, reason, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		d.OrderID, d.OrderItemID, d.PromotionID, d.Source, d.Name, d.Type, d.Value, d.Amount, d.Reason,
+		d.CreatedBy,
+	).Scan(&d.ID, &d.CreatedAt)
+}
+
+func (r *PromotionRepository) GetDiscounts(ctx context.Context, orderID int) ([]domain.OrderDiscount, error) {
+	query := `SELECT ` + discountColumns + ` FROM order_discounts WHERE order_id = $1 ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discounts []domain.OrderDiscount
+	for rows.Next() {
+		var d domain.OrderDiscount
+		if err := rows.Scan(
+			&d.ID, &d.OrderID, &d.OrderItemID, &d.PromotionID, &d.Source, &d.Name, &d.Type, &d.Value,
+			&d.Amount, &d.Reason, &d.CreatedBy, &d.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, d)
+	}
+
+	return discounts, rows.Err()
+}
+
+func (r *PromotionRepository) UpdateDiscountAmount(ctx context.Context, id int, amount float64) error {
+	query := `UPDATE order_discounts SET amount = $1 WHERE id = $2`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, amount, id)
+	return err
+}
+
+func (r *PromotionRepository) DeleteDiscount(ctx context.Context, id int) error {
+	query := `DELETE FROM order_discounts WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// DeletePromotionDiscounts removes the recomputable discounts of an order, keeping comps
+func (r *PromotionRepository) DeletePromotionDiscounts(ctx context.Context, orderID int) error {
+	query := `DELETE FROM order_discounts WHERE order_id = $1 AND source = 'promotion'`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, orderID)
+	return err
+}
@@ -0,0 +1,204 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type PurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+const purchaseOrderColumns = `po.id, po.supplier_id, s.name, po.status, po.expected_at, po.notes,
+	po.created_by, po.created_at, po.sent_at, po.received_at`
+
+func scanPurchaseOrder(row rowScanner) (*domain.PurchaseOrder, error) {
+	po := &domain.PurchaseOrder{}
+	err := row.Scan(
+		&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.ExpectedAt, &po.Notes,
+		&po.CreatedBy, &po.CreatedAt, &po.SentAt, &po.ReceivedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// Create inserts the purchase order together with its items
+func (r *PurchaseOrderRepository) Create(ctx context.Context, po *domain.PurchaseOrder) error {
+	query := `
+		INSERT INTO purchase_orders (supplier_id, status, expected_at, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		po.SupplierID, po.Status, po.ExpectedAt, po.Notes, po.CreatedBy,
+	).Scan(&po.ID, &po.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return r.insertItems(ctx, po)
+}
+
+// Update rewrites the header and replaces the items of a draft
+func (r *PurchaseOrderRepository) Update(ctx context.Context, po *domain.PurchaseOrder) error {
+	query := `
+		UPDATE purchase_orders SET supplier_id = $1, expected_at = $2, notes = $3
+		WHERE id = $4`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, po.SupplierID, po.ExpectedAt, po.Notes, po.ID); err != nil {
+		return err
+	}
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM purchase_order_items WHERE purchase_order_id = $1`, po.ID); err != nil {
+		return err
+	}
+
+	return r.insertItems(ctx, po)
+}
+
+func (r *PurchaseOrderRepository) insertItems(ctx context.Context, po *domain.PurchaseOrder) error {
+	query := `
+		INSERT INTO purchase_order_items (purchase_order_id, ingredient_id, qty_ordered, unit_price)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	for i := range po.Items {
+		item := &po.Items[i]
+		item.PurchaseOrderID = po.ID
+		err := conn(ctx, r.db).QueryRowContext(ctx, query,
+			po.ID, item.IngredientID, item.QtyOrdered, item.UnitPrice,
+		).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAll returns purchase orders with items, newest first
+func (r *PurchaseOrderRepository) GetAll(ctx context.Context, f domain.PurchaseOrderFilter) ([]domain.PurchaseOrder, error) {
+	query := `
+		SELECT ` + purchaseOrderColumns + `
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		WHERE ($1::int IS NULL OR po.supplier_id = $1)
+		  AND ($2::text IS NULL OR po.status = $2)
+		ORDER BY po.created_at DESC, po.id DESC`
+
+	var status *string
+	if f.Status != nil {
+		v := string(*f.Status)
+		status = &v
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, f.SupplierID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []domain.PurchaseOrder
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *po)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		orders[i].Items, err = r.getItems(ctx, orders[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+func (r *PurchaseOrderRepository) GetByID(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	return r.getOne(ctx, `WHERE po.id = $1`, id)
+}
+
+// GetByIDForUpdate locks the purchase order so deliveries are not received twice
+func (r *PurchaseOrderRepository) GetByIDForUpdate(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	return r.getOne(ctx, `WHERE po.id = $1 FOR UPDATE OF po`, id)
+}
+
+func (r *PurchaseOrderRepository) getOne(ctx context.Context, where string, args ...any) (*domain.PurchaseOrder, error) {
+	query := `
+		SELECT ` + purchaseOrderColumns + `
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		` + where
+
+	po, err := scanPurchaseOrder(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	po.Items, err = r.getItems(ctx, po.ID)
+	return po, err
+}
+
+func (r *PurchaseOrderRepository) getItems(ctx context.Context, purchaseOrderID int) ([]domain.PurchaseOrderItem, error) {
+	query := `
+		SELECT poi.id, poi.purchase_order_id, poi.ingredient_id, poi.qty_ordered, poi.qty_received, poi.unit_price,
+		       i.id, i.name, i.unit
+		FROM purchase_order_items poi
+		JOIN ingredients i ON i.id = poi.ingredient_id
+		WHERE poi.purchase_order_id = $1
+		ORDER BY poi.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.PurchaseOrderItem
+	for rows.Next() {
+		item := domain.PurchaseOrderItem{Ingredient: &domain.Ingredient{}}
+		if err := rows.Scan(
+			&item.ID, &item.PurchaseOrderID, &item.IngredientID, &item.QtyOrdered, &item.QtyReceived, &item.UnitPrice,
+			&item.Ingredient.ID, &item.Ingredient.Name, &item.Ingredient.Unit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// SetStatus moves the order through its lifecycle, stamping when it was sent
+// and when it was received in full
+func (r *PurchaseOrderRepository) SetStatus(ctx context.Context, po *domain.PurchaseOrder) error {
+	query := `
+		UPDATE purchase_orders
+		SET status = $1::text,
+		    sent_at = CASE WHEN $1::text = 'sent' THEN CURRENT_TIMESTAMP ELSE sent_at END,
+		    received_at = CASE WHEN $1::text = 'received' THEN CURRENT_TIMESTAMP ELSE received_at END
+		WHERE id = $2
+		RETURNING sent_at, received_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, po.Status, po.ID).Scan(&po.SentAt, &po.ReceivedAt)
+}
+
+func (r *PurchaseOrderRepository) AddReceived(ctx context.Context, itemID int, qty float64) error {
+	query := `UPDATE purchase_order_items SET qty_received = qty_received + $1 WHERE id = $2`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, qty, itemID)
+	return err
+}
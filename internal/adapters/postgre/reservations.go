@@ -0,0 +1,188 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type ReservationRepository struct {
+	db *sql.DB
+}
+
+func NewReservationRepository(db *sql.DB) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+const reservationColumns = `
+	r.id, r.table_id, r.guest_name, r.guest_phone, r.party_size, r.starts_at, r.ends_at, r.status,
+	r.notes, r.created_by, r.created_at, r.updated_at,
+	t.id, t.name, t.capacity, t.status`
+
+func scanReservation(row rowScanner, res *domain.Reservation) error {
+	res.Table = &domain.Table{}
+	return row.Scan(
+		&res.ID, &res.TableID, &res.GuestName, &res.GuestPhone, &res.PartySize, &res.StartsAt, &res.EndsAt,
+		&res.Status, &res.Notes, &res.CreatedBy, &res.CreatedAt, &res.UpdatedAt,
+		&res.Table.ID, &res.Table.Name, &res.Table.Capacity, &res.Table.Status,
+	)
+}
+
+func (r *ReservationRepository) Create(ctx context.Context, res *domain.Reservation) error {
+	query := `
+		INSERT INTO reservations (
+			table_id, guest_name, guest_phone, party_size, starts_at, ends_at, status, notes, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		res.TableID, res.GuestName, res.GuestPhone, res.PartySize, res.StartsAt, res.EndsAt, res.Status,
+		res.Notes, res.CreatedBy,
+	).Scan(&res.ID, &res.CreatedAt, &res.UpdatedAt)
+}
+
+func (r *ReservationRepository) GetByID(ctx context.Context, id int) (*domain.Reservation, error) {
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM reservations r
+		JOIN tables t ON t.id = r.table_id
+		WHERE r.id = $1`
+
+	res := &domain.Reservation{}
+	err := scanReservation(conn(ctx, r.db).QueryRowContext(ctx, query, id), res)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return res, err
+}
+
+func (r *ReservationRepository) GetAll(ctx context.Context, filter domain.ReservationFilter) ([]domain.Reservation, error) {
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM reservations r
+		JOIN tables t ON t.id = r.table_id
+		WHERE 1 = 1`
+
+	args := []interface{}{}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		query += fmt.Sprintf(` AND r.ends_at > $%d`, len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		query += fmt.Sprintf(` AND r.starts_at < $%d`, len(args))
+	}
+	if filter.TableID != nil {
+		args = append(args, *filter.TableID)
+		query += fmt.Sprintf(` AND r.table_id = $%d`, len(args))
+	}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		query += fmt.Sprintf(` AND r.status = $%d`, len(args))
+	}
+	query += ` ORDER BY r.starts_at`
+
+	return r.list(ctx, query, args...)
+}
+
+func (r *ReservationRepository) Update(ctx context.Context, res *domain.Reservation) error {
+	query := `
+		UPDATE reservations
+		SET table_id = $1, guest_name = $2, guest_phone = $3, party_size = $4, starts_at = $5, ends_at = $6,
+			notes = $7, updated_at = $8
+		WHERE id = $9`
+
+	res.UpdatedAt = time.Now()
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		res.TableID, res.GuestName, res.GuestPhone, res.PartySize, res.StartsAt, res.EndsAt,
+		res.Notes, res.UpdatedAt, res.ID,
+	)
+	return err
+}
+
+func (r *ReservationRepository) UpdateStatus(ctx context.Context, id int, status domain.ReservationStatus) error {
+	query := `UPDATE reservations SET status = $1, updated_at = $2 WHERE id = $3`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, status, time.Now(), id)
+	return err
+}
+
+func (r *ReservationRepository) GetConflicts(ctx context.Context, tableID int, from, to time.Time, excludeID int) ([]domain.Reservation, error) {
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM reservations r
+		JOIN tables t ON t.id = r.table_id
+		WHERE r.table_id = $1
+		  AND r.status IN ('booked', 'seated')
+		  AND r.starts_at < $3 AND r.ends_at > $2
+		  AND r.id <> $4
+		ORDER BY r.starts_at`
+
+	return r.list(ctx, query, tableID, from, to, excludeID)
+}
+
+func (r *ReservationRepository) GetBookedBefore(ctx context.Context, t time.Time) ([]domain.Reservation, error) {
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM reservations r
+		JOIN tables t ON t.id = r.table_id
+		WHERE r.status = 'booked' AND r.starts_at <= $1
+		ORDER BY r.starts_at`
+
+	return r.list(ctx, query, t)
+}
+
+func (r *ReservationRepository) GetAvailableTables(ctx context.Context, from, to time.Time, partySize int) ([]domain.Table, error) {
+	// Самые маленькие подходящие столы — первыми, чтобы не занимать большие под пары
+	query := `
+		SELECT t.id, t.name, t.capacity, t.status
+		FROM tables t
+		WHERE t.capacity >= $3
+		  AND NOT EXISTS (
+			SELECT 1 FROM reservations r
+			WHERE r.table_id = t.id
+			  AND r.status IN ('booked', 'seated')
+			  AND r.starts_at < $2 AND r.ends_at > $1
+		  )
+		ORDER BY t.capacity, t.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to, partySize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []domain.Table
+	for rows.Next() {
+		var table domain.Table
+		if err := rows.Scan(&table.ID, &table.Name, &table.Capacity, &table.Status); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+func (r *ReservationRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.Reservation, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []domain.Reservation
+	for rows.Next() {
+		var res domain.Reservation
+		if err := scanReservation(rows, &res); err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, res)
+	}
+
+	return reservations, rows.Err()
+}
@@ -0,0 +1,99 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type SectionRepository struct {
+	db *sql.DB
+}
+
+func NewSectionRepository(db *sql.DB) *SectionRepository {
+	return &SectionRepository{db: db}
+}
+
+// Assign replaces the waiter's section for the shift. Tables already assigned
+// to someone else for that shift are taken over.
+func (r *SectionRepository) Assign(ctx context.Context, shiftDate time.Time, waiterID int, tableIDs []int, assignedBy *int) error {
+	query := `DELETE FROM table_assignments WHERE shift_date = $1 AND waiter_id = $2`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, shiftDate, waiterID); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO table_assignments (shift_date, table_id, waiter_id, assigned_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (shift_date, table_id)
+		DO UPDATE SET waiter_id = EXCLUDED.waiter_id, assigned_by = EXCLUDED.assigned_by, created_at = NOW()`
+	for _, tableID := range tableIDs {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, shiftDate, tableID, waiterID, assignedBy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SectionRepository) GetByDate(ctx context.Context, shiftDate time.Time) ([]domain.Section, error) {
+	query := `
+		SELECT a.waiter_id, u.username, a.table_id
+		FROM table_assignments a
+		JOIN users u ON u.id = a.waiter_id
+		WHERE a.shift_date = $1
+		ORDER BY u.username, a.table_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, shiftDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sections []domain.Section
+	for rows.Next() {
+		var waiterID, tableID int
+		var waiterName string
+		if err := rows.Scan(&waiterID, &waiterName, &tableID); err != nil {
+			return nil, err
+		}
+
+		if n := len(sections); n == 0 || sections[n-1].WaiterID != waiterID {
+			sections = append(sections, domain.Section{
+				WaiterID:   waiterID,
+				WaiterName: waiterName,
+				ShiftDate:  shiftDate,
+			})
+		}
+		last := &sections[len(sections)-1]
+		last.TableIDs = append(last.TableIDs, tableID)
+	}
+
+	return sections, rows.Err()
+}
+
+// GetTableWaiter returns the waiter the table is assigned to for the shift, nil if nobody
+func (r *SectionRepository) GetTableWaiter(ctx context.Context, shiftDate time.Time, tableID int) (*int, error) {
+	query := `SELECT waiter_id FROM table_assignments WHERE shift_date = $1 AND table_id = $2`
+
+	var waiterID int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, shiftDate, tableID).Scan(&waiterID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &waiterID, nil
+}
+
+// Reassign moves the shift's assignments of one waiter to another; nil tableID moves all of them
+func (r *SectionRepository) Reassign(ctx context.Context, shiftDate time.Time, fromWaiterID, toWaiterID int, tableID *int) error {
+	query := `
+		UPDATE table_assignments SET waiter_id = $3
+		WHERE shift_date = $1 AND waiter_id = $2 AND ($4::int IS NULL OR table_id = $4)`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, shiftDate, fromWaiterID, toWaiterID, tableID)
+	return err
+}
@@ -0,0 +1,74 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+// ApplyMovement shifts the ingredient quantity by m.Qty and writes the ledger
+// entry with the quantities before and after, in one statement
+func (r *IngredientRepository) ApplyMovement(ctx context.Context, m *domain.StockMovement) error {
+	query := `
+		WITH moved AS (
+			UPDATE ingredients SET qty = qty + ROUND($2::NUMERIC, 2)
+			WHERE id = $1
+			RETURNING qty
+		)
+		INSERT INTO stock_movements (ingredient_id, type, qty, qty_before, qty_after, ref_id, user_id, note)
+		SELECT $1, $3, ROUND($2::NUMERIC, 2), moved.qty - ROUND($2::NUMERIC, 2), moved.qty, $4, $5, $6
+		FROM moved
+		RETURNING id, qty, qty_before, qty_after, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		m.IngredientID, m.Qty, m.Type, m.RefID, m.UserID, m.Note,
+	).Scan(&m.ID, &m.Qty, &m.QtyBefore, &m.QtyAfter, &m.CreatedAt)
+	if err == sql.ErrNoRows {
+		return domain.ErrIngredientNotFound
+	}
+	return err
+}
+
+func (r *IngredientRepository) GetMovements(ctx context.Context, f domain.StockMovementFilter) ([]domain.StockMovement, error) {
+	query := `
+		SELECT m.id, m.ingredient_id, m.type, m.qty, m.qty_before, m.qty_after,
+		       m.ref_id, m.user_id, u.username, m.note, m.created_at,
+		       i.id, i.name, i.unit
+		FROM stock_movements m
+		JOIN ingredients i ON i.id = m.ingredient_id
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE ($1::int IS NULL OR m.ingredient_id = $1)
+		  AND ($2::text IS NULL OR m.type = $2)
+		  AND ($3::timestamp IS NULL OR m.created_at >= $3)
+		  AND ($4::timestamp IS NULL OR m.created_at < $4)
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $5`
+
+	var movementType *string
+	if f.Type != nil {
+		t := string(*f.Type)
+		movementType = &t
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, f.IngredientID, movementType, f.From, f.To, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []domain.StockMovement
+	for rows.Next() {
+		m := domain.StockMovement{Ingredient: &domain.Ingredient{}}
+		if err := rows.Scan(
+			&m.ID, &m.IngredientID, &m.Type, &m.Qty, &m.QtyBefore, &m.QtyAfter,
+			&m.RefID, &m.UserID, &m.UserName, &m.Note, &m.CreatedAt,
+			&m.Ingredient.ID, &m.Ingredient.Name, &m.Ingredient.Unit,
+		); err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, rows.Err()
+}
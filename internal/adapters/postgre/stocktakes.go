@@ -0,0 +1,158 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type StocktakeRepository struct {
+	db *sql.DB
+}
+
+func NewStocktakeRepository(db *sql.DB) *StocktakeRepository {
+	return &StocktakeRepository{db: db}
+}
+
+const stocktakeColumns = `id, note, status, started_by, started_at, closed_by, closed_at`
+
+func scanStocktake(row rowScanner) (*domain.Stocktake, error) {
+	s := &domain.Stocktake{}
+	err := row.Scan(&s.ID, &s.Note, &s.Status, &s.StartedBy, &s.StartedAt, &s.ClosedBy, &s.ClosedAt)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *StocktakeRepository) Create(ctx context.Context, s *domain.Stocktake) error {
+	query := `
+		INSERT INTO stocktakes (note, status, started_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, started_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, s.Note, s.Status, s.StartedBy).Scan(&s.ID, &s.StartedAt)
+}
+
+// GetAll returns stocktakes without lines, newest first
+func (r *StocktakeRepository) GetAll(ctx context.Context) ([]domain.Stocktake, error) {
+	query := `SELECT ` + stocktakeColumns + ` FROM stocktakes ORDER BY started_at DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stocktakes []domain.Stocktake
+	for rows.Next() {
+		s, err := scanStocktake(rows)
+		if err != nil {
+			return nil, err
+		}
+		stocktakes = append(stocktakes, *s)
+	}
+
+	return stocktakes, rows.Err()
+}
+
+func (r *StocktakeRepository) GetByID(ctx context.Context, id int) (*domain.Stocktake, error) {
+	query := `SELECT ` + stocktakeColumns + ` FROM stocktakes WHERE id = $1`
+	return r.getOne(ctx, query, id)
+}
+
+// GetByIDForUpdate locks the stocktake so counts and posting do not overlap
+func (r *StocktakeRepository) GetByIDForUpdate(ctx context.Context, id int) (*domain.Stocktake, error) {
+	query := `SELECT ` + stocktakeColumns + ` FROM stocktakes WHERE id = $1 FOR UPDATE`
+	return r.getOne(ctx, query, id)
+}
+
+// GetOpen returns the stocktake in progress, if any
+func (r *StocktakeRepository) GetOpen(ctx context.Context) (*domain.Stocktake, error) {
+	query := `SELECT ` + stocktakeColumns + ` FROM stocktakes WHERE status = 'open'`
+	return r.getOne(ctx, query)
+}
+
+func (r *StocktakeRepository) getOne(ctx context.Context, query string, args ...any) (*domain.Stocktake, error) {
+	s, err := scanStocktake(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lines, err = r.getLines(ctx, s.ID)
+	return s, err
+}
+
+func (r *StocktakeRepository) getLines(ctx context.Context, stocktakeID int) ([]domain.StocktakeLine, error) {
+	query := `
+		SELECT c.stocktake_id, c.ingredient_id, c.counted_qty, c.counted_by, c.counted_at,
+		       c.system_qty, c.unit_cost,
+		       i.id, i.name, i.unit
+		FROM stocktake_counts c
+		JOIN ingredients i ON i.id = c.ingredient_id
+		WHERE c.stocktake_id = $1
+		ORDER BY i.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, stocktakeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []domain.StocktakeLine
+	for rows.Next() {
+		l := domain.StocktakeLine{Ingredient: &domain.Ingredient{}}
+		if err := rows.Scan(
+			&l.StocktakeID, &l.IngredientID, &l.CountedQty, &l.CountedBy, &l.CountedAt,
+			&l.SystemQty, &l.UnitCost,
+			&l.Ingredient.ID, &l.Ingredient.Name, &l.Ingredient.Unit,
+		); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+
+	return lines, rows.Err()
+}
+
+// SaveCount records a count with the system qty and cost at counting time;
+// counting the same ingredient again replaces both
+func (r *StocktakeRepository) SaveCount(ctx context.Context, l *domain.StocktakeLine) error {
+	query := `
+		INSERT INTO stocktake_counts (stocktake_id, ingredient_id, counted_qty, counted_by, system_qty, unit_cost)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (stocktake_id, ingredient_id) DO UPDATE
+		SET counted_qty = EXCLUDED.counted_qty, counted_by = EXCLUDED.counted_by, counted_at = CURRENT_TIMESTAMP,
+		    system_qty = EXCLUDED.system_qty, unit_cost = EXCLUDED.unit_cost
+		RETURNING counted_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		l.StocktakeID, l.IngredientID, l.CountedQty, l.CountedBy, l.SystemQty, l.UnitCost,
+	).Scan(&l.CountedAt)
+}
+
+func (r *StocktakeRepository) DeleteCount(ctx context.Context, stocktakeID, ingredientID int) error {
+	query := `DELETE FROM stocktake_counts WHERE stocktake_id = $1 AND ingredient_id = $2`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, stocktakeID, ingredientID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return domain.ErrStocktakeLineNotFound
+	}
+	return nil
+}
+
+func (r *StocktakeRepository) Close(ctx context.Context, s *domain.Stocktake) error {
+	query := `
+		UPDATE stocktakes SET status = $1, closed_by = $2, closed_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+		RETURNING closed_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, s.Status, s.ClosedBy, s.ID).Scan(&s.ClosedAt)
+}
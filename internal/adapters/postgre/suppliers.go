@@ -0,0 +1,151 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type SupplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) *SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+const supplierColumns = `id, name, contact_name, phone, email, address, notes, lead_time_days, is_active, created_at`
+
+func scanSupplier(row rowScanner) (*domain.Supplier, error) {
+	s := &domain.Supplier{}
+	err := row.Scan(
+		&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email, &s.Address, &s.Notes,
+		&s.LeadTimeDays, &s.IsActive, &s.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetAll returns suppliers without their catalogs
+func (r *SupplierRepository) GetAll(ctx context.Context, activeOnly bool) ([]domain.Supplier, error) {
+	query := `SELECT ` + supplierColumns + ` FROM suppliers`
+	if activeOnly {
+		query += ` WHERE is_active = TRUE`
+	}
+	query += ` ORDER BY name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suppliers []domain.Supplier
+	for rows.Next() {
+		s, err := scanSupplier(rows)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, *s)
+	}
+
+	return suppliers, rows.Err()
+}
+
+func (r *SupplierRepository) GetByID(ctx context.Context, id int) (*domain.Supplier, error) {
+	query := `SELECT ` + supplierColumns + ` FROM suppliers WHERE id = $1`
+
+	s, err := scanSupplier(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.Catalog, err = r.getCatalog(ctx, s.ID)
+	return s, err
+}
+
+func (r *SupplierRepository) getCatalog(ctx context.Context, supplierID int) ([]domain.SupplierIngredient, error) {
+	query := `
+		SELECT si.supplier_id, si.ingredient_id, si.unit_price, si.sku, si.min_order_qty,
+		       i.id, i.name, i.unit
+		FROM supplier_ingredients si
+		JOIN ingredients i ON i.id = si.ingredient_id
+		WHERE si.supplier_id = $1
+		ORDER BY i.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var catalog []domain.SupplierIngredient
+	for rows.Next() {
+		item := domain.SupplierIngredient{Ingredient: &domain.Ingredient{}}
+		if err := rows.Scan(
+			&item.SupplierID, &item.IngredientID, &item.UnitPrice, &item.SKU, &item.MinOrderQty,
+			&item.Ingredient.ID, &item.Ingredient.Name, &item.Ingredient.Unit,
+		); err != nil {
+			return nil, err
+		}
+		catalog = append(catalog, item)
+	}
+
+	return catalog, rows.Err()
+}
+
+func (r *SupplierRepository) Create(ctx context.Context, s *domain.Supplier) error {
+	query := `
+		INSERT INTO suppliers (name, contact_name, phone, email, address, notes, lead_time_days, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		s.Name, s.ContactName, s.Phone, s.Email, s.Address, s.Notes, s.LeadTimeDays, s.IsActive,
+	).Scan(&s.ID, &s.CreatedAt)
+}
+
+func (r *SupplierRepository) Update(ctx context.Context, s *domain.Supplier) error {
+	query := `
+		UPDATE suppliers
+		SET name = $1, contact_name = $2, phone = $3, email = $4, address = $5, notes = $6,
+		    lead_time_days = $7, is_active = $8
+		WHERE id = $9`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		s.Name, s.ContactName, s.Phone, s.Email, s.Address, s.Notes, s.LeadTimeDays, s.IsActive, s.ID,
+	)
+	return err
+}
+
+// Delete only deactivates the supplier: its supplies and purchase orders stay
+func (r *SupplierRepository) Delete(ctx context.Context, id int) error {
+	query := `UPDATE suppliers SET is_active = FALSE WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *SupplierRepository) SaveCatalogItem(ctx context.Context, item *domain.SupplierIngredient) error {
+	query := `
+		INSERT INTO supplier_ingredients (supplier_id, ingredient_id, unit_price, sku, min_order_qty)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (supplier_id, ingredient_id) DO UPDATE
+		SET unit_price = EXCLUDED.unit_price, sku = EXCLUDED.sku, min_order_qty = EXCLUDED.min_order_qty`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		item.SupplierID, item.IngredientID, item.UnitPrice, item.SKU, item.MinOrderQty,
+	)
+	return err
+}
+
+func (r *SupplierRepository) DeleteCatalogItem(ctx context.Context, supplierID, ingredientID int) error {
+	query := `DELETE FROM supplier_ingredients WHERE supplier_id = $1 AND ingredient_id = $2`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, supplierID, ingredientID)
+	return err
+}
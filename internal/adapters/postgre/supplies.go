@@ -15,45 +15,31 @@ func NewSupplyRepository(db *sql.DB) *SupplyRepository {
 	return &SupplyRepository{db: db}
 }
 
+// Create only records the delivery; the stock is moved by the service
+// through the ledger in the same transaction
 func (r *SupplyRepository) Create(ctx context.Context, supply *domain.Supply) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	// Insert supply
 	query := `
-		INSERT INTO supplies (ingredient_id, qty, supplier_name)
-		VALUES ($1, $2, $3)
+		INSERT INTO supplies (ingredient_id, qty, supplier_name, supplier_id, purchase_order_id,
+		                      unit_price, currency, invoice_number, invoice_url, received_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id, created_at`
 
-	err = tx.QueryRowContext(ctx, query,
-		supply.IngredientID, supply.Qty, supply.SupplierName,
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		supply.IngredientID, supply.Qty, supply.SupplierName, supply.SupplierID, supply.PurchaseOrderID,
+		supply.UnitPrice, supply.Currency, supply.InvoiceNumber, supply.InvoiceURL, supply.ReceivedBy,
 	).Scan(&supply.ID, &supply.CreatedAt)
-	if err != nil {
-		return err
-	}
-
-	// Update ingredient quantity
-	updateQuery := `UPDATE ingredients SET qty = qty + $1 WHERE id = $2`
-	_, err = tx.ExecContext(ctx, updateQuery, supply.Qty, supply.IngredientID)
-	if err != nil {
-		return err
-	}
-
-	return tx.Commit()
 }
 
 func (r *SupplyRepository) GetAll(ctx context.Context) ([]domain.Supply, error) {
 	query := `
-		SELECT s.id, s.ingredient_id, s.qty, s.supplier_name, s.created_at,
+		SELECT s.id, s.ingredient_id, s.qty, s.supplier_name, s.supplier_id, s.purchase_order_id,
+		       s.unit_price, s.currency, s.invoice_number, s.invoice_url, s.received_by, s.created_at,
 		       i.name, i.unit
 		FROM supplies s
 		JOIN ingredients i ON s.ingredient_id = i.id
 		ORDER BY s.created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +51,8 @@ func (r *SupplyRepository) GetAll(ctx context.Context) ([]domain.Supply, error)
 		supply.Ingredient = &domain.Ingredient{}
 
 		if err := rows.Scan(
-			&supply.ID, &supply.IngredientID, &supply.Qty, &supply.SupplierName, &supply.CreatedAt,
+			&supply.ID, &supply.IngredientID, &supply.Qty, &supply.SupplierName, &supply.SupplierID, &supply.PurchaseOrderID,
+			&supply.UnitPrice, &supply.Currency, &supply.InvoiceNumber, &supply.InvoiceURL, &supply.ReceivedBy, &supply.CreatedAt,
 			&supply.Ingredient.Name, &supply.Ingredient.Unit,
 		); err != nil {
 			return nil, err
@@ -78,15 +65,17 @@ func (r *SupplyRepository) GetAll(ctx context.Context) ([]domain.Supply, error)
 
 func (r *SupplyRepository) GetByID(ctx context.Context, id int) (*domain.Supply, error) {
 	query := `
-		SELECT s.id, s.ingredient_id, s.qty, s.supplier_name, s.created_at,
+		SELECT s.id, s.ingredient_id, s.qty, s.supplier_name, s.supplier_id, s.purchase_order_id,
+		       s.unit_price, s.currency, s.invoice_number, s.invoice_url, s.received_by, s.created_at,
 		       i.name, i.unit
 		FROM supplies s
 		JOIN ingredients i ON s.ingredient_id = i.id
 		WHERE s.id = $1`
 
 	supply := &domain.Supply{Ingredient: &domain.Ingredient{}}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&supply.ID, &supply.IngredientID, &supply.Qty, &supply.SupplierName, &supply.CreatedAt,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&supply.ID, &supply.IngredientID, &supply.Qty, &supply.SupplierName, &supply.SupplierID, &supply.PurchaseOrderID,
+		&supply.UnitPrice, &supply.Currency, &supply.InvoiceNumber, &supply.InvoiceURL, &supply.ReceivedBy, &supply.CreatedAt,
 		&supply.Ingredient.Name, &supply.Ingredient.Unit,
 	)
 
@@ -98,12 +87,13 @@ func (r *SupplyRepository) GetByID(ctx context.Context, id int) (*domain.Supply,
 
 func (r *SupplyRepository) GetByIngredientID(ctx context.Context, ingredientID int) ([]domain.Supply, error) {
 	query := `
-		SELECT id, ingredient_id, qty, supplier_name, created_at
+		SELECT id, ingredient_id, qty, supplier_name, supplier_id, purchase_order_id,
+		       unit_price, currency, invoice_number, invoice_url, received_by, created_at
 		FROM supplies
 		WHERE ingredient_id = $1
 		ORDER BY created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, ingredientID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, ingredientID)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +103,8 @@ func (r *SupplyRepository) GetByIngredientID(ctx context.Context, ingredientID i
 	for rows.Next() {
 		var supply domain.Supply
 		if err := rows.Scan(
-			&supply.ID, &supply.IngredientID, &supply.Qty, &supply.SupplierName, &supply.CreatedAt,
+			&supply.ID, &supply.IngredientID, &supply.Qty, &supply.SupplierName, &supply.SupplierID, &supply.PurchaseOrderID,
+			&supply.UnitPrice, &supply.Currency, &supply.InvoiceNumber, &supply.InvoiceURL, &supply.ReceivedBy, &supply.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -122,3 +113,9 @@ func (r *SupplyRepository) GetByIngredientID(ctx context.Context, ingredientID i
 
 	return supplies, rows.Err()
 }
+
+func (r *SupplyRepository) SetInvoiceURL(ctx context.Context, id int, url string) error {
+	query := `UPDATE supplies SET invoice_url = $1 WHERE id = $2`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, url, id)
+	return err
+}
@@ -15,10 +15,20 @@ func NewTableRepository(db *sql.DB) *TableRepository {
 	return &TableRepository{db: db}
 }
 
+const tableColumns = `
+	t.id, t.name, t.capacity, t.status, t.zone, t.shape, t.pos_x, t.pos_y, t.width, t.height, t.rotation`
+
+func scanTable(row rowScanner, table *domain.Table, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&table.ID, &table.Name, &table.Capacity, &table.Status, &table.Zone, &table.Shape,
+		&table.PosX, &table.PosY, &table.Width, &table.Height, &table.Rotation,
+	}, extra...)...)
+}
+
 func (r *TableRepository) GetAll(ctx context.Context) ([]domain.Table, error) {
-	query := `SELECT id, name, status FROM tables ORDER BY name`
+	query := `SELECT ` + tableColumns + ` FROM tables t ORDER BY t.name`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +37,7 @@ func (r *TableRepository) GetAll(ctx context.Context) ([]domain.Table, error) {
 	var tables []domain.Table
 	for rows.Next() {
 		var table domain.Table
-		if err := rows.Scan(&table.ID, &table.Name, &table.Status); err != nil {
+		if err := scanTable(rows, &table); err != nil {
 			return nil, err
 		}
 		tables = append(tables, table)
@@ -37,10 +47,19 @@ func (r *TableRepository) GetAll(ctx context.Context) ([]domain.Table, error) {
 }
 
 func (r *TableRepository) GetByID(ctx context.Context, id int) (*domain.Table, error) {
-	query := `SELECT id, name, status FROM tables WHERE id = $1`
+	query := `SELECT ` + tableColumns + ` FROM tables t WHERE t.id = $1`
+	return r.getOne(ctx, query, id)
+}
 
+// GetByIDForUpdate locks the table row until the surrounding transaction ends
+func (r *TableRepository) GetByIDForUpdate(ctx context.Context, id int) (*domain.Table, error) {
+	query := `SELECT ` + tableColumns + ` FROM tables t WHERE t.id = $1 FOR UPDATE`
+	return r.getOne(ctx, query, id)
+}
+
+func (r *TableRepository) getOne(ctx context.Context, query string, id int) (*domain.Table, error) {
 	table := &domain.Table{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&table.ID, &table.Name, &table.Status)
+	err := scanTable(conn(ctx, r.db).QueryRowContext(ctx, query, id), table)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -48,19 +67,89 @@ func (r *TableRepository) GetByID(ctx context.Context, id int) (*domain.Table, e
 	return table, err
 }
 
+// GetFloor returns every table with the open order served at it, if any
+func (r *TableRepository) GetFloor(ctx context.Context) ([]domain.FloorTable, error) {
+	query := `
+		SELECT ` + tableColumns + `,
+			o.id, o.status, o.total, o.guests, o.waiter_id, u.username, o.created_at
+		FROM tables t
+		LEFT JOIN LATERAL (
+			SELECT id, status, total, guests, waiter_id, created_at
+			FROM orders
+			WHERE table_number = t.id AND status IN ('new', 'in_progress', 'ready')
+			ORDER BY created_at DESC
+			LIMIT 1
+		) o ON true
+		LEFT JOIN users u ON u.id = o.waiter_id
+		ORDER BY t.zone, t.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var floor []domain.FloorTable
+	for rows.Next() {
+		var ft domain.FloorTable
+		if err := scanTable(rows, &ft.Table,
+			&ft.OrderID, &ft.OrderStatus, &ft.OrderTotal, &ft.Guests, &ft.WaiterID, &ft.WaiterName, &ft.SeatedAt,
+		); err != nil {
+			return nil, err
+		}
+		floor = append(floor, ft)
+	}
+
+	return floor, rows.Err()
+}
+
 func (r *TableRepository) Create(ctx context.Context, table *domain.Table) error {
-	query := `INSERT INTO tables (name, status) VALUES ($1, $2) RETURNING id`
-	return r.db.QueryRowContext(ctx, query, table.Name, table.Status).Scan(&table.ID)
+	query := `
+		INSERT INTO tables (name, capacity, status, zone, shape, pos_x, pos_y, width, height, rotation)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		table.Name, table.Capacity, table.Status, table.Zone, table.Shape,
+		table.PosX, table.PosY, table.Width, table.Height, table.Rotation,
+	).Scan(&table.ID)
+}
+
+// Update changes the table details and layout; the status is changed only by UpdateStatus
+func (r *TableRepository) Update(ctx context.Context, table *domain.Table) error {
+	query := `
+		UPDATE tables
+		SET name = $1, capacity = $2, zone = $3, shape = $4, pos_x = $5, pos_y = $6,
+			width = $7, height = $8, rotation = $9
+		WHERE id = $10`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		table.Name, table.Capacity, table.Zone, table.Shape, table.PosX, table.PosY,
+		table.Width, table.Height, table.Rotation, table.ID,
+	)
+	return err
+}
+
+func (r *TableRepository) UpdateLayout(ctx context.Context, id int, layout domain.TableLayout) error {
+	query := `
+		UPDATE tables
+		SET zone = $1, shape = $2, pos_x = $3, pos_y = $4, width = $5, height = $6, rotation = $7
+		WHERE id = $8`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		layout.Zone, layout.Shape, layout.PosX, layout.PosY, layout.Width, layout.Height, layout.Rotation, id,
+	)
+	return err
 }
 
 func (r *TableRepository) UpdateStatus(ctx context.Context, id int, status domain.TableStatus) error {
 	query := `UPDATE tables SET status = $1 WHERE id = $2`
-	_, err := r.db.ExecContext(ctx, query, status, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, status, id)
 	return err
 }
 
 func (r *TableRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM tables WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	return err
 }
@@ -21,7 +21,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at`
 
-	return r.db.QueryRowContext(ctx, query,
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
 		user.Username, user.PasswordHash, user.Role, user.PhotoKey, user.IsActive,
 	).Scan(&user.ID, &user.CreatedAt)
 }
@@ -32,7 +32,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*domain.User, err
 		FROM users WHERE id = $1`
 
 	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&user.ID, &user.Username, &user.PasswordHash, &user.Role,
 		&user.PhotoKey, &user.IsActive, &user.CreatedAt,
 	)
@@ -49,7 +49,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 		FROM users WHERE username = $1`
 
 	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, username).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, username).Scan(
 		&user.ID, &user.Username, &user.PasswordHash, &user.Role,
 		&user.PhotoKey, &user.IsActive, &user.CreatedAt,
 	)
@@ -65,7 +65,7 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]domain.User, error) {
 		SELECT id, username, password_hash, role, photokey, is_active, created_at
 		FROM users ORDER BY created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +92,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 		SET username = $1, password_hash = $2, role = $3, photokey = $4, is_active = $5
 		WHERE id = $6`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		user.Username, user.PasswordHash, user.Role, user.PhotoKey, user.IsActive, user.ID,
 	)
 	return err
@@ -100,6 +100,6 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 
 func (r *UserRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	return err
 }
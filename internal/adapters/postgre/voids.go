@@ -0,0 +1,90 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type VoidRepository struct {
+	db *sql.DB
+}
+
+func NewVoidRepository(db *sql.DB) *VoidRepository {
+	return &VoidRepository{db: db}
+}
+
+const voidColumns = `
+	id, order_id, order_item_id, dish_id, qty, amount, reason_code, reason_text,
+	stock_action, status, voided_by, approved_by, created_at, resolved_at`
+
+func scanVoid(row rowScanner, v *domain.OrderVoid) error {
+	return row.Scan(
+		&v.ID, &v.OrderID, &v.OrderItemID, &v.DishID, &v.Qty, &v.Amount, &v.ReasonCode, &v.ReasonText,
+		&v.StockAction, &v.Status, &v.VoidedBy, &v.ApprovedBy, &v.CreatedAt, &v.ResolvedAt,
+	)
+}
+
+func (r *VoidRepository) Create(ctx context.Context, v *domain.OrderVoid) error {
+	query := `
+		INSERT INTO order_voids (
+			order_id, order_item_id, dish_id, qty, amount, reason_code, reason_text,
+			stock_action, status, voided_by, approved_by, resolved_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		v.OrderID, v.OrderItemID, v.DishID, v.Qty, v.Amount, v.ReasonCode, v.ReasonText,
+		v.StockAction, v.Status, v.VoidedBy, v.ApprovedBy, v.ResolvedAt,
+	).Scan(&v.ID, &v.CreatedAt)
+}
+
+func (r *VoidRepository) GetByID(ctx context.Context, id int) (*domain.OrderVoid, error) {
+	query := `SELECT ` + voidColumns + ` FROM order_voids WHERE id = $1`
+
+	v := &domain.OrderVoid{}
+	err := scanVoid(conn(ctx, r.db).QueryRowContext(ctx, query, id), v)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+func (r *VoidRepository) GetByOrderID(ctx context.Context, orderID int) ([]domain.OrderVoid, error) {
+	query := `SELECT ` + voidColumns + ` FROM order_voids WHERE order_id = $1 ORDER BY created_at`
+	return r.list(ctx, query, orderID)
+}
+
+func (r *VoidRepository) GetPending(ctx context.Context) ([]domain.OrderVoid, error) {
+	query := `SELECT ` + voidColumns + ` FROM order_voids WHERE status = 'pending' ORDER BY created_at`
+	return r.list(ctx, query)
+}
+
+func (r *VoidRepository) Resolve(ctx context.Context, id int, status domain.VoidStatus, approvedBy int) error {
+	query := `UPDATE order_voids SET status = $1, approved_by = $2, resolved_at = $3 WHERE id = $4`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, status, approvedBy, time.Now(), id)
+	return err
+}
+
+func (r *VoidRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.OrderVoid, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var voids []domain.OrderVoid
+	for rows.Next() {
+		var v domain.OrderVoid
+		if err := scanVoid(rows, &v); err != nil {
+			return nil, err
+		}
+		voids = append(voids, v)
+	}
+
+	return voids, rows.Err()
+}
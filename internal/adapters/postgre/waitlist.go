@@ -0,0 +1,115 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type WaitlistRepository struct {
+	db *sql.DB
+}
+
+func NewWaitlistRepository(db *sql.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+const waitlistColumns = `
+	w.id, w.guest_name, w.guest_phone, w.party_size, w.status, w.quoted_wait, w.table_id,
+	w.notes, w.created_by, w.notified_at, w.seated_at, w.created_at, w.updated_at`
+
+func scanWaitlistEntry(row rowScanner, e *domain.WaitlistEntry) error {
+	return row.Scan(
+		&e.ID, &e.GuestName, &e.GuestPhone, &e.PartySize, &e.Status, &e.QuotedWait, &e.TableID,
+		&e.Notes, &e.CreatedBy, &e.NotifiedAt, &e.SeatedAt, &e.CreatedAt, &e.UpdatedAt,
+	)
+}
+
+func (r *WaitlistRepository) Create(ctx context.Context, e *domain.WaitlistEntry) error {
+	query := `
+		INSERT INTO waitlist (guest_name, guest_phone, party_size, status, quoted_wait, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query,
+		e.GuestName, e.GuestPhone, e.PartySize, e.Status, e.QuotedWait, e.Notes, e.CreatedBy,
+	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+}
+
+func (r *WaitlistRepository) GetByID(ctx context.Context, id int) (*domain.WaitlistEntry, error) {
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist w WHERE w.id = $1`
+	return r.getOne(ctx, query, id)
+}
+
+// GetActive returns parties still in the queue, first come first
+func (r *WaitlistRepository) GetActive(ctx context.Context) ([]domain.WaitlistEntry, error) {
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist w
+		WHERE w.status IN ('waiting', 'notified')
+		ORDER BY w.created_at, w.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.WaitlistEntry
+	for rows.Next() {
+		var e domain.WaitlistEntry
+		if err := scanWaitlistEntry(rows, &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetNextFitting returns the earliest waiting party that fits at a table of the given capacity
+func (r *WaitlistRepository) GetNextFitting(ctx context.Context, capacity int) (*domain.WaitlistEntry, error) {
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist w
+		WHERE w.status = 'waiting' AND w.party_size <= $1
+		ORDER BY w.created_at, w.id
+		LIMIT 1
+		FOR UPDATE`
+	return r.getOne(ctx, query, capacity)
+}
+
+// GetNotifiedForTable returns the party a freed table is being held for, if any
+func (r *WaitlistRepository) GetNotifiedForTable(ctx context.Context, tableID int) (*domain.WaitlistEntry, error) {
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist w
+		WHERE w.status = 'notified' AND w.table_id = $1
+		LIMIT 1`
+	return r.getOne(ctx, query, tableID)
+}
+
+func (r *WaitlistRepository) getOne(ctx context.Context, query string, arg interface{}) (*domain.WaitlistEntry, error) {
+	e := &domain.WaitlistEntry{}
+	err := scanWaitlistEntry(conn(ctx, r.db).QueryRowContext(ctx, query, arg), e)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return e, err
+}
+
+// UpdateStatus moves the party along the queue; notified_at and seated_at are stamped on the way
+func (r *WaitlistRepository) UpdateStatus(ctx context.Context, id int, status domain.WaitlistStatus, tableID *int) error {
+	query := `
+		UPDATE waitlist
+		SET status = $1::varchar, table_id = $2, updated_at = $3,
+			notified_at = CASE WHEN $1::varchar = 'notified' THEN $3 ELSE notified_at END,
+			seated_at = CASE WHEN $1::varchar = 'seated' THEN $3 ELSE seated_at END
+		WHERE id = $4`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, status, tableID, time.Now(), id)
+	return err
+}
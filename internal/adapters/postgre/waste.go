@@ -0,0 +1,139 @@
+package postgre
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+type WasteRepository struct {
+	db *sql.DB
+}
+
+func NewWasteRepository(db *sql.DB) *WasteRepository {
+	return &WasteRepository{db: db}
+}
+
+const wasteColumns = `w.id, w.ingredient_id, w.dish_id, w.qty, w.reason, w.note, w.photo_url, w.cost,
+	w.user_id, u.username, w.created_at, COALESCE(i.name, d.name)`
+
+const wasteFrom = `
+	FROM waste_entries w
+	LEFT JOIN ingredients i ON i.id = w.ingredient_id
+	LEFT JOIN dishes d ON d.id = w.dish_id
+	LEFT JOIN users u ON u.id = w.user_id`
+
+func scanWaste(row rowScanner) (*domain.WasteEntry, error) {
+	w := &domain.WasteEntry{}
+	err := row.Scan(
+		&w.ID, &w.IngredientID, &w.DishID, &w.Qty, &w.Reason, &w.Note, &w.PhotoURL, &w.Cost,
+		&w.UserID, &w.UserName, &w.CreatedAt, &w.ItemName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *WasteRepository) Create(ctx context.Context, w *domain.WasteEntry) error {
+	query := `
+		INSERT INTO waste_entries (ingredient_id, dish_id, qty, reason, note, photo_url, cost, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		w.IngredientID, w.DishID, w.Qty, w.Reason, w.Note, w.PhotoURL, w.Cost, w.UserID,
+	).Scan(&w.ID, &w.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO waste_items (waste_id, ingredient_id, qty, unit_cost)
+		VALUES ($1, $2, $3, $4)`
+
+	for _, item := range w.Items {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, itemQuery, w.ID, item.IngredientID, item.Qty, item.UnitCost); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAll returns the waste log without items, newest first
+func (r *WasteRepository) GetAll(ctx context.Context, f domain.WasteFilter) ([]domain.WasteEntry, error) {
+	query := `SELECT ` + wasteColumns + wasteFrom + `
+		WHERE ($1::text IS NULL OR w.reason = $1)
+		  AND ($2::timestamp IS NULL OR w.created_at >= $2)
+		  AND ($3::timestamp IS NULL OR w.created_at < $3)
+		ORDER BY w.created_at DESC, w.id DESC
+		LIMIT $4`
+
+	var reason *string
+	if f.Reason != nil {
+		v := string(*f.Reason)
+		reason = &v
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, reason, f.From, f.To, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.WasteEntry
+	for rows.Next() {
+		w, err := scanWaste(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *w)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *WasteRepository) GetByID(ctx context.Context, id int) (*domain.WasteEntry, error) {
+	query := `SELECT ` + wasteColumns + wasteFrom + ` WHERE w.id = $1`
+
+	w, err := scanWaste(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	w.Items, err = r.getItems(ctx, w.ID)
+	return w, err
+}
+
+func (r *WasteRepository) getItems(ctx context.Context, wasteID int) ([]domain.WasteItem, error) {
+	query := `
+		SELECT wi.ingredient_id, wi.qty, wi.unit_cost, i.id, i.name, i.unit
+		FROM waste_items wi
+		JOIN ingredients i ON i.id = wi.ingredient_id
+		WHERE wi.waste_id = $1
+		ORDER BY i.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, wasteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.WasteItem
+	for rows.Next() {
+		item := domain.WasteItem{Ingredient: &domain.Ingredient{}}
+		if err := rows.Scan(
+			&item.IngredientID, &item.Qty, &item.UnitCost,
+			&item.Ingredient.ID, &item.Ingredient.Name, &item.Ingredient.Unit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
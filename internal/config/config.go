@@ -9,11 +9,14 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	MinIO    MinIOConfig
-	JWT      JWTConfig
-	Env      string
+	Server       ServerConfig
+	Database     DatabaseConfig
+	MinIO        MinIOConfig
+	JWT          JWTConfig
+	Pricing      PricingConfig
+	Reservations ReservationConfig
+	Waitlist     WaitlistConfig
+	Env          string
 }
 
 type ServerConfig struct {
@@ -44,6 +47,26 @@ type JWTConfig struct {
 	ExpirationHours int
 }
 
+// ReservationConfig holds booking timings, in minutes
+type ReservationConfig struct {
+	DurationMinutes   int // default length of a booking
+	HoldMinutes       int // table turns "reserve" this long before the slot
+	NoShowMinutes     int // booking is released if guests are this late
+	CheckIntervalSecs int
+}
+
+// WaitlistConfig holds how walk-in waits are estimated
+type WaitlistConfig struct {
+	LookbackDays       int // history used for table turn times
+	DefaultTurnMinutes int // turn time when there is no history yet
+}
+
+// PricingConfig holds the automatic service charge for large parties
+type PricingConfig struct {
+	ServiceChargeRate      float64 // percent, 0 disables it
+	ServiceChargeMinGuests int
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
 		Server: ServerConfig{
@@ -70,9 +93,27 @@ func Load() (*Config, error) {
 			Secret:          getEnv("JWT_SECRET", "change-me-in-production"),
 			ExpirationHours: getEnvInt("JWT_EXPIRATION_HOURS", 24),
 		},
+		Pricing: PricingConfig{
+			ServiceChargeRate:      getEnvFloat("SERVICE_CHARGE_RATE", 0),
+			ServiceChargeMinGuests: getEnvInt("SERVICE_CHARGE_MIN_GUESTS", 6),
+		},
+		Reservations: ReservationConfig{
+			DurationMinutes:   getEnvInt("RESERVATION_DURATION_MINUTES", 120),
+			HoldMinutes:       getEnvInt("RESERVATION_HOLD_MINUTES", 30),
+			NoShowMinutes:     getEnvInt("RESERVATION_NO_SHOW_MINUTES", 20),
+			CheckIntervalSecs: getEnvInt("RESERVATION_CHECK_INTERVAL_SECONDS", 60),
+		},
+		Waitlist: WaitlistConfig{
+			LookbackDays:       getEnvInt("WAITLIST_LOOKBACK_DAYS", 28),
+			DefaultTurnMinutes: getEnvInt("WAITLIST_DEFAULT_TURN_MINUTES", 60),
+		},
 		Env: getEnv("ENV", "development"),
 	}
 
+	if cfg.Pricing.ServiceChargeRate < 0 || cfg.Pricing.ServiceChargeRate > 100 {
+		return nil, fmt.Errorf("SERVICE_CHARGE_RATE must be between 0 and 100")
+	}
+
 	if cfg.JWT.Secret == "change-me-in-production" && cfg.Env == "production" {
 		return nil, fmt.Errorf("JWT_SECRET must be set in production")
 	}
@@ -91,6 +132,10 @@ func (c *JWTConfig) ExpirationDuration() time.Duration {
 	return time.Duration(c.ExpirationHours) * time.Hour
 }
 
+func (c *ReservationConfig) CheckInterval() time.Duration {
+	return time.Duration(c.CheckIntervalSecs) * time.Second
+}
+
 func getEnv(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -107,6 +152,15 @@ func getEnvInt(key string, defaultVal int) int {
 	return defaultVal
 }
 
+func getEnvFloat(key string, defaultVal float64) float64 {
+	if val := os.Getenv(key); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultVal
+}
+
 func getEnvBool(key string, defaultVal bool) bool {
 	if val := os.Getenv(key); val != "" {
 		if boolVal, err := strconv.ParseBool(val); err == nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
 	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
 )
 
 type AnalyticsHandler struct {
@@ -146,6 +147,57 @@ func (h *AnalyticsHandler) GetWaiterPerformance(w http.ResponseWriter, r *http.R
 	response.Success(w, performance)
 }
 
+// GetRevenueByPaymentMethod returns payments grouped by payment method
+func (h *AnalyticsHandler) GetRevenueByPaymentMethod(w http.ResponseWriter, r *http.Request) {
+	from, to, err := h.parseDateRange(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	methods, err := h.analyticsService.GetRevenueByPaymentMethod(r.Context(), from, to)
+	if err != nil {
+		response.InternalError(w, "failed to get revenue by payment method")
+		return
+	}
+
+	response.Success(w, methods)
+}
+
+// GetTipsByWaiter returns tips collected per waiter
+func (h *AnalyticsHandler) GetTipsByWaiter(w http.ResponseWriter, r *http.Request) {
+	from, to, err := h.parseDateRange(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	tips, err := h.analyticsService.GetTipsByWaiter(r.Context(), from, to)
+	if err != nil {
+		response.InternalError(w, "failed to get tips by waiter")
+		return
+	}
+
+	response.Success(w, tips)
+}
+
+// GetDiscountCost returns the revenue given away by promotions and comps
+func (h *AnalyticsHandler) GetDiscountCost(w http.ResponseWriter, r *http.Request) {
+	from, to, err := h.parseDateRange(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	costs, err := h.analyticsService.GetDiscountCost(r.Context(), from, to)
+	if err != nil {
+		response.InternalError(w, "failed to get discount cost")
+		return
+	}
+
+	response.Success(w, costs)
+}
+
 // GetOrderStats returns order statistics
 func (h *AnalyticsHandler) GetOrderStats(w http.ResponseWriter, r *http.Request) {
 	from, to, err := h.parseDateRange(r)
@@ -258,3 +310,112 @@ func (h *AnalyticsHandler) GetDishAvailability(w http.ResponseWriter, r *http.Re
 
 	response.Success(w, data)
 }
+
+// GetDishCosts returns recipe cost, food-cost % and gross margin per dish
+func (h *AnalyticsHandler) GetDishCosts(w http.ResponseWriter, r *http.Request) {
+	costs, err := h.analyticsService.GetDishCosts(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get dish costs")
+		return
+	}
+
+	response.Success(w, costs)
+}
+
+// GetCategoryMargins returns food-cost % and gross margin per category
+func (h *AnalyticsHandler) GetCategoryMargins(w http.ResponseWriter, r *http.Request) {
+	margins, err := h.analyticsService.GetCategoryMargins(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get category margins")
+		return
+	}
+
+	response.Success(w, margins)
+}
+
+// GetMenuEngineering returns stars, plowhorses, puzzles and dogs for the period
+func (h *AnalyticsHandler) GetMenuEngineering(w http.ResponseWriter, r *http.Request) {
+	from, to, err := h.parseDateRange(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	report, err := h.analyticsService.GetMenuEngineering(r.Context(), from, to)
+	if err != nil {
+		response.InternalError(w, "failed to get menu engineering report")
+		return
+	}
+
+	response.Success(w, report)
+}
+
+// GetWasteReport returns waste cost by reason, ingredient and day for the period
+func (h *AnalyticsHandler) GetWasteReport(w http.ResponseWriter, r *http.Request) {
+	from, to, err := h.parseDateRange(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	report, err := h.analyticsService.GetWasteReport(r.Context(), from, to)
+	if err != nil {
+		response.InternalError(w, "failed to get waste report")
+		return
+	}
+
+	response.Success(w, report)
+}
+
+// GetSpendReport returns supply spend by supplier, ingredient and day/week/month bucket
+func (h *AnalyticsHandler) GetSpendReport(w http.ResponseWriter, r *http.Request) {
+	from, to, err := h.parseDateRange(r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	period := domain.SpendByMonth
+	if v := r.URL.Query().Get("period"); v != "" {
+		period = domain.SpendPeriod(v)
+		if !period.IsValid() {
+			response.BadRequest(w, "invalid period, use day, week or month")
+			return
+		}
+	}
+
+	report, err := h.analyticsService.GetSpendReport(r.Context(), from, to, period)
+	if err != nil {
+		response.InternalError(w, "failed to get spend report")
+		return
+	}
+
+	response.Success(w, report)
+}
+
+// GetPriceHistory returns purchase prices of an ingredient, optionally of one supplier
+func (h *AnalyticsHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid ingredient id")
+		return
+	}
+
+	var supplierID *int
+	if v := r.URL.Query().Get("supplier_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			response.BadRequest(w, "invalid supplier_id")
+			return
+		}
+		supplierID = &id
+	}
+
+	history, err := h.analyticsService.GetPriceHistory(r.Context(), ingredientID, supplierID)
+	if err != nil {
+		response.InternalError(w, "failed to get price history")
+		return
+	}
+
+	response.Success(w, history)
+}
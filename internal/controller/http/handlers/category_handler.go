@@ -59,6 +59,10 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.categoryService.Create(r.Context(), &category); err != nil {
+		if err == domain.ErrInvalidRate {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalError(w, "failed to create category")
 		return
 	}
@@ -82,6 +86,10 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	category.ID = id
 	if err := h.categoryService.Update(r.Context(), &category); err != nil {
+		if err == domain.ErrInvalidRate {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		if err == domain.ErrCategoryNotFound {
 			response.NotFound(w, "category not found")
 			return
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type ComboHandler struct {
+	comboService ports.ComboService
+}
+
+func NewComboHandler(comboService ports.ComboService) *ComboHandler {
+	return &ComboHandler{comboService: comboService}
+}
+
+// GET /api/combos?active=true
+func (h *ComboHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	activeOnly := r.URL.Query().Get("active") == "true"
+
+	combos, err := h.comboService.GetAll(r.Context(), activeOnly)
+	if err != nil {
+		response.InternalError(w, "failed to get combos")
+		return
+	}
+
+	response.Success(w, combos)
+}
+
+func (h *ComboHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid combo id")
+		return
+	}
+
+	combo, err := h.comboService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writeComboError(w, err, "failed to get combo")
+		return
+	}
+
+	response.Success(w, combo)
+}
+
+func (h *ComboHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var combo domain.Combo
+	if err := json.NewDecoder(r.Body).Decode(&combo); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.comboService.Create(r.Context(), &combo); err != nil {
+		h.writeComboError(w, err, "failed to create combo")
+		return
+	}
+
+	response.Created(w, combo)
+}
+
+func (h *ComboHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid combo id")
+		return
+	}
+
+	var combo domain.Combo
+	if err := json.NewDecoder(r.Body).Decode(&combo); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	combo.ID = id
+	if err := h.comboService.Update(r.Context(), &combo); err != nil {
+		h.writeComboError(w, err, "failed to update combo")
+		return
+	}
+
+	response.Success(w, combo)
+}
+
+func (h *ComboHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid combo id")
+		return
+	}
+
+	if err := h.comboService.Delete(r.Context(), id); err != nil {
+		h.writeComboError(w, err, "failed to delete combo")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "combo removed from sale"})
+}
+
+func (h *ComboHandler) writeComboError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrComboNotFound:
+		response.NotFound(w, "combo not found")
+	case domain.ErrDishNotFound:
+		response.BadRequest(w, "dish not found")
+	case domain.ErrInvalidCombo:
+		response.BadRequest(w, "combo needs a name, a price and slots with at least one dish each")
+	default:
+		response.InternalError(w, fallback)
+	}
+}
@@ -61,6 +61,10 @@ func (h *DishHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.dishService.Create(r.Context(), &dish); err != nil {
+		if err == domain.ErrInvalidRate {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalError(w, "failed to create dish")
 		return
 	}
@@ -84,6 +88,10 @@ func (h *DishHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	dish.ID = id
 	if err := h.dishService.Update(r.Context(), &dish); err != nil {
+		if err == domain.ErrInvalidRate {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		if err == domain.ErrDishNotFound {
 			response.NotFound(w, "dish not found")
 			return
@@ -114,6 +122,40 @@ func (h *DishHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, map[string]string{"message": "dish deleted"})
 }
 
+// PUT /api/dishes/{id}/sold-out {"sold_out": true} — повар снимает блюдо (86) или возвращает его
+func (h *DishHandler) SetSoldOut(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+
+	var req struct {
+		SoldOut bool `json:"sold_out"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.dishService.SetSoldOut(r.Context(), id, req.SoldOut); err != nil {
+		if err == domain.ErrDishNotFound {
+			response.NotFound(w, "dish not found")
+			return
+		}
+		response.InternalError(w, "failed to update dish availability")
+		return
+	}
+
+	dish, err := h.dishService.GetByID(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "failed to get dish")
+		return
+	}
+
+	response.Success(w, dish)
+}
+
 func (h *DishHandler) GetIngredients(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
@@ -130,3 +172,198 @@ func (h *DishHandler) GetIngredients(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, ingredients)
 }
+
+// GET /api/dishes/{id}/modifiers
+func (h *DishHandler) GetModifiers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+
+	groups, err := h.dishService.GetModifiers(r.Context(), id)
+	if err != nil {
+		h.writeModifierError(w, err, "failed to get dish modifiers")
+		return
+	}
+
+	response.Success(w, groups)
+}
+
+// POST /api/dishes/{id}/modifiers — группа вместе с вариантами
+func (h *DishHandler) CreateModifierGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+
+	var group domain.ModifierGroup
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	group.DishID = id
+	if err := h.dishService.CreateModifierGroup(r.Context(), &group); err != nil {
+		h.writeModifierError(w, err, "failed to create modifier group")
+		return
+	}
+
+	response.Created(w, group)
+}
+
+// PUT /api/dishes/{id}/modifiers/{groupId}
+func (h *DishHandler) UpdateModifierGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+	groupID, err := strconv.Atoi(chi.URLParam(r, "groupId"))
+	if err != nil {
+		response.BadRequest(w, "invalid modifier group id")
+		return
+	}
+
+	var group domain.ModifierGroup
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	group.ID = groupID
+	group.DishID = id
+	if err := h.dishService.UpdateModifierGroup(r.Context(), &group); err != nil {
+		h.writeModifierError(w, err, "failed to update modifier group")
+		return
+	}
+
+	response.Success(w, group)
+}
+
+// DELETE /api/dishes/{id}/modifiers/{groupId}
+func (h *DishHandler) DeleteModifierGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+	groupID, err := strconv.Atoi(chi.URLParam(r, "groupId"))
+	if err != nil {
+		response.BadRequest(w, "invalid modifier group id")
+		return
+	}
+
+	if err := h.dishService.DeleteModifierGroup(r.Context(), id, groupID); err != nil {
+		h.writeModifierError(w, err, "failed to delete modifier group")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "modifier group deleted"})
+}
+
+// POST /api/dishes/{id}/modifiers/{groupId}/options
+func (h *DishHandler) CreateModifierOption(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+	groupID, err := strconv.Atoi(chi.URLParam(r, "groupId"))
+	if err != nil {
+		response.BadRequest(w, "invalid modifier group id")
+		return
+	}
+
+	var option domain.ModifierOption
+	if err := json.NewDecoder(r.Body).Decode(&option); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	option.GroupID = groupID
+	if err := h.dishService.CreateModifierOption(r.Context(), id, &option); err != nil {
+		h.writeModifierError(w, err, "failed to create modifier option")
+		return
+	}
+
+	response.Created(w, option)
+}
+
+// PUT /api/dishes/{id}/modifiers/{groupId}/options/{optionId}
+func (h *DishHandler) UpdateModifierOption(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+	groupID, err := strconv.Atoi(chi.URLParam(r, "groupId"))
+	if err != nil {
+		response.BadRequest(w, "invalid modifier group id")
+		return
+	}
+	optionID, err := strconv.Atoi(chi.URLParam(r, "optionId"))
+	if err != nil {
+		response.BadRequest(w, "invalid modifier option id")
+		return
+	}
+
+	var option domain.ModifierOption
+	if err := json.NewDecoder(r.Body).Decode(&option); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	option.ID = optionID
+	option.GroupID = groupID
+	if err := h.dishService.UpdateModifierOption(r.Context(), id, &option); err != nil {
+		h.writeModifierError(w, err, "failed to update modifier option")
+		return
+	}
+
+	response.Success(w, option)
+}
+
+// DELETE /api/dishes/{id}/modifiers/{groupId}/options/{optionId}
+func (h *DishHandler) DeleteModifierOption(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+	groupID, err := strconv.Atoi(chi.URLParam(r, "groupId"))
+	if err != nil {
+		response.BadRequest(w, "invalid modifier group id")
+		return
+	}
+	optionID, err := strconv.Atoi(chi.URLParam(r, "optionId"))
+	if err != nil {
+		response.BadRequest(w, "invalid modifier option id")
+		return
+	}
+
+	if err := h.dishService.DeleteModifierOption(r.Context(), id, groupID, optionID); err != nil {
+		h.writeModifierError(w, err, "failed to delete modifier option")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "modifier option withdrawn"})
+}
+
+func (h *DishHandler) writeModifierError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrDishNotFound:
+		response.NotFound(w, "dish not found")
+	case domain.ErrModifierGroupNotFound:
+		response.NotFound(w, "modifier group not found")
+	case domain.ErrModifierOptionNotFound:
+		response.NotFound(w, "modifier option not found")
+	case domain.ErrIngredientNotFound:
+		response.BadRequest(w, "ingredient not found")
+	case domain.ErrInvalidModifier:
+		response.BadRequest(w, "name is required, max_select must not be below min_select, ingredient changes must be non-zero and unique")
+	default:
+		response.InternalError(w, fallback)
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+)
+
+// heartbeatInterval keeps idle SSE connections open through proxies
+const heartbeatInterval = 25 * time.Second
+
+type EventHandler struct {
+	subscriber ports.EventSubscriber
+}
+
+func NewEventHandler(subscriber ports.EventSubscriber) *EventHandler {
+	return &EventHandler{subscriber: subscriber}
+}
+
+// GET /api/events
+// Server-Sent Events stream filtered by the caller's role
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+	role, _ := r.Context().Value(middleware.UserRoleKey).(domain.Role)
+
+	rc := http.NewResponseController(w)
+	// Поток живёт дольше WriteTimeout сервера
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	events, unsubscribe := h.subscriber.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, ": connected\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			if err := rc.Flush(); err != nil {
+				return
+			}
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !event.VisibleTo(role, userID) {
+				continue
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
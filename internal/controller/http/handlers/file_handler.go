@@ -2,7 +2,10 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -41,33 +44,12 @@ func (h *FileHandler) UploadDishPhoto(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
-	contentType := header.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
-
 	// Разрешаем только картинки
-	if !strings.HasPrefix(contentType, "image/") {
+	url, err := uploadFile(r.Context(), h.storage, h.bucketName, "dishes", file, header, "image/")
+	if err == errUnsupportedFile {
 		response.BadRequest(w, "only image uploads are allowed")
 		return
 	}
-
-	ext := filepath.Ext(header.Filename)
-	if ext == "" {
-		ext = ".jpg"
-	}
-
-	// Имя файла: dishes/<uuid>.<ext>
-	filename := fmt.Sprintf("dishes/%s%s", uuid.New().String(), ext)
-
-	url, err := h.storage.Upload(
-		r.Context(),
-		h.bucketName,
-		filename,
-		file,
-		header.Size,
-		contentType,
-	)
 	if err != nil {
 		response.InternalError(w, "failed to upload file")
 		return
@@ -78,3 +60,40 @@ func (h *FileHandler) UploadDishPhoto(w http.ResponseWriter, r *http.Request) {
 		"url": url,
 	})
 }
+
+var errUnsupportedFile = errors.New("unsupported file type")
+
+// uploadFile кладёт файл в хранилище как <folder>/<uuid>.<ext> и возвращает
+// его URL. accept — разрешённые префиксы Content-Type ("image/", "application/pdf").
+func uploadFile(
+	ctx context.Context,
+	storage ports.FileStorage,
+	bucket, folder string,
+	file multipart.File,
+	header *multipart.FileHeader,
+	accept ...string,
+) (string, error) {
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	allowed := false
+	for _, prefix := range accept {
+		if strings.HasPrefix(contentType, prefix) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", errUnsupportedFile
+	}
+
+	ext := filepath.Ext(header.Filename)
+	if ext == "" {
+		ext = ".jpg"
+	}
+
+	filename := fmt.Sprintf("%s/%s%s", folder, uuid.New().String(), ext)
+	return storage.Upload(ctx, bucket, filename, file, header.Size, contentType)
+}
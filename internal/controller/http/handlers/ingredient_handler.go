@@ -3,9 +3,10 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-
 	"strconv"
+	"time"
 
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
 	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
@@ -63,13 +64,19 @@ func (h *IngredientHandler) GetLowStock(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *IngredientHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
 	var ingredient domain.Ingredient
 	if err := json.NewDecoder(r.Body).Decode(&ingredient); err != nil {
 		response.BadRequest(w, "invalid request body")
 		return
 	}
 
-	if err := h.ingredientService.Create(r.Context(), &ingredient); err != nil {
+	if err := h.ingredientService.Create(r.Context(), &ingredient, userID); err != nil {
 		response.InternalError(w, "failed to create ingredient")
 		return
 	}
@@ -78,6 +85,12 @@ func (h *IngredientHandler) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *IngredientHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -92,7 +105,11 @@ func (h *IngredientHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ingredient.ID = id
-	if err := h.ingredientService.Update(r.Context(), &ingredient); err != nil {
+	if err := h.ingredientService.Update(r.Context(), &ingredient, userID); err != nil {
+		if err == domain.ErrIngredientNotFound {
+			response.NotFound(w, "ingredient not found")
+			return
+		}
 		response.InternalError(w, "failed to update ingredient")
 		return
 	}
@@ -109,9 +126,81 @@ func (h *IngredientHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.ingredientService.Delete(r.Context(), id); err != nil {
-		response.InternalError(w, "failed to delete ingredient")
+		switch err {
+		case domain.ErrIngredientNotFound:
+			response.NotFound(w, "ingredient not found")
+		case domain.ErrIngredientInUse:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.InternalError(w, "failed to delete ingredient")
+		}
 		return
 	}
 
 	response.Success(w, map[string]string{"message": "ingredient deleted"})
 }
+
+// GET /api/ingredients/movements?ingredient_id=&type=&from=2025-01-01&to=2025-01-31&limit=100
+// and GET /api/ingredients/{id}/movements — the stock ledger, newest first
+func (h *IngredientHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var filter domain.StockMovementFilter
+
+	idStr := chi.URLParam(r, "id")
+	if idStr == "" {
+		idStr = q.Get("ingredient_id")
+	}
+	if idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			response.BadRequest(w, "invalid ingredient id")
+			return
+		}
+		filter.IngredientID = &id
+	}
+
+	if t := q.Get("type"); t != "" {
+		movementType := domain.StockMovementType(t)
+		if !movementType.IsValid() {
+			response.BadRequest(w, "invalid movement type")
+			return
+		}
+		filter.Type = &movementType
+	}
+
+	if fromStr := q.Get("from"); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			response.BadRequest(w, "invalid from date, use YYYY-MM-DD")
+			return
+		}
+		filter.From = &from
+	}
+	if toStr := q.Get("to"); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			response.BadRequest(w, "invalid to date, use YYYY-MM-DD")
+			return
+		}
+		// включаем весь день
+		to = to.Add(24 * time.Hour)
+		filter.To = &to
+	}
+
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			response.BadRequest(w, "invalid limit parameter")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	movements, err := h.ingredientService.GetMovements(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "failed to get stock movements")
+		return
+	}
+
+	response.Success(w, movements)
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type MenuHandler struct {
+	menuService ports.MenuService
+}
+
+func NewMenuHandler(menuService ports.MenuService) *MenuHandler {
+	return &MenuHandler{menuService: menuService}
+}
+
+func (h *MenuHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	menus, err := h.menuService.GetAll(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get menus")
+		return
+	}
+
+	response.Success(w, menus)
+}
+
+// GET /api/menus/current — меню, открытые в эту минуту
+func (h *MenuHandler) GetCurrent(w http.ResponseWriter, r *http.Request) {
+	menus, err := h.menuService.GetCurrent(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get current menus")
+		return
+	}
+
+	response.Success(w, menus)
+}
+
+func (h *MenuHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid menu id")
+		return
+	}
+
+	menu, err := h.menuService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writeMenuError(w, err, "failed to get menu")
+		return
+	}
+
+	response.Success(w, menu)
+}
+
+func (h *MenuHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var menu domain.Menu
+	if err := json.NewDecoder(r.Body).Decode(&menu); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.menuService.Create(r.Context(), &menu); err != nil {
+		h.writeMenuError(w, err, "failed to create menu")
+		return
+	}
+
+	response.Created(w, menu)
+}
+
+func (h *MenuHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid menu id")
+		return
+	}
+
+	var menu domain.Menu
+	if err := json.NewDecoder(r.Body).Decode(&menu); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	menu.ID = id
+	if err := h.menuService.Update(r.Context(), &menu); err != nil {
+		h.writeMenuError(w, err, "failed to update menu")
+		return
+	}
+
+	response.Success(w, menu)
+}
+
+func (h *MenuHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid menu id")
+		return
+	}
+
+	if err := h.menuService.Delete(r.Context(), id); err != nil {
+		h.writeMenuError(w, err, "failed to delete menu")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "menu deleted"})
+}
+
+// PUT /api/menus/{id}/dishes/{dishId} {"price": 1500}; без цены — обычная цена блюда
+func (h *MenuHandler) SetDish(w http.ResponseWriter, r *http.Request) {
+	menuID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid menu id")
+		return
+	}
+	dishID, err := strconv.Atoi(chi.URLParam(r, "dishId"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+
+	var req struct {
+		Price *float64 `json:"price"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	menuDish := &domain.MenuDish{MenuID: menuID, DishID: dishID, Price: req.Price}
+	if err := h.menuService.SetDish(r.Context(), menuDish); err != nil {
+		h.writeMenuError(w, err, "failed to put dish on menu")
+		return
+	}
+
+	response.Success(w, menuDish)
+}
+
+func (h *MenuHandler) RemoveDish(w http.ResponseWriter, r *http.Request) {
+	menuID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid menu id")
+		return
+	}
+	dishID, err := strconv.Atoi(chi.URLParam(r, "dishId"))
+	if err != nil {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+
+	if err := h.menuService.RemoveDish(r.Context(), menuID, dishID); err != nil {
+		h.writeMenuError(w, err, "failed to remove dish from menu")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "dish removed from menu"})
+}
+
+func (h *MenuHandler) writeMenuError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrMenuNotFound:
+		response.NotFound(w, "menu not found")
+	case domain.ErrDishNotFound:
+		response.NotFound(w, "dish not found")
+	case domain.ErrInvalidMenu:
+		response.BadRequest(w, "menu needs a name, weekdays 0-6, an HH:MM window and a valid price")
+	default:
+		response.InternalError(w, fallback)
+	}
+}
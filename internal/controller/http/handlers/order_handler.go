@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
@@ -22,14 +25,89 @@ func NewOrderHandler(orderService ports.OrderService) *OrderHandler {
 
 type CreateOrderRequest struct {
 	TableNumber int                      `json:"table_number"`
+	Guests      *int                     `json:"guests"`
 	Notes       *string                  `json:"notes"`
+	PromoCode   *string                  `json:"promo_code"`
 	Items       []CreateOrderItemRequest `json:"items"`
+	Combos      []OrderComboRequest      `json:"combos"`
 }
 
 type CreateOrderItemRequest struct {
-	DishID int     `json:"dish_id"`
-	Qty    int     `json:"qty"`
-	Notes  *string `json:"notes"`
+	DishID    int     `json:"dish_id"`
+	Qty       int     `json:"qty"`
+	Notes     *string `json:"notes"`
+	Seat      *int    `json:"seat"`
+	Modifiers []int   `json:"modifiers"` // IDs of the chosen modifier options
+}
+
+func (req *CreateOrderItemRequest) toDomain() domain.OrderItem {
+	item := domain.OrderItem{
+		DishID: req.DishID,
+		Qty:    req.Qty,
+		Notes:  req.Notes,
+		Seat:   req.Seat,
+	}
+	for i := range req.Modifiers {
+		item.Modifiers = append(item.Modifiers, domain.OrderItemModifier{OptionID: &req.Modifiers[i]})
+	}
+	return item
+}
+
+// OrderComboRequest — комбо одной строкой: блюдо на каждый слот набора
+type OrderComboRequest struct {
+	ComboID int                  `json:"combo_id"`
+	Qty     int                  `json:"qty"`
+	Seat    *int                 `json:"seat"`
+	Choices []ComboChoiceRequest `json:"choices"`
+}
+
+type ComboChoiceRequest struct {
+	SlotID    int     `json:"slot_id"`
+	DishID    int     `json:"dish_id"`
+	Notes     *string `json:"notes"`
+	Modifiers []int   `json:"modifiers"` // IDs of the chosen modifier options
+}
+
+func (req *OrderComboRequest) toDomain() domain.OrderCombo {
+	line := domain.OrderCombo{
+		ComboID: &req.ComboID,
+		Qty:     req.Qty,
+		Seat:    req.Seat,
+	}
+	for _, c := range req.Choices {
+		sel := domain.ComboSelection{SlotID: c.SlotID, DishID: c.DishID, Notes: c.Notes}
+		for i := range c.Modifiers {
+			sel.Modifiers = append(sel.Modifiers, domain.OrderItemModifier{OptionID: &c.Modifiers[i]})
+		}
+		line.Choices = append(line.Choices, sel)
+	}
+	return line
+}
+
+// validate checks the request fields the service cannot default
+func (req *OrderComboRequest) validate() string {
+	if req.ComboID <= 0 {
+		return "invalid combo id"
+	}
+	if req.Qty <= 0 {
+		return "combo quantity must be greater than 0"
+	}
+	if req.Seat != nil && *req.Seat <= 0 {
+		return "seat must be greater than 0"
+	}
+	return ""
+}
+
+// CloseOrderRequest описывает оплату всего счёта; пустое тело — оплата наличными без чаевых
+type CloseOrderRequest struct {
+	Method   domain.PaymentMethod `json:"method"`
+	Tip      float64              `json:"tip"`
+	Tendered float64              `json:"tendered"`
+}
+
+type UpdateOrderItemRequest struct {
+	Qty   int     `json:"qty"`
+	Notes *string `json:"notes"`
 }
 
 func (h *OrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
@@ -89,15 +167,22 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(req.Items) == 0 {
+	if len(req.Items) == 0 && len(req.Combos) == 0 {
 		response.BadRequest(w, "order must have at least one item")
 		return
 	}
 
+	if req.Guests != nil && *req.Guests <= 0 {
+		response.BadRequest(w, "guests must be greater than 0")
+		return
+	}
+
 	order := &domain.Order{
 		WaiterID:    waiterID,
 		TableNumber: req.TableNumber,
+		Guests:      req.Guests,
 		Notes:       req.Notes,
+		PromoCode:   req.PromoCode,
 	}
 
 	var items []domain.OrderItem
@@ -106,14 +191,23 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 			response.BadRequest(w, "item quantity must be greater than 0")
 			return
 		}
-		items = append(items, domain.OrderItem{
-			DishID: itemReq.DishID,
-			Qty:    itemReq.Qty,
-			Notes:  itemReq.Notes,
-		})
+		if itemReq.Seat != nil && *itemReq.Seat <= 0 {
+			response.BadRequest(w, "seat must be greater than 0")
+			return
+		}
+		items = append(items, itemReq.toDomain())
 	}
 
-	if err := h.orderService.Create(r.Context(), order, items); err != nil {
+	var combos []domain.OrderCombo
+	for _, comboReq := range req.Combos {
+		if msg := comboReq.validate(); msg != "" {
+			response.BadRequest(w, msg)
+			return
+		}
+		combos = append(combos, comboReq.toDomain())
+	}
+
+	if err := h.orderService.Create(r.Context(), order, items, combos); err != nil {
 		if err == domain.ErrInsufficientStock {
 			response.BadRequest(w, "insufficient stock for order")
 			return
@@ -122,6 +216,26 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 			response.BadRequest(w, "table not found")
 			return
 		}
+		if err == domain.ErrInvalidPromoCode {
+			response.BadRequest(w, "promo code is invalid or not active")
+			return
+		}
+		if err == domain.ErrInvalidModifierSelection || err == domain.ErrInvalidComboChoice {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		if err == domain.ErrComboNotFound {
+			response.BadRequest(w, "combo not found")
+			return
+		}
+		if err == domain.ErrDishNotAvailable || err == domain.ErrDishSoldOut {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		if err == domain.ErrNotYourTable || err == domain.ErrNotYourOrder {
+			response.Forbidden(w, err.Error())
+			return
+		}
 		response.InternalError(w, "failed to create order")
 		return
 	}
@@ -152,12 +266,11 @@ func (h *OrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Валидация статуса
+	// Валидация статуса; paid ставится только оплатой (/close или /payments)
 	validStatuses := map[domain.OrderStatus]bool{
 		domain.OrderNew:        true,
 		domain.OrderInProgress: true,
 		domain.OrderReady:      true,
-		domain.OrderPaid:       true,
 	}
 
 	if !validStatuses[req.Status] {
@@ -178,6 +291,10 @@ func (h *OrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 			response.BadRequest(w, "insufficient stock to start cooking this order")
 			return
 		}
+		if err == domain.ErrNotYourTable || err == domain.ErrNotYourOrder {
+			response.Forbidden(w, err.Error())
+			return
+		}
 		response.InternalError(w, "failed to update order status")
 		return
 	}
@@ -193,6 +310,12 @@ func (h *OrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *OrderHandler) CloseOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -200,16 +323,57 @@ func (h *OrderHandler) CloseOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.orderService.CloseOrder(r.Context(), id); err != nil {
+	req := CloseOrderRequest{Method: domain.PaymentCash}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	if !req.Method.IsValid() {
+		response.BadRequest(w, "invalid payment method")
+		return
+	}
+	if req.Tip < 0 || req.Tendered < 0 {
+		response.BadRequest(w, "payment amounts must not be negative")
+		return
+	}
+
+	payment := &domain.Payment{
+		OrderID:   id,
+		Method:    req.Method,
+		Tip:       req.Tip,
+		Tendered:  req.Tendered,
+		CreatedBy: userID,
+	}
+
+	if err := h.orderService.CloseOrder(r.Context(), payment); err != nil {
 		if err == domain.ErrOrderNotFound {
 			response.NotFound(w, "order not found")
 			return
 		}
+		if err == domain.ErrOrderNotReady {
+			response.BadRequest(w, "order must be in ready status to close")
+			return
+		}
+		if err == domain.ErrOutstandingBalance {
+			response.BadRequest(w, "order is being paid in parts, pay the remaining balance instead")
+			return
+		}
+		if err == domain.ErrInsufficientTender {
+			response.BadRequest(w, "tendered amount is less than payment amount")
+			return
+		}
+		if err == domain.ErrNotYourTable || err == domain.ErrNotYourOrder {
+			response.Forbidden(w, err.Error())
+			return
+		}
 		response.InternalError(w, "failed to close order")
 		return
 	}
 
-	response.Success(w, map[string]string{"message": "order closed successfully"})
+	response.Success(w, map[string]interface{}{
+		"message": "order closed successfully",
+		"payment": payment,
+	})
 }
 
 func (h *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -231,3 +395,652 @@ func (h *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, map[string]string{"message": "order deleted successfully"})
 }
+
+func (h *OrderHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req CreateOrderItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if req.DishID <= 0 {
+		response.BadRequest(w, "invalid dish id")
+		return
+	}
+	if req.Qty <= 0 {
+		response.BadRequest(w, "item quantity must be greater than 0")
+		return
+	}
+	if req.Seat != nil && *req.Seat <= 0 {
+		response.BadRequest(w, "seat must be greater than 0")
+		return
+	}
+
+	item := req.toDomain()
+	if err := h.orderService.AddItem(r.Context(), id, &item); err != nil {
+		h.writeItemError(w, err, "failed to add order item")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusCreated)
+}
+
+func (h *OrderHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+	itemID, err := strconv.Atoi(chi.URLParam(r, "itemId"))
+	if err != nil {
+		response.BadRequest(w, "invalid item id")
+		return
+	}
+
+	var req UpdateOrderItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if req.Qty <= 0 {
+		response.BadRequest(w, "item quantity must be greater than 0")
+		return
+	}
+
+	item := &domain.OrderItem{
+		ID:    itemID,
+		Qty:   req.Qty,
+		Notes: req.Notes,
+	}
+
+	if err := h.orderService.UpdateItem(r.Context(), id, item); err != nil {
+		h.writeItemError(w, err, "failed to update order item")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+func (h *OrderHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+	itemID, err := strconv.Atoi(chi.URLParam(r, "itemId"))
+	if err != nil {
+		response.BadRequest(w, "invalid item id")
+		return
+	}
+
+	if err := h.orderService.RemoveItem(r.Context(), id, itemID); err != nil {
+		h.writeItemError(w, err, "failed to remove order item")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+// POST /api/orders/{id}/combos
+func (h *OrderHandler) AddCombo(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req OrderComboRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		response.BadRequest(w, msg)
+		return
+	}
+
+	line := req.toDomain()
+	if err := h.orderService.AddCombo(r.Context(), id, &line); err != nil {
+		h.writeItemError(w, err, "failed to add combo")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusCreated)
+}
+
+// DELETE /api/orders/{id}/combos/{lineId}
+func (h *OrderHandler) RemoveCombo(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+	lineID, err := strconv.Atoi(chi.URLParam(r, "lineId"))
+	if err != nil {
+		response.BadRequest(w, "invalid combo line id")
+		return
+	}
+
+	if err := h.orderService.RemoveCombo(r.Context(), id, lineID); err != nil {
+		h.writeItemError(w, err, "failed to remove combo")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+// writeItemError maps order item errors to HTTP responses
+func (h *OrderHandler) writeItemError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrOrderNotFound:
+		response.NotFound(w, "order not found")
+	case domain.ErrOrderItemNotFound:
+		response.NotFound(w, "order item not found")
+	case domain.ErrComboLineNotFound:
+		response.NotFound(w, "combo line not found")
+	case domain.ErrDishNotFound:
+		response.BadRequest(w, "dish not found")
+	case domain.ErrComboNotFound:
+		response.BadRequest(w, "combo not found")
+	case domain.ErrOrderNotEditable:
+		response.BadRequest(w, "order can no longer be edited")
+	case domain.ErrLastOrderItem:
+		response.BadRequest(w, "order must have at least one item")
+	case domain.ErrBillHasPayments:
+		response.BadRequest(w, "order items cannot be changed after the first payment")
+	case domain.ErrInsufficientStock:
+		response.BadRequest(w, "insufficient stock for order")
+	case domain.ErrInvalidModifierSelection, domain.ErrInvalidComboChoice, domain.ErrComboItemNotEditable,
+		domain.ErrDishNotAvailable, domain.ErrDishSoldOut:
+		response.BadRequest(w, err.Error())
+	case domain.ErrNotYourTable, domain.ErrNotYourOrder:
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
+
+// respondWithOrder reloads the order with its items after a change
+func (h *OrderHandler) respondWithOrder(w http.ResponseWriter, r *http.Request, id int, statusCode int) {
+	order, err := h.orderService.GetByID(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "order updated but failed to retrieve details")
+		return
+	}
+
+	response.JSON(w, statusCode, order)
+}
+
+func (h *OrderHandler) UpdateItemStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+	itemID, err := strconv.Atoi(chi.URLParam(r, "itemId"))
+	if err != nil {
+		response.BadRequest(w, "invalid item id")
+		return
+	}
+
+	var req struct {
+		Status domain.OrderItemStatus `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	validStatuses := map[domain.OrderItemStatus]bool{
+		domain.ItemQueued:  true,
+		domain.ItemCooking: true,
+		domain.ItemDone:    true,
+		domain.ItemServed:  true,
+	}
+
+	if !validStatuses[req.Status] {
+		response.BadRequest(w, "invalid item status")
+		return
+	}
+	// Официант только отмечает подачу; готовку ведёт кухня
+	role, _ := r.Context().Value(middleware.UserRoleKey).(domain.Role)
+	if role == domain.RoleWaiter && req.Status != domain.ItemServed {
+		response.Forbidden(w, "waiters can only mark items as served")
+		return
+	}
+
+	if err := h.orderService.UpdateItemStatus(r.Context(), id, itemID, req.Status); err != nil {
+		if err == domain.ErrInvalidStatusChange {
+			response.BadRequest(w, "invalid status change")
+			return
+		}
+		h.writeItemError(w, err, "failed to update item status")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+// GetKitchenQueue returns queued and cooking items grouped by station
+func (h *OrderHandler) GetKitchenQueue(w http.ResponseWriter, r *http.Request) {
+	stations, err := h.orderService.GetKitchenQueue(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get kitchen queue")
+		return
+	}
+
+	response.Success(w, stations)
+}
+
+type VoidRequest struct {
+	Qty         int                `json:"qty"`
+	ReasonCode  domain.VoidReason  `json:"reason_code"`
+	ReasonText  *string            `json:"reason_text"`
+	StockAction domain.StockAction `json:"stock_action"`
+}
+
+// POST /api/orders/{id}/cancel
+func (h *OrderHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	v, byManager, ok := h.decodeVoid(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := h.orderService.CancelOrder(r.Context(), v, byManager); err != nil {
+		h.writeVoidError(w, err, "failed to cancel order")
+		return
+	}
+
+	response.Created(w, v)
+}
+
+// POST /api/orders/{id}/items/{itemId}/void
+func (h *OrderHandler) VoidItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+	itemID, err := strconv.Atoi(chi.URLParam(r, "itemId"))
+	if err != nil {
+		response.BadRequest(w, "invalid item id")
+		return
+	}
+
+	v, byManager, ok := h.decodeVoid(w, r, id)
+	if !ok {
+		return
+	}
+	v.OrderItemID = &itemID
+
+	if err := h.orderService.VoidItem(r.Context(), v, byManager); err != nil {
+		h.writeVoidError(w, err, "failed to void order item")
+		return
+	}
+
+	response.Created(w, v)
+}
+
+// GET /api/orders/{id}/voids
+func (h *OrderHandler) GetVoids(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	voids, err := h.orderService.GetVoids(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "failed to get voids")
+		return
+	}
+
+	response.Success(w, voids)
+}
+
+// GET /api/voids/pending
+func (h *OrderHandler) GetPendingVoids(w http.ResponseWriter, r *http.Request) {
+	voids, err := h.orderService.GetPendingVoids(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get pending voids")
+		return
+	}
+
+	response.Success(w, voids)
+}
+
+// PUT /api/voids/{id}/approve
+func (h *OrderHandler) ApproveVoid(w http.ResponseWriter, r *http.Request) {
+	h.resolveVoid(w, r, h.orderService.ApproveVoid, "void approved")
+}
+
+// PUT /api/voids/{id}/reject
+func (h *OrderHandler) RejectVoid(w http.ResponseWriter, r *http.Request) {
+	h.resolveVoid(w, r, h.orderService.RejectVoid, "void rejected")
+}
+
+func (h *OrderHandler) resolveVoid(
+	w http.ResponseWriter,
+	r *http.Request,
+	resolve func(ctx context.Context, voidID, managerID int) error,
+	message string,
+) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid void id")
+		return
+	}
+
+	managerID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	if err := resolve(r.Context(), id, managerID); err != nil {
+		h.writeVoidError(w, err, "failed to resolve void")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": message})
+}
+
+// decodeVoid reads the void request and fills in who is voiding
+func (h *OrderHandler) decodeVoid(w http.ResponseWriter, r *http.Request, orderID int) (*domain.OrderVoid, bool, bool) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return nil, false, false
+	}
+	role, _ := r.Context().Value(middleware.UserRoleKey).(domain.Role)
+
+	var req VoidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return nil, false, false
+	}
+
+	if req.StockAction == "" {
+		req.StockAction = domain.StockReturn
+	}
+	if !req.ReasonCode.IsValid() {
+		response.BadRequest(w, "invalid reason_code")
+		return nil, false, false
+	}
+	if !req.StockAction.IsValid() {
+		response.BadRequest(w, "stock_action must be 'return' or 'waste'")
+		return nil, false, false
+	}
+	if req.Qty < 0 {
+		response.BadRequest(w, "qty must be >= 0")
+		return nil, false, false
+	}
+
+	v := &domain.OrderVoid{
+		OrderID:     orderID,
+		Qty:         req.Qty,
+		ReasonCode:  req.ReasonCode,
+		ReasonText:  req.ReasonText,
+		StockAction: req.StockAction,
+		VoidedBy:    userID,
+	}
+
+	byManager := role == domain.RoleManager || role == domain.RoleAdmin
+	return v, byManager, true
+}
+
+func (h *OrderHandler) writeVoidError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrVoidNotFound:
+		response.NotFound(w, "void not found")
+	case domain.ErrVoidNotPending:
+		response.BadRequest(w, "void is already resolved")
+	case domain.ErrVoidQtyExceeded:
+		response.BadRequest(w, "void quantity exceeds item quantity")
+	case domain.ErrInvalidVoidInput:
+		response.BadRequest(w, "invalid reason_code or stock_action")
+	case domain.ErrBillHasPayments:
+		response.BadRequest(w, "items cannot be voided after the first payment")
+	default:
+		h.writeItemError(w, err, fallback)
+	}
+}
+
+type MoveOrderRequest struct {
+	TableID int `json:"table_id"`
+}
+
+// PUT /api/orders/{id}/table
+func (h *OrderHandler) MoveOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req MoveOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	if req.TableID <= 0 {
+		response.BadRequest(w, "invalid table id")
+		return
+	}
+
+	if err := h.orderService.MoveOrder(r.Context(), id, req.TableID); err != nil {
+		h.writeTransferError(w, err, "failed to move order")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+// POST /api/orders/{id}/merge — {"order_ids": [..]} и/или {"table_ids": [..]}
+func (h *OrderHandler) MergeOrders(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req domain.MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.orderService.MergeOrders(r.Context(), id, req); err != nil {
+		h.writeTransferError(w, err, "failed to merge orders")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+func (h *OrderHandler) writeTransferError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrOrderNotFound:
+		response.NotFound(w, "order not found")
+	case domain.ErrTableNotFound:
+		response.NotFound(w, "table not found")
+	case domain.ErrOrderNotEditable:
+		response.BadRequest(w, "order is already closed")
+	case domain.ErrTableBusy:
+		response.BadRequest(w, "table is not free")
+	case domain.ErrBillHasPayments:
+		response.BadRequest(w, "orders with payments cannot be merged")
+	case domain.ErrInsufficientStock:
+		response.BadRequest(w, "insufficient stock for order")
+	case domain.ErrInvalidMerge, domain.ErrNoOpenOrder, domain.ErrHasPendingVoids:
+		response.BadRequest(w, err.Error())
+	case domain.ErrNotYourTable, domain.ErrNotYourOrder:
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
+
+type PromoCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// ServiceChargeRequest — ставка сервисного сбора в процентах
+type ServiceChargeRequest struct {
+	Rate float64 `json:"rate"`
+}
+
+// CompRequest — разовая скидка менеджера на заказ или позицию (item_id)
+type CompRequest struct {
+	ItemID *int                `json:"item_id"`
+	Type   domain.DiscountType `json:"type"`
+	Value  float64             `json:"value"`
+	Name   string              `json:"name"`
+	Reason *string             `json:"reason"`
+}
+
+// PUT /api/orders/{id}/promo-code
+func (h *OrderHandler) ApplyPromoCode(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req PromoCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.orderService.ApplyPromoCode(r.Context(), id, strings.TrimSpace(req.Code)); err != nil {
+		h.writeDiscountError(w, err, "failed to apply promo code")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+// POST /api/orders/{id}/discounts
+func (h *OrderHandler) AddComp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req CompRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if !req.Type.IsValid() {
+		response.BadRequest(w, "type must be 'percent' or 'fixed'")
+		return
+	}
+
+	d := &domain.OrderDiscount{
+		OrderID:     id,
+		OrderItemID: req.ItemID,
+		Name:        req.Name,
+		Type:        req.Type,
+		Value:       req.Value,
+		Reason:      req.Reason,
+		CreatedBy:   &userID,
+	}
+
+	if err := h.orderService.AddComp(r.Context(), d); err != nil {
+		h.writeDiscountError(w, err, "failed to apply discount")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusCreated)
+}
+
+// DELETE /api/orders/{id}/discounts/{discountId}
+func (h *OrderHandler) RemoveDiscount(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+	discountID, err := strconv.Atoi(chi.URLParam(r, "discountId"))
+	if err != nil {
+		response.BadRequest(w, "invalid discount id")
+		return
+	}
+
+	if err := h.orderService.RemoveDiscount(r.Context(), id, discountID); err != nil {
+		h.writeDiscountError(w, err, "failed to remove discount")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+// PUT /api/orders/{id}/service-charge
+func (h *OrderHandler) SetServiceCharge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req ServiceChargeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.orderService.SetServiceCharge(r.Context(), id, req.Rate); err != nil {
+		h.writeDiscountError(w, err, "failed to set service charge")
+		return
+	}
+
+	h.respondWithOrder(w, r, id, http.StatusOK)
+}
+
+func (h *OrderHandler) writeDiscountError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrOrderNotFound:
+		response.NotFound(w, "order not found")
+	case domain.ErrDiscountNotFound:
+		response.NotFound(w, "discount not found")
+	case domain.ErrOrderItemNotFound:
+		response.NotFound(w, "order item not found")
+	case domain.ErrOrderNotEditable:
+		response.BadRequest(w, "order can no longer be edited")
+	case domain.ErrBillHasPayments:
+		response.BadRequest(w, "discounts cannot be changed after the first payment")
+	case domain.ErrInvalidPromoCode:
+		response.BadRequest(w, "promo code is invalid or not active")
+	case domain.ErrInvalidDiscount:
+		response.BadRequest(w, "invalid discount value")
+	case domain.ErrInvalidRate:
+		response.BadRequest(w, err.Error())
+	case domain.ErrNotYourTable, domain.ErrNotYourOrder:
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type PaymentHandler struct {
+	paymentService ports.PaymentService
+}
+
+func NewPaymentHandler(paymentService ports.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+type PaymentRequest struct {
+	SplitID  *int                 `json:"split_id"`
+	Method   domain.PaymentMethod `json:"method"`
+	Amount   float64              `json:"amount"` // 0 — оплатить весь остаток
+	Tip      float64              `json:"tip"`
+	Tendered float64              `json:"tendered"` // сколько наличных дал гость
+}
+
+func (h *PaymentHandler) GetBill(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	bill, err := h.paymentService.GetBill(r.Context(), id)
+	if err != nil {
+		h.writePaymentError(w, err, "failed to get bill")
+		return
+	}
+
+	response.Success(w, bill)
+}
+
+func (h *PaymentHandler) Split(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req domain.SplitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if _, err := h.paymentService.SplitOrder(r.Context(), id, &req); err != nil {
+		h.writePaymentError(w, err, "failed to split bill")
+		return
+	}
+
+	h.respondWithBill(w, r, id, http.StatusCreated)
+}
+
+func (h *PaymentHandler) Pay(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid order id")
+		return
+	}
+
+	var req PaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if !req.Method.IsValid() {
+		response.BadRequest(w, "invalid payment method")
+		return
+	}
+	if req.Amount < 0 || req.Tip < 0 || req.Tendered < 0 {
+		response.BadRequest(w, "payment amounts must not be negative")
+		return
+	}
+
+	payment := &domain.Payment{
+		OrderID:   id,
+		SplitID:   req.SplitID,
+		Method:    req.Method,
+		Amount:    req.Amount,
+		Tip:       req.Tip,
+		Tendered:  req.Tendered,
+		CreatedBy: userID,
+	}
+
+	if err := h.paymentService.Pay(r.Context(), payment); err != nil {
+		h.writePaymentError(w, err, "failed to accept payment")
+		return
+	}
+
+	// Нулевой счёт закрыт без платежа — отдаём итоговое состояние счёта
+	if payment.ID == 0 {
+		h.respondWithBill(w, r, id, http.StatusOK)
+		return
+	}
+
+	response.Created(w, payment)
+}
+
+// respondWithBill отдаёт текущее состояние счёта после изменения
+func (h *PaymentHandler) respondWithBill(w http.ResponseWriter, r *http.Request, orderID, status int) {
+	bill, err := h.paymentService.GetBill(r.Context(), orderID)
+	if err != nil {
+		response.Success(w, map[string]string{"message": "bill updated"})
+		return
+	}
+
+	response.JSON(w, status, bill)
+}
+
+func (h *PaymentHandler) writePaymentError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrOrderNotFound:
+		response.NotFound(w, "order not found")
+	case domain.ErrSplitNotFound:
+		response.NotFound(w, "bill split not found")
+	case domain.ErrOrderItemNotFound:
+		response.BadRequest(w, "order item not found")
+	case domain.ErrOrderNotReady:
+		response.BadRequest(w, "order must be in ready status to be paid")
+	case domain.ErrOrderNotEditable:
+		response.BadRequest(w, "order is already closed")
+	case domain.ErrInvalidSplit:
+		response.BadRequest(w, "invalid bill split")
+	case domain.ErrBillHasPayments:
+		response.BadRequest(w, "bill already has payments and cannot be split again")
+	case domain.ErrInvalidPayment:
+		response.BadRequest(w, "invalid payment")
+	case domain.ErrPaymentExceedsBalance:
+		response.BadRequest(w, "payment exceeds the remaining balance")
+	case domain.ErrInsufficientTender:
+		response.BadRequest(w, "tendered amount is less than payment amount")
+	case domain.ErrNotYourTable, domain.ErrNotYourOrder:
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
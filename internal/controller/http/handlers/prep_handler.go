@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type PrepHandler struct {
+	prepService ports.PrepService
+}
+
+func NewPrepHandler(prepService ports.PrepService) *PrepHandler {
+	return &PrepHandler{prepService: prepService}
+}
+
+func (h *PrepHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	preps, err := h.prepService.GetAll(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get prep items")
+		return
+	}
+
+	response.Success(w, preps)
+}
+
+func (h *PrepHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid prep item id")
+		return
+	}
+
+	prep, err := h.prepService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writePrepError(w, err, "failed to get prep item")
+		return
+	}
+
+	response.Success(w, prep)
+}
+
+// PUT /api/preps/{id} — {id} is the ingredient the recipe produces
+// {"batch_yield": 5, "made_to_order": false, "components": [{"ingredient_id": 1, "qty_per_batch": 3}]}
+func (h *PrepHandler) Save(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid prep item id")
+		return
+	}
+
+	var prep domain.PrepItem
+	if err := json.NewDecoder(r.Body).Decode(&prep); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	prep.IngredientID = id
+	if err := h.prepService.Save(r.Context(), &prep); err != nil {
+		h.writePrepError(w, err, "failed to save prep item")
+		return
+	}
+
+	response.Success(w, prep)
+}
+
+func (h *PrepHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid prep item id")
+		return
+	}
+
+	if err := h.prepService.Delete(r.Context(), id); err != nil {
+		h.writePrepError(w, err, "failed to delete prep item")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "prep item deleted"})
+}
+
+// POST /api/preps/{id}/batches {"batches": 2, "yield_qty": 9.5, "notes": "..."}
+func (h *PrepHandler) Produce(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid prep item id")
+		return
+	}
+
+	var batch domain.ProductionBatch
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	batch.PrepID = id
+	batch.UserID = &userID
+	if err := h.prepService.Produce(r.Context(), &batch); err != nil {
+		h.writePrepError(w, err, "failed to record production batch")
+		return
+	}
+
+	response.Created(w, batch)
+}
+
+// GET /api/preps/batches?limit=50 and GET /api/preps/{id}/batches
+func (h *PrepHandler) GetBatches(w http.ResponseWriter, r *http.Request) {
+	var prepID *int
+	if idStr := chi.URLParam(r, "id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			response.BadRequest(w, "invalid prep item id")
+			return
+		}
+		prepID = &id
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			response.BadRequest(w, "invalid limit parameter")
+			return
+		}
+	}
+
+	batches, err := h.prepService.GetBatches(r.Context(), prepID, limit)
+	if err != nil {
+		response.InternalError(w, "failed to get production batches")
+		return
+	}
+
+	response.Success(w, batches)
+}
+
+func (h *PrepHandler) writePrepError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrPrepItemNotFound:
+		response.NotFound(w, "prep item not found")
+	case domain.ErrIngredientNotFound:
+		response.BadRequest(w, "ingredient not found")
+	case domain.ErrInvalidPrepItem:
+		response.BadRequest(w, "batch_yield and batches must be > 0, components must be unique with qty_per_batch > 0")
+	case domain.ErrPrepCycle, domain.ErrPrepMadeToOrder:
+		response.BadRequest(w, err.Error())
+	case domain.ErrInsufficientStock:
+		response.BadRequest(w, "insufficient stock for the batch")
+	default:
+		response.InternalError(w, fallback)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type PromotionHandler struct {
+	promotionService ports.PromotionService
+}
+
+func NewPromotionHandler(promotionService ports.PromotionService) *PromotionHandler {
+	return &PromotionHandler{promotionService: promotionService}
+}
+
+func (h *PromotionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.promotionService.GetAll(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get promotions")
+		return
+	}
+
+	response.Success(w, promotions)
+}
+
+func (h *PromotionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.BadRequest(w, "invalid promotion id")
+		return
+	}
+
+	promotion, err := h.promotionService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writePromotionError(w, err, "failed to get promotion")
+		return
+	}
+
+	response.Success(w, promotion)
+}
+
+func (h *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	promotion := domain.Promotion{IsActive: true}
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.promotionService.Create(r.Context(), &promotion); err != nil {
+		h.writePromotionError(w, err, "failed to create promotion")
+		return
+	}
+
+	response.Created(w, promotion)
+}
+
+func (h *PromotionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.BadRequest(w, "invalid promotion id")
+		return
+	}
+
+	var promotion domain.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	promotion.ID = id
+	if err := h.promotionService.Update(r.Context(), &promotion); err != nil {
+		h.writePromotionError(w, err, "failed to update promotion")
+		return
+	}
+
+	response.Success(w, promotion)
+}
+
+func (h *PromotionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.BadRequest(w, "invalid promotion id")
+		return
+	}
+
+	if err := h.promotionService.Delete(r.Context(), id); err != nil {
+		h.writePromotionError(w, err, "failed to delete promotion")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "promotion deleted"})
+}
+
+func (h *PromotionHandler) writePromotionError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrPromotionNotFound:
+		response.NotFound(w, "promotion not found")
+	case domain.ErrInvalidPromotion:
+		response.BadRequest(w, "invalid promotion: check type, value and the fields the type requires")
+	case domain.ErrPromoCodeExists:
+		response.BadRequest(w, "promo code is already used by another promotion")
+	default:
+		response.InternalError(w, fallback)
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type PurchaseOrderHandler struct {
+	purchaseOrderService ports.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(purchaseOrderService ports.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{purchaseOrderService: purchaseOrderService}
+}
+
+// GET /api/purchase-orders?supplier_id=1&status=sent
+func (h *PurchaseOrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	var filter domain.PurchaseOrderFilter
+	q := r.URL.Query()
+
+	if v := q.Get("supplier_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			response.BadRequest(w, "invalid supplier_id")
+			return
+		}
+		filter.SupplierID = &id
+	}
+	if v := q.Get("status"); v != "" {
+		status := domain.PurchaseOrderStatus(v)
+		if !status.IsValid() {
+			response.BadRequest(w, "invalid status")
+			return
+		}
+		filter.Status = &status
+	}
+
+	orders, err := h.purchaseOrderService.GetAll(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "failed to get purchase orders")
+		return
+	}
+
+	response.Success(w, orders)
+}
+
+func (h *PurchaseOrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid purchase order id")
+		return
+	}
+
+	po, err := h.purchaseOrderService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writePurchaseOrderError(w, err, "failed to get purchase order")
+		return
+	}
+
+	response.Success(w, po)
+}
+
+// POST /api/purchase-orders
+// {"supplier_id": 1, "expected_at": "2025-01-20T00:00:00Z", "items": [{"ingredient_id": 1, "qty_ordered": 10}]}
+func (h *PurchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	var po domain.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	po.CreatedBy = &userID
+	if err := h.purchaseOrderService.Create(r.Context(), &po); err != nil {
+		h.writePurchaseOrderError(w, err, "failed to create purchase order")
+		return
+	}
+
+	response.Created(w, po)
+}
+
+func (h *PurchaseOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid purchase order id")
+		return
+	}
+
+	var po domain.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	po.ID = id
+	if err := h.purchaseOrderService.Update(r.Context(), &po); err != nil {
+		h.writePurchaseOrderError(w, err, "failed to update purchase order")
+		return
+	}
+
+	response.Success(w, po)
+}
+
+// POST /api/purchase-orders/{id}/send
+func (h *PurchaseOrderHandler) Send(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.purchaseOrderService.Send, "failed to send purchase order")
+}
+
+// POST /api/purchase-orders/{id}/cancel
+func (h *PurchaseOrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.purchaseOrderService.Cancel, "failed to cancel purchase order")
+}
+
+// POST /api/purchase-orders/{id}/receive
+// {"invoice_number": "INV-102", "currency": "KZT", "lines": [{"ingredient_id": 1, "qty": 6, "unit_price": 2150}]}
+func (h *PurchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid purchase order id")
+		return
+	}
+
+	var receipt domain.Receipt
+	if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	po, err := h.purchaseOrderService.Receive(r.Context(), id, receipt, userID)
+	if err != nil {
+		h.writePurchaseOrderError(w, err, "failed to receive purchase order")
+		return
+	}
+
+	response.Success(w, po)
+}
+
+func (h *PurchaseOrderHandler) transition(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, id int) (*domain.PurchaseOrder, error),
+	fallback string,
+) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid purchase order id")
+		return
+	}
+
+	po, err := action(r.Context(), id)
+	if err != nil {
+		h.writePurchaseOrderError(w, err, fallback)
+		return
+	}
+
+	response.Success(w, po)
+}
+
+func (h *PurchaseOrderHandler) writePurchaseOrderError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrPurchaseOrderNotFound:
+		response.NotFound(w, "purchase order not found")
+	case domain.ErrInvalidPurchaseOrder:
+		response.BadRequest(w, "supplier_id and at least one item are required, items must be unique with qty_ordered > 0 and unit_price >= 0")
+	case domain.ErrInvalidReceipt, domain.ErrInvalidCurrency, domain.ErrSupplierNotFound, domain.ErrSupplierInactive:
+		response.BadRequest(w, err.Error())
+	case domain.ErrIngredientNotFound:
+		response.BadRequest(w, "ingredient not found")
+	case domain.ErrPurchaseOrderStatus:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
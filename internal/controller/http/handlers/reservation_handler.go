@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type ReservationHandler struct {
+	reservationService ports.ReservationService
+}
+
+func NewReservationHandler(reservationService ports.ReservationService) *ReservationHandler {
+	return &ReservationHandler{reservationService: reservationService}
+}
+
+// ReservationRequest — время в RFC3339; ends_at можно не указывать, тогда берётся стандартная длительность брони.
+// Время приводится к локальному, как и остальные метки времени в базе.
+type ReservationRequest struct {
+	TableID    int        `json:"table_id"`
+	GuestName  string     `json:"guest_name"`
+	GuestPhone *string    `json:"guest_phone"`
+	PartySize  int        `json:"party_size"`
+	StartsAt   time.Time  `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+	Notes      *string    `json:"notes"`
+}
+
+func (req *ReservationRequest) toDomain() *domain.Reservation {
+	reservation := &domain.Reservation{
+		TableID:    req.TableID,
+		GuestName:  req.GuestName,
+		GuestPhone: req.GuestPhone,
+		PartySize:  req.PartySize,
+		StartsAt:   req.StartsAt.Local(),
+		Notes:      req.Notes,
+	}
+	if req.EndsAt != nil {
+		reservation.EndsAt = req.EndsAt.Local()
+	}
+	return reservation
+}
+
+// GET /api/reservations?from=&to=&table_id=&status=
+func (h *ReservationHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	var filter domain.ReservationFilter
+	query := r.URL.Query()
+
+	if v := query.Get("from"); v != "" {
+		from, err := parseReservationTime(v)
+		if err != nil {
+			response.BadRequest(w, "invalid 'from', use RFC3339 or YYYY-MM-DD")
+			return
+		}
+		filter.From = &from
+	}
+	if v := query.Get("to"); v != "" {
+		to, err := parseReservationTime(v)
+		if err != nil {
+			response.BadRequest(w, "invalid 'to', use RFC3339 or YYYY-MM-DD")
+			return
+		}
+		filter.To = &to
+	}
+	if v := query.Get("table_id"); v != "" {
+		tableID, err := strconv.Atoi(v)
+		if err != nil {
+			response.BadRequest(w, "invalid table_id")
+			return
+		}
+		filter.TableID = &tableID
+	}
+	if v := query.Get("status"); v != "" {
+		status := domain.ReservationStatus(v)
+		filter.Status = &status
+	}
+
+	reservations, err := h.reservationService.GetAll(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "failed to get reservations")
+		return
+	}
+
+	response.Success(w, reservations)
+}
+
+// GET /api/reservations/availability?at=2025-01-31T19:00:00Z&party_size=4
+func (h *ReservationHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	at, err := parseReservationTime(r.URL.Query().Get("at"))
+	if err != nil {
+		response.BadRequest(w, "invalid 'at', use RFC3339")
+		return
+	}
+	partySize, err := strconv.Atoi(r.URL.Query().Get("party_size"))
+	if err != nil || partySize <= 0 {
+		response.BadRequest(w, "invalid party_size")
+		return
+	}
+
+	tables, err := h.reservationService.FindAvailable(r.Context(), at, partySize)
+	if err != nil {
+		h.writeReservationError(w, err, "failed to search available tables")
+		return
+	}
+
+	response.Success(w, tables)
+}
+
+func (h *ReservationHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid reservation id")
+		return
+	}
+
+	reservation, err := h.reservationService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writeReservationError(w, err, "failed to get reservation")
+		return
+	}
+
+	response.Success(w, reservation)
+}
+
+func (h *ReservationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	var req ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	reservation := req.toDomain()
+	reservation.CreatedBy = &userID
+	if err := h.reservationService.Create(r.Context(), reservation); err != nil {
+		h.writeReservationError(w, err, "failed to create reservation")
+		return
+	}
+
+	response.Created(w, reservation)
+}
+
+func (h *ReservationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid reservation id")
+		return
+	}
+
+	var req ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	reservation := req.toDomain()
+	reservation.ID = id
+	if err := h.reservationService.Update(r.Context(), reservation); err != nil {
+		h.writeReservationError(w, err, "failed to update reservation")
+		return
+	}
+
+	response.Success(w, reservation)
+}
+
+// POST /api/reservations/{id}/cancel
+func (h *ReservationHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid reservation id")
+		return
+	}
+
+	if err := h.reservationService.Cancel(r.Context(), id); err != nil {
+		h.writeReservationError(w, err, "failed to cancel reservation")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "reservation cancelled"})
+}
+
+// POST /api/reservations/{id}/seat
+func (h *ReservationHandler) Seat(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid reservation id")
+		return
+	}
+
+	if err := h.reservationService.Seat(r.Context(), id); err != nil {
+		h.writeReservationError(w, err, "failed to seat reservation")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "guests seated"})
+}
+
+func (h *ReservationHandler) writeReservationError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrReservationNotFound:
+		response.NotFound(w, "reservation not found")
+	case domain.ErrTableNotFound:
+		response.NotFound(w, "table not found")
+	case domain.ErrReservationConflict:
+		response.Error(w, http.StatusConflict, err.Error())
+	case domain.ErrInvalidReservation:
+		response.BadRequest(w, "guest_name, table_id, party_size and a valid time slot are required")
+	case domain.ErrTableTooSmall, domain.ErrReservationNotBooked, domain.ErrReservationInThePast:
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
+
+func parseReservationTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Local(), nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
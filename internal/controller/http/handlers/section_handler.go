@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type SectionHandler struct {
+	sectionService ports.SectionService
+}
+
+func NewSectionHandler(sectionService ports.SectionService) *SectionHandler {
+	return &SectionHandler{sectionService: sectionService}
+}
+
+// GET /api/sections?date=2025-01-31 (по умолчанию — текущая смена)
+func (h *SectionHandler) GetSections(w http.ResponseWriter, r *http.Request) {
+	shiftDate := time.Now()
+	if v := r.URL.Query().Get("date"); v != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			response.BadRequest(w, "invalid date, use YYYY-MM-DD")
+			return
+		}
+		shiftDate = parsed
+	}
+
+	sections, err := h.sectionService.GetSections(r.Context(), shiftDate)
+	if err != nil {
+		response.InternalError(w, "failed to get sections")
+		return
+	}
+
+	response.Success(w, sections)
+}
+
+// PUT /api/sections/{waiterId} — заменяет секцию официанта на смену
+func (h *SectionHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	waiterID, err := strconv.Atoi(chi.URLParam(r, "waiterId"))
+	if err != nil {
+		response.BadRequest(w, "invalid waiter id")
+		return
+	}
+
+	var req struct {
+		Date     string `json:"date"`
+		TableIDs []int  `json:"table_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	section := &domain.Section{WaiterID: waiterID, ShiftDate: time.Now(), TableIDs: req.TableIDs}
+	if req.Date != "" {
+		section.ShiftDate, err = time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			response.BadRequest(w, "invalid date, use YYYY-MM-DD")
+			return
+		}
+	}
+
+	if err := h.sectionService.Assign(r.Context(), section, userID); err != nil {
+		h.writeSectionError(w, err, "failed to assign section")
+		return
+	}
+
+	response.Success(w, section)
+}
+
+// POST /api/sections/handover — передать открытые столы другому официанту
+func (h *SectionHandler) Handover(w http.ResponseWriter, r *http.Request) {
+	var handover domain.Handover
+	if err := json.NewDecoder(r.Body).Decode(&handover); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.sectionService.Handover(r.Context(), &handover); err != nil {
+		h.writeSectionError(w, err, "failed to hand over tables")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "tables handed over"})
+}
+
+func (h *SectionHandler) writeSectionError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrTableNotFound:
+		response.NotFound(w, "table not found")
+	case domain.ErrInvalidSection:
+		response.BadRequest(w, "waiter must be an active waiter and tables must not repeat")
+	case domain.ErrInvalidHandover:
+		response.BadRequest(w, "handover must be to another active waiter")
+	case domain.ErrHandoverNotAllowed:
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type StocktakeHandler struct {
+	stocktakeService ports.StocktakeService
+}
+
+func NewStocktakeHandler(stocktakeService ports.StocktakeService) *StocktakeHandler {
+	return &StocktakeHandler{stocktakeService: stocktakeService}
+}
+
+func (h *StocktakeHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	stocktakes, err := h.stocktakeService.GetAll(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get stocktakes")
+		return
+	}
+
+	response.Success(w, stocktakes)
+}
+
+func (h *StocktakeHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid stocktake id")
+		return
+	}
+
+	stocktake, err := h.stocktakeService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writeStocktakeError(w, err, "failed to get stocktake")
+		return
+	}
+
+	response.Success(w, stocktake)
+}
+
+// POST /api/stocktakes {"note": "end of month"}
+func (h *StocktakeHandler) Start(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	var req struct {
+		Note *string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "invalid request body")
+			return
+		}
+	}
+
+	stocktake, err := h.stocktakeService.Start(r.Context(), req.Note, userID)
+	if err != nil {
+		h.writeStocktakeError(w, err, "failed to start stocktake")
+		return
+	}
+
+	response.Created(w, stocktake)
+}
+
+// PUT /api/stocktakes/{id}/counts {"counts": [{"ingredient_id": 1, "counted_qty": 12.5}]}
+func (h *StocktakeHandler) SaveCounts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid stocktake id")
+		return
+	}
+
+	var req struct {
+		Counts []domain.StocktakeLine `json:"counts"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	if len(req.Counts) == 0 {
+		response.BadRequest(w, "counts are required")
+		return
+	}
+
+	stocktake, err := h.stocktakeService.SaveCounts(r.Context(), id, req.Counts, userID)
+	if err != nil {
+		h.writeStocktakeError(w, err, "failed to save counts")
+		return
+	}
+
+	response.Success(w, stocktake)
+}
+
+func (h *StocktakeHandler) RemoveCount(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid stocktake id")
+		return
+	}
+
+	ingredientID, err := strconv.Atoi(chi.URLParam(r, "ingredientId"))
+	if err != nil {
+		response.BadRequest(w, "invalid ingredient id")
+		return
+	}
+
+	if err := h.stocktakeService.RemoveCount(r.Context(), id, ingredientID); err != nil {
+		h.writeStocktakeError(w, err, "failed to remove count")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "count removed"})
+}
+
+// POST /api/stocktakes/{id}/post
+func (h *StocktakeHandler) Post(w http.ResponseWriter, r *http.Request) {
+	h.close(w, r, h.stocktakeService.Post, "failed to post stocktake")
+}
+
+// POST /api/stocktakes/{id}/cancel
+func (h *StocktakeHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.close(w, r, h.stocktakeService.Cancel, "failed to cancel stocktake")
+}
+
+func (h *StocktakeHandler) close(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, id int, userID int) (*domain.Stocktake, error),
+	fallback string,
+) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid stocktake id")
+		return
+	}
+
+	stocktake, err := action(r.Context(), id, userID)
+	if err != nil {
+		h.writeStocktakeError(w, err, fallback)
+		return
+	}
+
+	response.Success(w, stocktake)
+}
+
+func (h *StocktakeHandler) writeStocktakeError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrStocktakeNotFound, domain.ErrStocktakeLineNotFound:
+		response.NotFound(w, err.Error())
+	case domain.ErrIngredientNotFound:
+		response.BadRequest(w, "ingredient not found")
+	case domain.ErrInvalidStocktakeCount:
+		response.BadRequest(w, err.Error())
+	case domain.ErrStocktakeOpen, domain.ErrStocktakeClosed:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
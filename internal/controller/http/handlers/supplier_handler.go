@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type SupplierHandler struct {
+	supplierService ports.SupplierService
+}
+
+func NewSupplierHandler(supplierService ports.SupplierService) *SupplierHandler {
+	return &SupplierHandler{supplierService: supplierService}
+}
+
+// GET /api/suppliers?active=true
+func (h *SupplierHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	activeOnly := r.URL.Query().Get("active") == "true"
+
+	suppliers, err := h.supplierService.GetAll(r.Context(), activeOnly)
+	if err != nil {
+		response.InternalError(w, "failed to get suppliers")
+		return
+	}
+
+	response.Success(w, suppliers)
+}
+
+func (h *SupplierHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid supplier id")
+		return
+	}
+
+	supplier, err := h.supplierService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writeSupplierError(w, err, "failed to get supplier")
+		return
+	}
+
+	response.Success(w, supplier)
+}
+
+func (h *SupplierHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var supplier domain.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	if err := h.supplierService.Create(r.Context(), &supplier); err != nil {
+		h.writeSupplierError(w, err, "failed to create supplier")
+		return
+	}
+
+	response.Created(w, supplier)
+}
+
+func (h *SupplierHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid supplier id")
+		return
+	}
+
+	var supplier domain.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	supplier.ID = id
+	if err := h.supplierService.Update(r.Context(), &supplier); err != nil {
+		h.writeSupplierError(w, err, "failed to update supplier")
+		return
+	}
+
+	response.Success(w, supplier)
+}
+
+func (h *SupplierHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid supplier id")
+		return
+	}
+
+	if err := h.supplierService.Delete(r.Context(), id); err != nil {
+		h.writeSupplierError(w, err, "failed to delete supplier")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "supplier deactivated"})
+}
+
+// PUT /api/suppliers/{id}/catalog/{ingredientId} {"unit_price": 2200, "sku": "CH-01", "min_order_qty": 5}
+func (h *SupplierHandler) SaveCatalogItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid supplier id")
+		return
+	}
+
+	ingredientID, err := strconv.Atoi(chi.URLParam(r, "ingredientId"))
+	if err != nil {
+		response.BadRequest(w, "invalid ingredient id")
+		return
+	}
+
+	var item domain.SupplierIngredient
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	item.SupplierID = id
+	item.IngredientID = ingredientID
+	if err := h.supplierService.SaveCatalogItem(r.Context(), &item); err != nil {
+		h.writeSupplierError(w, err, "failed to save catalog item")
+		return
+	}
+
+	response.Success(w, item)
+}
+
+func (h *SupplierHandler) RemoveCatalogItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid supplier id")
+		return
+	}
+
+	ingredientID, err := strconv.Atoi(chi.URLParam(r, "ingredientId"))
+	if err != nil {
+		response.BadRequest(w, "invalid ingredient id")
+		return
+	}
+
+	if err := h.supplierService.RemoveCatalogItem(r.Context(), id, ingredientID); err != nil {
+		h.writeSupplierError(w, err, "failed to remove catalog item")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "catalog item removed"})
+}
+
+func (h *SupplierHandler) writeSupplierError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrSupplierNotFound:
+		response.NotFound(w, "supplier not found")
+	case domain.ErrInvalidSupplier:
+		response.BadRequest(w, "name is required, lead_time_days, unit_price and min_order_qty must be >= 0")
+	case domain.ErrIngredientNotFound:
+		response.BadRequest(w, "ingredient not found")
+	default:
+		response.InternalError(w, fallback)
+	}
+}
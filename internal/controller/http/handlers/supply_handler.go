@@ -3,18 +3,27 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
 	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
 )
 
 type SupplyHandler struct {
 	supplyService ports.SupplyService
+	storage       ports.FileStorage
+	bucketName    string
 }
 
-func NewSupplyHandler(supplyService ports.SupplyService) *SupplyHandler {
-	return &SupplyHandler{supplyService: supplyService}
+func NewSupplyHandler(supplyService ports.SupplyService, storage ports.FileStorage, bucketName string) *SupplyHandler {
+	return &SupplyHandler{
+		supplyService: supplyService,
+		storage:       storage,
+		bucketName:    bucketName,
+	}
 }
 
 func (h *SupplyHandler) GetAll(w http.ResponseWriter, r *http.Request) {
@@ -27,7 +36,33 @@ func (h *SupplyHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, supplies)
 }
 
+func (h *SupplyHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid supply id")
+		return
+	}
+
+	supply, err := h.supplyService.GetByID(r.Context(), id)
+	if err != nil {
+		if err == domain.ErrSupplyNotFound {
+			response.NotFound(w, "supply not found")
+			return
+		}
+		response.InternalError(w, "failed to get supply")
+		return
+	}
+
+	response.Success(w, supply)
+}
+
 func (h *SupplyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
 	var supply domain.Supply
 	if err := json.NewDecoder(r.Body).Decode(&supply); err != nil {
 		response.BadRequest(w, "invalid request body")
@@ -42,15 +77,70 @@ func (h *SupplyHandler) Create(w http.ResponseWriter, r *http.Request) {
 		response.BadRequest(w, "qty must be > 0")
 		return
 	}
-	if supply.SupplierName == "" {
-		response.BadRequest(w, "supplier_name is required")
+	if supply.SupplierName == "" && supply.SupplierID == nil {
+		response.BadRequest(w, "supplier_name or supplier_id is required")
+		return
+	}
+	if supply.UnitPrice != nil && *supply.UnitPrice < 0 {
+		response.BadRequest(w, "unit_price must be >= 0")
 		return
 	}
 
+	supply.ReceivedBy = &userID
 	if err := h.supplyService.Create(r.Context(), &supply); err != nil {
+		if err == domain.ErrIngredientNotFound || err == domain.ErrSupplierNotFound || err == domain.ErrInvalidCurrency {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalError(w, "failed to create supply")
 		return
 	}
 
 	response.Created(w, supply)
 }
+
+// POST /api/supplies/{id}/invoice
+// form-data: file=<image или pdf накладной>
+func (h *SupplyHandler) UploadInvoice(w http.ResponseWriter, r *http.Request) {
+	const maxSize = 10 << 20 // 10 MB, сканы накладных бывают тяжёлыми
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid supply id")
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		response.BadRequest(w, "failed to parse multipart form")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, "file is required")
+		return
+	}
+	defer file.Close()
+
+	url, err := uploadFile(r.Context(), h.storage, h.bucketName, "invoices", file, header, "image/", "application/pdf")
+	if err == errUnsupportedFile {
+		response.BadRequest(w, "only image or pdf uploads are allowed")
+		return
+	}
+	if err != nil {
+		response.InternalError(w, "failed to upload invoice")
+		return
+	}
+
+	supply, err := h.supplyService.AttachInvoice(r.Context(), id, url)
+	if err != nil {
+		if err == domain.ErrSupplyNotFound {
+			response.NotFound(w, "supply not found")
+			return
+		}
+		response.InternalError(w, "failed to attach invoice")
+		return
+	}
+
+	response.Success(w, supply)
+}
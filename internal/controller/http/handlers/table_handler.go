@@ -30,6 +30,17 @@ func (h *TableHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, tables)
 }
 
+// GET /api/tables/floor
+func (h *TableHandler) GetFloor(w http.ResponseWriter, r *http.Request) {
+	floor, err := h.tableService.GetFloor(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get floor plan")
+		return
+	}
+
+	response.Success(w, floor)
+}
+
 func (h *TableHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
@@ -60,13 +71,59 @@ func (h *TableHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.tableService.Create(r.Context(), &table); err != nil {
-		response.InternalError(w, "failed to create table")
+		h.writeTableError(w, err, "failed to create table")
 		return
 	}
 
 	response.Created(w, table)
 }
 
+// PUT /api/tables/{id} — название, вместимость и расположение стола
+func (h *TableHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.BadRequest(w, "invalid table id")
+		return
+	}
+
+	var table domain.Table
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	table.ID = id
+	if err := h.tableService.Update(r.Context(), &table); err != nil {
+		h.writeTableError(w, err, "failed to update table")
+		return
+	}
+
+	response.Success(w, table)
+}
+
+// PUT /api/tables/layout — расстановка нескольких столов за раз
+func (h *TableHandler) UpdateLayout(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Tables []domain.TablePosition `json:"tables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+	if len(req.Tables) == 0 {
+		response.BadRequest(w, "tables are required")
+		return
+	}
+
+	if err := h.tableService.UpdateLayout(r.Context(), req.Tables); err != nil {
+		h.writeTableError(w, err, "failed to update layout")
+		return
+	}
+
+	h.GetFloor(w, r)
+}
+
 func (h *TableHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
@@ -84,13 +141,26 @@ func (h *TableHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.tableService.UpdateStatus(r.Context(), id, req.Status); err != nil {
-		response.InternalError(w, "failed to update table status")
+		h.writeTableError(w, err, "failed to update table status")
 		return
 	}
 
 	response.Success(w, map[string]string{"message": "table status updated"})
 }
 
+func (h *TableHandler) writeTableError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrTableNotFound:
+		response.NotFound(w, "table not found")
+	case domain.ErrInvalidTable:
+		response.BadRequest(w, "invalid table: name, capacity, zone (hall, terrace, bar), shape (square, round, rectangle) or position")
+	case domain.ErrNotYourTable, domain.ErrNotYourOrder:
+		response.Forbidden(w, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
+
 func (h *TableHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type WaitlistHandler struct {
+	waitlistService ports.WaitlistService
+}
+
+func NewWaitlistHandler(waitlistService ports.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{waitlistService: waitlistService}
+}
+
+// GET /api/waitlist — очередь с текущей оценкой ожидания
+func (h *WaitlistHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	queue, err := h.waitlistService.GetQueue(r.Context())
+	if err != nil {
+		response.InternalError(w, "failed to get waitlist")
+		return
+	}
+
+	response.Success(w, queue)
+}
+
+// GET /api/waitlist/estimate?party_size=4
+func (h *WaitlistHandler) Estimate(w http.ResponseWriter, r *http.Request) {
+	partySize, err := strconv.Atoi(r.URL.Query().Get("party_size"))
+	if err != nil || partySize <= 0 {
+		response.BadRequest(w, "invalid party_size")
+		return
+	}
+
+	estimate, err := h.waitlistService.Estimate(r.Context(), partySize)
+	if err != nil {
+		h.writeWaitlistError(w, err, "failed to estimate wait")
+		return
+	}
+
+	response.Success(w, estimate)
+}
+
+func (h *WaitlistHandler) Add(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	var req struct {
+		GuestName  string  `json:"guest_name"`
+		GuestPhone *string `json:"guest_phone"`
+		PartySize  int     `json:"party_size"`
+		Notes      *string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	entry := &domain.WaitlistEntry{
+		GuestName:  req.GuestName,
+		GuestPhone: req.GuestPhone,
+		PartySize:  req.PartySize,
+		Notes:      req.Notes,
+		CreatedBy:  &userID,
+	}
+	if err := h.waitlistService.Add(r.Context(), entry); err != nil {
+		h.writeWaitlistError(w, err, "failed to add party to waitlist")
+		return
+	}
+
+	response.Created(w, entry)
+}
+
+// POST /api/waitlist/{id}/cancel
+func (h *WaitlistHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid waitlist entry id")
+		return
+	}
+
+	if err := h.waitlistService.Cancel(r.Context(), id); err != nil {
+		h.writeWaitlistError(w, err, "failed to cancel waitlist entry")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "party removed from waitlist"})
+}
+
+// POST /api/waitlist/{id}/seat {"table_id": 3}
+func (h *WaitlistHandler) Seat(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid waitlist entry id")
+		return
+	}
+
+	var req struct {
+		TableID int `json:"table_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TableID <= 0 {
+		response.BadRequest(w, "table_id is required")
+		return
+	}
+
+	if err := h.waitlistService.Seat(r.Context(), id, req.TableID); err != nil {
+		h.writeWaitlistError(w, err, "failed to seat party")
+		return
+	}
+
+	response.Success(w, map[string]string{"message": "guests seated"})
+}
+
+func (h *WaitlistHandler) writeWaitlistError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrWaitlistEntryNotFound:
+		response.NotFound(w, "waitlist entry not found")
+	case domain.ErrTableNotFound:
+		response.NotFound(w, "table not found")
+	case domain.ErrInvalidWaitlistEntry:
+		response.BadRequest(w, "guest_name and party_size are required")
+	case domain.ErrTableBusy:
+		response.Error(w, http.StatusConflict, err.Error())
+	case domain.ErrWaitlistEntryClosed, domain.ErrTableTooSmall, domain.ErrNoTableForParty:
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalError(w, fallback)
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/controller/http/middleware"
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
+	"github.com/go-chi/chi/v5"
+)
+
+type WasteHandler struct {
+	wasteService ports.WasteService
+	storage      ports.FileStorage
+	bucketName   string
+}
+
+func NewWasteHandler(wasteService ports.WasteService, storage ports.FileStorage, bucketName string) *WasteHandler {
+	return &WasteHandler{wasteService: wasteService, storage: storage, bucketName: bucketName}
+}
+
+// POST /api/waste
+// JSON: {"ingredient_id": 3, "qty": 1.5, "reason": "spoiled", "note": "...", "photo_url": "..."}
+// или form-data с теми же полями и фото в поле photo
+func (h *WasteHandler) Log(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		response.Unauthorized(w, "user not authenticated")
+		return
+	}
+
+	var entry domain.WasteEntry
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if !h.parseForm(w, r, &entry) {
+			return
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		response.BadRequest(w, "invalid request body")
+		return
+	}
+
+	entry.UserID = &userID
+	if err := h.wasteService.Log(r.Context(), &entry); err != nil {
+		h.writeWasteError(w, err, "failed to log waste")
+		return
+	}
+
+	response.Created(w, entry)
+}
+
+// parseForm reads a multipart waste entry and uploads its photo, if any
+func (h *WasteHandler) parseForm(w http.ResponseWriter, r *http.Request, entry *domain.WasteEntry) bool {
+	const maxSize = 5 << 20 // 5 MB
+
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		response.BadRequest(w, "failed to parse multipart form")
+		return false
+	}
+
+	if v := r.FormValue("ingredient_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			response.BadRequest(w, "invalid ingredient_id")
+			return false
+		}
+		entry.IngredientID = &id
+	}
+	if v := r.FormValue("dish_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			response.BadRequest(w, "invalid dish_id")
+			return false
+		}
+		entry.DishID = &id
+	}
+
+	qty, err := strconv.ParseFloat(r.FormValue("qty"), 64)
+	if err != nil {
+		response.BadRequest(w, "invalid qty")
+		return false
+	}
+	entry.Qty = qty
+	entry.Reason = domain.WasteReason(r.FormValue("reason"))
+	if note := r.FormValue("note"); note != "" {
+		entry.Note = &note
+	}
+
+	file, header, err := r.FormFile("photo")
+	if err == http.ErrMissingFile {
+		return true
+	}
+	if err != nil {
+		response.BadRequest(w, "invalid photo")
+		return false
+	}
+	defer file.Close()
+
+	url, err := uploadFile(r.Context(), h.storage, h.bucketName, "waste", file, header, "image/")
+	if err == errUnsupportedFile {
+		response.BadRequest(w, "only image uploads are allowed")
+		return false
+	}
+	if err != nil {
+		response.InternalError(w, "failed to upload photo")
+		return false
+	}
+	entry.PhotoURL = &url
+	return true
+}
+
+// GET /api/waste?reason=spoiled&from=2025-01-01&to=2025-01-31&limit=100
+func (h *WasteHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	var filter domain.WasteFilter
+	q := r.URL.Query()
+
+	if v := q.Get("reason"); v != "" {
+		reason := domain.WasteReason(v)
+		if !reason.IsValid() {
+			response.BadRequest(w, "invalid reason")
+			return
+		}
+		filter.Reason = &reason
+	}
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.BadRequest(w, "invalid from date, use YYYY-MM-DD")
+			return
+		}
+		filter.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.BadRequest(w, "invalid to date, use YYYY-MM-DD")
+			return
+		}
+		to = to.Add(24 * time.Hour)
+		filter.To = &to
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			response.BadRequest(w, "invalid limit parameter")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.wasteService.GetAll(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "failed to get waste log")
+		return
+	}
+
+	response.Success(w, entries)
+}
+
+func (h *WasteHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "invalid waste entry id")
+		return
+	}
+
+	entry, err := h.wasteService.GetByID(r.Context(), id)
+	if err != nil {
+		h.writeWasteError(w, err, "failed to get waste entry")
+		return
+	}
+
+	response.Success(w, entry)
+}
+
+func (h *WasteHandler) writeWasteError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrWasteEntryNotFound:
+		response.NotFound(w, "waste entry not found")
+	case domain.ErrInvalidWasteEntry:
+		response.BadRequest(w, "either ingredient_id or dish_id is required, qty must be > 0, reason must be spoiled, expired, dropped, burnt, returned or other")
+	case domain.ErrIngredientNotFound, domain.ErrDishNotFound:
+		response.BadRequest(w, err.Error())
+	case domain.ErrInsufficientStock:
+		response.BadRequest(w, "cannot write off more than is in stock")
+	default:
+		response.InternalError(w, fallback)
+	}
+}
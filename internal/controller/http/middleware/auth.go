@@ -6,6 +6,7 @@ import (
 
 	"strings"
 
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 	"github.com/YelzhanWeb/uno-spicchio/pkg/jwt"
 	"github.com/YelzhanWeb/uno-spicchio/pkg/response"
 )
@@ -43,8 +44,23 @@ func Auth(tokenManager *jwt.TokenManager) func(http.Handler) http.Handler {
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, UsernameKey, claims.Username)
 			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
+			ctx = domain.WithActor(ctx, domain.Actor{UserID: claims.UserID, Role: claims.Role})
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// TokenFromQuery copies ?token= into the Authorization header for clients
+// that cannot send headers, such as the browser EventSource API.
+func TokenFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			if token := r.URL.Query().Get("token"); token != "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
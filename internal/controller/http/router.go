@@ -11,44 +11,83 @@ import (
 )
 
 type Router struct {
-	authHandler       *handlers.AuthHandler
-	userHandler       *handlers.UserHandler
-	orderHandler      *handlers.OrderHandler
-	dishHandler       *handlers.DishHandler
-	ingredientHandler *handlers.IngredientHandler
-	supplyHandler     *handlers.SupplyHandler
-	tableHandler      *handlers.TableHandler
-	categoryHandler   *handlers.CategoryHandler
-	analyticsHandler  *handlers.AnalyticsHandler
-	fileHandler       *handlers.FileHandler
-	tokenManager      *jwt.TokenManager
+	authHandler        *handlers.AuthHandler
+	userHandler        *handlers.UserHandler
+	orderHandler       *handlers.OrderHandler
+	paymentHandler     *handlers.PaymentHandler
+	promotionHandler   *handlers.PromotionHandler
+	dishHandler        *handlers.DishHandler
+	comboHandler       *handlers.ComboHandler
+	menuHandler        *handlers.MenuHandler
+	ingredientHandler  *handlers.IngredientHandler
+	prepHandler        *handlers.PrepHandler
+	stocktakeHandler   *handlers.StocktakeHandler
+	wasteHandler       *handlers.WasteHandler
+	supplyHandler      *handlers.SupplyHandler
+	supplierHandler    *handlers.SupplierHandler
+	purchaseHandler    *handlers.PurchaseOrderHandler
+	tableHandler       *handlers.TableHandler
+	reservationHandler *handlers.ReservationHandler
+	waitlistHandler    *handlers.WaitlistHandler
+	sectionHandler     *handlers.SectionHandler
+	categoryHandler    *handlers.CategoryHandler
+	analyticsHandler   *handlers.AnalyticsHandler
+	fileHandler        *handlers.FileHandler
+	eventHandler       *handlers.EventHandler
+	tokenManager       *jwt.TokenManager
 }
 
 func NewRouter(
 	authService ports.AuthService,
 	userService ports.UserService,
 	orderService ports.OrderService,
+	paymentService ports.PaymentService,
+	promotionService ports.PromotionService,
 	dishService ports.DishService,
+	comboService ports.ComboService,
+	menuService ports.MenuService,
 	ingredientService ports.IngredientService,
+	prepService ports.PrepService,
+	stocktakeService ports.StocktakeService,
+	wasteService ports.WasteService,
 	supplyService ports.SupplyService,
+	supplierService ports.SupplierService,
+	purchaseOrderService ports.PurchaseOrderService,
 	tableService ports.TableService,
+	reservationService ports.ReservationService,
+	waitlistService ports.WaitlistService,
+	sectionService ports.SectionService,
 	categoryService ports.CategoryService,
 	analyticsService ports.AnalyticsService,
 	fileStorage ports.FileStorage,
+	eventSubscriber ports.EventSubscriber,
 	tokenManager *jwt.TokenManager,
 ) *Router {
 	return &Router{
-		authHandler:       handlers.NewAuthHandler(authService),
-		userHandler:       handlers.NewUserHandler(userService),
-		orderHandler:      handlers.NewOrderHandler(orderService),
-		dishHandler:       handlers.NewDishHandler(dishService),
-		ingredientHandler: handlers.NewIngredientHandler(ingredientService),
-		supplyHandler:     handlers.NewSupplyHandler(supplyService),
-		tableHandler:      handlers.NewTableHandler(tableService),
-		categoryHandler:   handlers.NewCategoryHandler(categoryService),
-		analyticsHandler:  handlers.NewAnalyticsHandler(analyticsService),
-		fileHandler:       handlers.NewFileHandler(fileStorage, "uno-spicchio"),
-		tokenManager:      tokenManager,
+		authHandler:        handlers.NewAuthHandler(authService),
+		userHandler:        handlers.NewUserHandler(userService),
+		orderHandler:       handlers.NewOrderHandler(orderService),
+		paymentHandler:     handlers.NewPaymentHandler(paymentService),
+		promotionHandler:   handlers.NewPromotionHandler(promotionService),
+		dishHandler:        handlers.NewDishHandler(dishService),
+		comboHandler:       handlers.NewComboHandler(comboService),
+		menuHandler:        handlers.NewMenuHandler(menuService),
+		ingredientHandler:  handlers.NewIngredientHandler(ingredientService),
+		prepHandler:        handlers.NewPrepHandler(prepService),
+		stocktakeHandler:   handlers.NewStocktakeHandler(stocktakeService),
+		wasteHandler:       handlers.NewWasteHandler(wasteService, fileStorage, "uno-spicchio"),
+		supplyHandler:      handlers.NewSupplyHandler(supplyService, fileStorage, "uno-spicchio"),
+		supplierHandler:    handlers.NewSupplierHandler(supplierService),
+		purchaseHandler:    handlers.NewPurchaseOrderHandler(purchaseOrderService),
+		tableHandler:       handlers.NewTableHandler(tableService),
+		reservationHandler: handlers.NewReservationHandler(reservationService),
+		waitlistHandler:    handlers.NewWaitlistHandler(waitlistService),
+		sectionHandler:     handlers.NewSectionHandler(sectionService),
+		categoryHandler:    handlers.NewCategoryHandler(categoryService),
+		analyticsHandler:   handlers.NewAnalyticsHandler(analyticsService),
+		fileHandler:        handlers.NewFileHandler(fileStorage, "uno-spicchio"),
+		eventHandler:       handlers.NewEventHandler(eventSubscriber),
+		tokenManager:       tokenManager,
 	}
 }
 
@@ -62,6 +101,10 @@ func (rt *Router) Setup() *chi.Mux {
 	// Public routes
 	r.Post("/api/auth/login", rt.authHandler.Login)
 
+	// Live events (SSE). EventSource не умеет заголовки, поэтому токен можно передать в ?token=
+	r.With(middleware.TokenFromQuery, middleware.Auth(rt.tokenManager)).
+		Get("/api/events", rt.eventHandler.Stream)
+
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Auth(rt.tokenManager))
@@ -98,6 +141,13 @@ func (rt *Router) Setup() *chi.Mux {
 			r.Get("/", rt.dishHandler.GetAll)
 			r.Get("/{id}", rt.dishHandler.GetByID)
 			r.Get("/{id}/ingredients", rt.dishHandler.GetIngredients)
+			r.Get("/{id}/modifiers", rt.dishHandler.GetModifiers)
+
+			// Кухня снимает блюдо с продажи (86) и возвращает его
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleCook, domain.RoleManager, domain.RoleAdmin))
+				r.Put("/{id}/sold-out", rt.dishHandler.SetSoldOut)
+			})
 
 			// Admin only
 			r.Group(func(r chi.Router) {
@@ -105,6 +155,44 @@ func (rt *Router) Setup() *chi.Mux {
 				r.Post("/", rt.dishHandler.Create)
 				r.Put("/{id}", rt.dishHandler.Update)
 				r.Delete("/{id}", rt.dishHandler.Delete)
+
+				r.Post("/{id}/modifiers", rt.dishHandler.CreateModifierGroup)
+				r.Put("/{id}/modifiers/{groupId}", rt.dishHandler.UpdateModifierGroup)
+				r.Delete("/{id}/modifiers/{groupId}", rt.dishHandler.DeleteModifierGroup)
+				r.Post("/{id}/modifiers/{groupId}/options", rt.dishHandler.CreateModifierOption)
+				r.Put("/{id}/modifiers/{groupId}/options/{optionId}", rt.dishHandler.UpdateModifierOption)
+				r.Delete("/{id}/modifiers/{groupId}/options/{optionId}", rt.dishHandler.DeleteModifierOption)
+			})
+		})
+
+		// Menu routes: расписание меню и цены блюд в них
+		r.Route("/api/menus", func(r chi.Router) {
+			r.Get("/", rt.menuHandler.GetAll)
+			r.Get("/current", rt.menuHandler.GetCurrent)
+			r.Get("/{id}", rt.menuHandler.GetByID)
+
+			// Admin only
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleAdmin))
+				r.Post("/", rt.menuHandler.Create)
+				r.Put("/{id}", rt.menuHandler.Update)
+				r.Delete("/{id}", rt.menuHandler.Delete)
+				r.Put("/{id}/dishes/{dishId}", rt.menuHandler.SetDish)
+				r.Delete("/{id}/dishes/{dishId}", rt.menuHandler.RemoveDish)
+			})
+		})
+
+		// Combo routes
+		r.Route("/api/combos", func(r chi.Router) {
+			r.Get("/", rt.comboHandler.GetAll)
+			r.Get("/{id}", rt.comboHandler.GetByID)
+
+			// Admin only
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleAdmin))
+				r.Post("/", rt.comboHandler.Create)
+				r.Put("/{id}", rt.comboHandler.Update)
+				r.Delete("/{id}", rt.comboHandler.Delete)
 			})
 		})
 
@@ -118,13 +206,84 @@ func (rt *Router) Setup() *chi.Mux {
 			r.With(middleware.RequireRole(domain.RoleWaiter, domain.RoleAdmin)).
 				Post("/", rt.orderHandler.Create)
 
-			// Waiter и Admin могут закрывать заказы (переводить в paid и освобождать стол)
-			r.With(middleware.RequireRole(domain.RoleWaiter, domain.RoleAdmin)).
+			// Официант закрывает заказы своей секции, менеджер и админ — любые
+			r.With(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin)).
 				Put("/{id}/close", rt.orderHandler.CloseOrder)
 
+			// Счёт: разделение между гостями и оплата частями
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin))
+				r.Get("/{id}/bill", rt.paymentHandler.GetBill)
+				r.Post("/{id}/split", rt.paymentHandler.Split)
+				r.Post("/{id}/payments", rt.paymentHandler.Pay)
+			})
+
+			// Промокод может ввести официант, разовые скидки (comp) и сервисный сбор — только менеджер
+			r.With(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin)).
+				Put("/{id}/promo-code", rt.orderHandler.ApplyPromoCode)
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleManager, domain.RoleAdmin))
+				r.Post("/{id}/discounts", rt.orderHandler.AddComp)
+				r.Delete("/{id}/discounts/{discountId}", rt.orderHandler.RemoveDiscount)
+				r.Put("/{id}/service-charge", rt.orderHandler.SetServiceCharge)
+			})
+
+			// Пересадка гостей и объединение столов
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin))
+				r.Put("/{id}/table", rt.orderHandler.MoveOrder)
+				r.Post("/{id}/merge", rt.orderHandler.MergeOrders)
+			})
+
+			// Waiter и Admin могут менять состав открытого заказа
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleWaiter, domain.RoleAdmin))
+				r.Post("/{id}/items", rt.orderHandler.AddItem)
+				r.Put("/{id}/items/{itemId}", rt.orderHandler.UpdateItem)
+				r.Delete("/{id}/items/{itemId}", rt.orderHandler.RemoveItem)
+				r.Post("/{id}/combos", rt.orderHandler.AddCombo)
+				r.Delete("/{id}/combos/{lineId}", rt.orderHandler.RemoveCombo)
+			})
+
+			// Отмена заказа и позиций; после начала готовки — с подтверждением менеджера
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin))
+				r.Post("/{id}/cancel", rt.orderHandler.CancelOrder)
+				r.Post("/{id}/items/{itemId}/void", rt.orderHandler.VoidItem)
+				r.Get("/{id}/voids", rt.orderHandler.GetVoids)
+			})
+
 			// Cook, Manager и Admin могут менять статус заказов
 			r.With(middleware.RequireRole(domain.RoleCook, domain.RoleManager, domain.RoleAdmin)).
 				Put("/{id}/status", rt.orderHandler.UpdateStatus)
+
+			// Cook ведёт позиции на кухне, Waiter отмечает подачу (served)
+			r.With(middleware.RequireRole(domain.RoleCook, domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin)).
+				Put("/{id}/items/{itemId}/status", rt.orderHandler.UpdateItemStatus)
+		})
+
+		// Void approval routes (Manager and Admin only)
+		r.Route("/api/voids", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleManager, domain.RoleAdmin))
+			r.Get("/pending", rt.orderHandler.GetPendingVoids)
+			r.Put("/{id}/approve", rt.orderHandler.ApproveVoid)
+			r.Put("/{id}/reject", rt.orderHandler.RejectVoid)
+		})
+
+		// Promotion routes (Manager and Admin only)
+		r.Route("/api/promotions", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.promotionHandler.GetAll)
+			r.Get("/{id}", rt.promotionHandler.GetByID)
+			r.Post("/", rt.promotionHandler.Create)
+			r.Put("/{id}", rt.promotionHandler.Update)
+			r.Delete("/{id}", rt.promotionHandler.Delete)
+		})
+
+		// Kitchen display routes
+		r.Route("/api/kitchen", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleCook, domain.RoleManager, domain.RoleAdmin))
+			r.Get("/queue", rt.orderHandler.GetKitchenQueue)
 		})
 
 		// Ingredient routes (Admin only)
@@ -132,28 +291,134 @@ func (rt *Router) Setup() *chi.Mux {
 			r.Use(middleware.RequireRole(domain.RoleAdmin))
 			r.Get("/", rt.ingredientHandler.GetAll)
 			r.Get("/low-stock", rt.ingredientHandler.GetLowStock)
+			r.Get("/movements", rt.ingredientHandler.GetMovements)
 			r.Get("/{id}", rt.ingredientHandler.GetByID)
+			r.Get("/{id}/movements", rt.ingredientHandler.GetMovements)
 			r.Post("/", rt.ingredientHandler.Create)
 			r.Put("/{id}", rt.ingredientHandler.Update)
 			r.Delete("/{id}", rt.ingredientHandler.Delete)
 		})
 
+		// Prep item routes: рецепты заготовок ведёт админ, партии выпускает кухня
+		r.Route("/api/preps", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleCook, domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.prepHandler.GetAll)
+			r.Get("/batches", rt.prepHandler.GetBatches)
+			r.Get("/{id}", rt.prepHandler.GetByID)
+			r.Get("/{id}/batches", rt.prepHandler.GetBatches)
+			r.Post("/{id}/batches", rt.prepHandler.Produce)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleAdmin))
+				r.Put("/{id}", rt.prepHandler.Save)
+				r.Delete("/{id}", rt.prepHandler.Delete)
+			})
+		})
+
+		// Stocktake routes: считает кухня, открывает и проводит менеджер
+		r.Route("/api/stocktakes", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleCook, domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.stocktakeHandler.GetAll)
+			r.Get("/{id}", rt.stocktakeHandler.GetByID)
+			r.Put("/{id}/counts", rt.stocktakeHandler.SaveCounts)
+			r.Delete("/{id}/counts/{ingredientId}", rt.stocktakeHandler.RemoveCount)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleManager, domain.RoleAdmin))
+				r.Post("/", rt.stocktakeHandler.Start)
+				r.Post("/{id}/post", rt.stocktakeHandler.Post)
+				r.Post("/{id}/cancel", rt.stocktakeHandler.Cancel)
+			})
+		})
+
+		// Списания: записывает кухня, журнал смотрят все, кто ведёт склад
+		r.Route("/api/waste", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleCook, domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.wasteHandler.GetAll)
+			r.Get("/{id}", rt.wasteHandler.GetByID)
+			r.Post("/", rt.wasteHandler.Log)
+		})
+
 		// Supply routes (Admin only)
 		r.Route("/api/supplies", func(r chi.Router) {
 			r.Use(middleware.RequireRole(domain.RoleAdmin))
 			r.Get("/", rt.supplyHandler.GetAll)
 			r.Post("/", rt.supplyHandler.Create)
+			r.Get("/{id}", rt.supplyHandler.GetByID)
+			r.Post("/{id}/invoice", rt.supplyHandler.UploadInvoice)
+		})
+
+		// Поставщики и заказы им ведут менеджер и админ
+		r.Route("/api/suppliers", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.supplierHandler.GetAll)
+			r.Get("/{id}", rt.supplierHandler.GetByID)
+			r.Post("/", rt.supplierHandler.Create)
+			r.Put("/{id}", rt.supplierHandler.Update)
+			r.Delete("/{id}", rt.supplierHandler.Delete)
+			r.Put("/{id}/catalog/{ingredientId}", rt.supplierHandler.SaveCatalogItem)
+			r.Delete("/{id}/catalog/{ingredientId}", rt.supplierHandler.RemoveCatalogItem)
+		})
+
+		r.Route("/api/purchase-orders", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.purchaseHandler.GetAll)
+			r.Get("/{id}", rt.purchaseHandler.GetByID)
+			r.Post("/", rt.purchaseHandler.Create)
+			r.Put("/{id}", rt.purchaseHandler.Update)
+			r.Post("/{id}/send", rt.purchaseHandler.Send)
+			r.Post("/{id}/receive", rt.purchaseHandler.Receive)
+			r.Post("/{id}/cancel", rt.purchaseHandler.Cancel)
 		})
 
 		// Table routes
+		// Бронирования ведут официанты (хостес), менеджеры и админ
+		r.Route("/api/reservations", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.reservationHandler.GetAll)
+			r.Get("/availability", rt.reservationHandler.GetAvailability)
+			r.Get("/{id}", rt.reservationHandler.GetByID)
+			r.Post("/", rt.reservationHandler.Create)
+			r.Put("/{id}", rt.reservationHandler.Update)
+			r.Post("/{id}/cancel", rt.reservationHandler.Cancel)
+			r.Post("/{id}/seat", rt.reservationHandler.Seat)
+		})
+
+		// Очередь гостей без брони
+		r.Route("/api/waitlist", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.waitlistHandler.GetQueue)
+			r.Get("/estimate", rt.waitlistHandler.Estimate)
+			r.Post("/", rt.waitlistHandler.Add)
+			r.Post("/{id}/cancel", rt.waitlistHandler.Cancel)
+			r.Post("/{id}/seat", rt.waitlistHandler.Seat)
+		})
+
+		// Секции официантов на смену и передача столов при пересменке
+		r.Route("/api/sections", func(r chi.Router) {
+			r.Use(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin))
+			r.Get("/", rt.sectionHandler.GetSections)
+			r.Post("/handover", rt.sectionHandler.Handover)
+			r.With(middleware.RequireRole(domain.RoleManager, domain.RoleAdmin)).
+				Put("/{waiterId}", rt.sectionHandler.Assign)
+		})
+
 		r.Route("/api/tables", func(r chi.Router) {
 			r.Get("/", rt.tableHandler.GetAll)
+			r.Get("/floor", rt.tableHandler.GetFloor)
 			r.Get("/{id}", rt.tableHandler.GetByID)
 
-			// Waiter и Admin могут обновлять статус стола
-			r.With(middleware.RequireRole(domain.RoleWaiter, domain.RoleAdmin)).
+			// Официант меняет статус столов своей секции, менеджер и админ — любых
+			r.With(middleware.RequireRole(domain.RoleWaiter, domain.RoleManager, domain.RoleAdmin)).
 				Put("/{id}/status", rt.tableHandler.UpdateStatus)
 
+			// Расстановку зала правят менеджер и админ
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(domain.RoleManager, domain.RoleAdmin))
+				r.Put("/layout", rt.tableHandler.UpdateLayout)
+				r.Put("/{id}", rt.tableHandler.Update)
+			})
+
 			// Admin only
 			r.Group(func(r chi.Router) {
 				r.Use(middleware.RequireRole(domain.RoleAdmin))
@@ -177,19 +442,30 @@ func (rt *Router) Setup() *chi.Mux {
 			r.Get("/sales/summary", rt.analyticsHandler.GetSalesSummary)
 			r.Get("/sales/by-category", rt.analyticsHandler.GetSalesByCategory)
 			r.Get("/sales/hourly", rt.analyticsHandler.GetHourlyRevenue)
+			r.Get("/sales/by-payment-method", rt.analyticsHandler.GetRevenueByPaymentMethod)
+			r.Get("/sales/discounts", rt.analyticsHandler.GetDiscountCost)
 
 			// Dishes analytics
 			r.Get("/dishes/popular", rt.analyticsHandler.GetPopularDishes)
 			r.Get("/dishes/availability", rt.analyticsHandler.GetDishAvailability)
+			r.Get("/dishes/costs", rt.analyticsHandler.GetDishCosts)
+			r.Get("/categories/margins", rt.analyticsHandler.GetCategoryMargins)
+			r.Get("/menu-engineering", rt.analyticsHandler.GetMenuEngineering)
 
 			// Orders analytics
 			r.Get("/orders/stats", rt.analyticsHandler.GetOrderStats)
 
 			// Staff analytics
 			r.Get("/waiters/performance", rt.analyticsHandler.GetWaiterPerformance)
+			r.Get("/waiters/tips", rt.analyticsHandler.GetTipsByWaiter)
 
 			// Inventory analytics
 			r.Get("/ingredients/turnover", rt.analyticsHandler.GetIngredientTurnover)
+			r.Get("/waste", rt.analyticsHandler.GetWasteReport)
+
+			// Purchasing analytics
+			r.Get("/spend", rt.analyticsHandler.GetSpendReport)
+			r.Get("/ingredients/{id}/price-history", rt.analyticsHandler.GetPriceHistory)
 
 			// Tables analytics
 			r.Get("/tables/utilization", rt.analyticsHandler.GetTableUtilization)
@@ -12,7 +12,11 @@ type DashboardData struct {
 
 // SalesSummary contains key metrics for sales
 type SalesSummary struct {
-	TotalRevenue      float64 `json:"total_revenue"`
+	Subtotal          float64 `json:"subtotal"` // before discounts
+	Discounts         float64 `json:"discounts"`
+	Tax               float64 `json:"tax"`
+	ServiceCharge     float64 `json:"service_charge"`
+	TotalRevenue      float64 `json:"total_revenue"` // grand total: subtotal - discounts + tax + service charge
 	TotalOrders       int     `json:"total_orders"`
 	AverageOrderValue float64 `json:"average_order_value"`
 	RevenueChange     float64 `json:"revenue_change"`   // % change from previous period
@@ -45,6 +49,36 @@ type WaiterPerformance struct {
 	AvgCheck   float64 `json:"avg_check"`
 }
 
+// PaymentMethodRevenue represents payments received with one payment method
+type PaymentMethodRevenue struct {
+	Method         PaymentMethod `json:"method"`
+	PaymentCount   int           `json:"payment_count"`
+	Revenue        float64       `json:"revenue"` // applied to order bills
+	Tips           float64       `json:"tips"`
+	ServiceCharges float64       `json:"service_charges"` // part of Revenue, not on top of it
+	Percentage     float64       `json:"percentage"`      // percentage of total revenue
+}
+
+// WaiterTips represents tips collected on a waiter's orders
+type WaiterTips struct {
+	WaiterID   int     `json:"waiter_id"`
+	WaiterName string  `json:"waiter_name"`
+	OrderCount int     `json:"order_count"` // orders with at least one tip
+	Tips       float64 `json:"tips"`
+	AvgTip     float64 `json:"avg_tip"` // per tipped order
+}
+
+// DiscountCost represents revenue given away by one promotion or by manager comps
+type DiscountCost struct {
+	PromotionID  *int           `json:"promotion_id,omitempty"`
+	Name         string         `json:"name"`
+	Source       DiscountSource `json:"source"`
+	TimesApplied int            `json:"times_applied"`
+	OrderCount   int            `json:"order_count"`
+	Amount       float64        `json:"amount"`
+	Percentage   float64        `json:"percentage"` // percentage of all discounts
+}
+
 // OrderStats represents order statistics
 type OrderStats struct {
 	TotalOrders     int     `json:"total_orders"`
@@ -2,7 +2,12 @@
 
 package domain
 
+// DefaultStation is the kitchen station used when a category has none
+const DefaultStation = "kitchen"
+
 type Category struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID      int     `json:"id"`
+	Name    string  `json:"name"`
+	Station string  `json:"station"`  // kitchen station that prepares dishes of this category
+	VatRate float64 `json:"vat_rate"` // percent, used by dishes without their own rate
 }
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Combo is a set of dishes sold at a bundle price: one dish is chosen in every slot
+type Combo struct {
+	ID          int         `json:"id"`
+	Name        string      `json:"name"`
+	Description *string     `json:"description,omitempty"`
+	Price       float64     `json:"price"`
+	PhotoURL    *string     `json:"photo_url,omitempty"`
+	IsActive    bool        `json:"is_active"`
+	Slots       []ComboSlot `json:"slots"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// IsValid checks the combo has a name, a price and at least one slot,
+// and that every slot offers at least one dish, each dish once.
+func (c *Combo) IsValid() bool {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" || c.Price < 0 || len(c.Slots) == 0 {
+		return false
+	}
+
+	for i := range c.Slots {
+		slot := &c.Slots[i]
+		slot.Name = strings.TrimSpace(slot.Name)
+		if slot.Name == "" || len(slot.Choices) == 0 {
+			return false
+		}
+
+		seen := make(map[int]bool, len(slot.Choices))
+		for _, choice := range slot.Choices {
+			if choice.DishID <= 0 || choice.PriceDelta < 0 || seen[choice.DishID] {
+				return false
+			}
+			seen[choice.DishID] = true
+		}
+	}
+	return true
+}
+
+// ComboSlot is one course of the combo ("Pizza", "Drink", "Dessert")
+type ComboSlot struct {
+	ID       int           `json:"id"`
+	ComboID  int           `json:"combo_id"`
+	Name     string        `json:"name"`
+	Position int           `json:"position"`
+	Choices  []ComboChoice `json:"choices"`
+}
+
+// Choice returns the slot's choice for the dish
+func (s *ComboSlot) Choice(dishID int) (ComboChoice, bool) {
+	for _, c := range s.Choices {
+		if c.DishID == dishID {
+			return c, true
+		}
+	}
+	return ComboChoice{}, false
+}
+
+// ComboChoice is a dish allowed in a slot; PriceDelta is the surcharge for choosing it
+type ComboChoice struct {
+	SlotID     int     `json:"slot_id"`
+	DishID     int     `json:"dish_id"`
+	PriceDelta float64 `json:"price_delta"`
+	Dish       *Dish   `json:"dish,omitempty"`
+}
+
+// OrderCombo is a combo ordered as one line of the bill. The kitchen, the stock
+// and the dish analytics work with its component items; each component's price
+// is its share of the bundle price, so the components add up to Price * Qty.
+type OrderCombo struct {
+	ID        int       `json:"id"`
+	OrderID   int       `json:"order_id"`
+	ComboID   *int      `json:"combo_id,omitempty"`
+	Name      string    `json:"name"`
+	Qty       int       `json:"qty"`
+	Price     float64   `json:"price"` // one set, with surcharges and modifiers
+	Seat      *int      `json:"seat,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Choices is the request side: the dish picked for each slot
+	Choices []ComboSelection `json:"choices,omitempty"`
+	// Items are the component order items
+	Items []OrderItem `json:"items,omitempty"`
+}
+
+// ComboSelection is the dish a guest picked for a slot, with its modifiers
+type ComboSelection struct {
+	SlotID    int                 `json:"slot_id"`
+	DishID    int                 `json:"dish_id"`
+	Notes     *string             `json:"notes,omitempty"`
+	Modifiers []OrderItemModifier `json:"modifiers,omitempty"`
+}
@@ -0,0 +1,181 @@
+package domain
+
+import "math"
+
+// Cost returns what the ingredient in one portion of the dish costs
+func (di *DishIngredient) Cost() float64 {
+	if di.Ingredient == nil {
+		return 0
+	}
+	return di.QtyPerDish * di.Ingredient.UnitCost
+}
+
+// DishCost is the recipe cost of a dish against its menu price
+type DishCost struct {
+	DishID       int              `json:"dish_id"`
+	DishName     string           `json:"dish_name"`
+	CategoryID   int              `json:"category_id"`
+	CategoryName string           `json:"category_name"`
+	Price        float64          `json:"price"`
+	Cost         float64          `json:"cost"`          // ingredients of one portion
+	FoodCostPct  float64          `json:"food_cost_pct"` // cost as % of price
+	Margin       float64          `json:"margin"`        // price - cost
+	MarginPct    float64          `json:"margin_pct"`
+	HasRecipe    bool             `json:"has_recipe"` // false: no ingredients linked, the cost is unknown
+	Ingredients  []DishIngredient `json:"ingredients,omitempty"`
+}
+
+// NewDishCost prices the recipe of the dish
+func NewDishCost(dish *Dish, recipe []DishIngredient) DishCost {
+	dc := DishCost{
+		DishID:      dish.ID,
+		DishName:    dish.Name,
+		CategoryID:  dish.CategoryID,
+		Price:       dish.Price,
+		HasRecipe:   len(recipe) > 0,
+		Ingredients: recipe,
+	}
+	if dish.Category != nil {
+		dc.CategoryName = dish.Category.Name
+	}
+	for i := range recipe {
+		dc.Cost += recipe[i].Cost()
+	}
+	dc.Cost = roundCents(dc.Cost)
+	dc.Margin = roundCents(dc.Price - dc.Cost)
+	dc.FoodCostPct = percentOf(dc.Cost, dc.Price)
+	dc.MarginPct = percentOf(dc.Margin, dc.Price)
+	return dc
+}
+
+// CategoryMargin sums up the recipe costs of the dishes in one category
+type CategoryMargin struct {
+	CategoryID   int     `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	DishCount    int     `json:"dish_count"`
+	AvgPrice     float64 `json:"avg_price"`
+	AvgCost      float64 `json:"avg_cost"`
+	FoodCostPct  float64 `json:"food_cost_pct"`
+	AvgMargin    float64 `json:"avg_margin"`
+	MarginPct    float64 `json:"margin_pct"`
+}
+
+// MarginsByCategory groups dish costs by category; dishes without a recipe are left out
+func MarginsByCategory(costs []DishCost) []CategoryMargin {
+	var margins []CategoryMargin
+	index := make(map[int]int)
+	for _, dc := range costs {
+		if !dc.HasRecipe {
+			continue
+		}
+		i, ok := index[dc.CategoryID]
+		if !ok {
+			i = len(margins)
+			index[dc.CategoryID] = i
+			margins = append(margins, CategoryMargin{CategoryID: dc.CategoryID, CategoryName: dc.CategoryName})
+		}
+		m := &margins[i]
+		m.DishCount++
+		m.AvgPrice += dc.Price
+		m.AvgCost += dc.Cost
+	}
+
+	for i := range margins {
+		m := &margins[i]
+		price, cost := m.AvgPrice, m.AvgCost
+		n := float64(m.DishCount)
+		m.AvgPrice = roundCents(price / n)
+		m.AvgCost = roundCents(cost / n)
+		m.AvgMargin = roundCents((price - cost) / n)
+		m.FoodCostPct = percentOf(cost, price)
+		m.MarginPct = percentOf(price-cost, price)
+	}
+	return margins
+}
+
+// MenuClass is the menu-engineering quadrant of a dish
+type MenuClass string
+
+const (
+	MenuStar      MenuClass = "star"      // popular and profitable: keep as is
+	MenuPlowhorse MenuClass = "plowhorse" // popular, low margin: reprice or cheapen the recipe
+	MenuPuzzle    MenuClass = "puzzle"    // profitable, sells poorly: promote or reposition
+	MenuDog       MenuClass = "dog"       // neither: candidate to drop
+)
+
+// MenuEngineeringItem is one dish of the menu-engineering report
+type MenuEngineeringItem struct {
+	DishID        int       `json:"dish_id"`
+	DishName      string    `json:"dish_name"`
+	CategoryName  string    `json:"category_name"`
+	QtySold       int       `json:"qty_sold"`
+	Revenue       float64   `json:"revenue"`
+	AvgPrice      float64   `json:"avg_price"` // actually charged, incl. combo shares
+	UnitCost      float64   `json:"unit_cost"`
+	UnitMargin    float64   `json:"unit_margin"`
+	TotalMargin   float64   `json:"total_margin"`
+	FoodCostPct   float64   `json:"food_cost_pct"`
+	PopularityPct float64   `json:"popularity_pct"` // share of all portions sold
+	Class         MenuClass `json:"class"`
+}
+
+// MenuEngineering is the Kasavana–Smith matrix over a period
+type MenuEngineering struct {
+	TotalSold       int                   `json:"total_sold"`
+	PopularityLimit float64               `json:"popularity_limit"` // % of sales a dish needs to count as popular
+	AvgUnitMargin   float64               `json:"avg_unit_margin"`  // sales-weighted, the profitability bar
+	Items           []MenuEngineeringItem `json:"items"`
+}
+
+// ClassifyMenu puts every dish in a quadrant. A dish is popular when its share
+// of portions sold reaches 70% of an even share (1/N); it is profitable when
+// its unit margin reaches the sales-weighted average margin.
+func ClassifyMenu(items []MenuEngineeringItem) MenuEngineering {
+	report := MenuEngineering{Items: items}
+	if len(items) == 0 {
+		return report
+	}
+
+	totalMargin := 0.0
+	for _, it := range items {
+		report.TotalSold += it.QtySold
+		totalMargin += it.TotalMargin
+	}
+	report.PopularityLimit = roundCents(70.0 / float64(len(items)))
+	if report.TotalSold > 0 {
+		report.AvgUnitMargin = roundCents(totalMargin / float64(report.TotalSold))
+	}
+
+	for i := range items {
+		it := &items[i]
+		it.FoodCostPct = percentOf(it.UnitCost, it.AvgPrice)
+		if report.TotalSold > 0 {
+			it.PopularityPct = roundCents(float64(it.QtySold) * 100 / float64(report.TotalSold))
+		}
+		popular := report.TotalSold > 0 && it.PopularityPct >= report.PopularityLimit
+		profitable := it.UnitMargin >= report.AvgUnitMargin
+
+		switch {
+		case popular && profitable:
+			it.Class = MenuStar
+		case popular:
+			it.Class = MenuPlowhorse
+		case profitable:
+			it.Class = MenuPuzzle
+		default:
+			it.Class = MenuDog
+		}
+	}
+	return report
+}
+
+func percentOf(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return roundCents(part * 100 / whole)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -8,7 +8,37 @@ type Dish struct {
 	Price       float64   `json:"price"`
 	PhotoURL    *string   `json:"photo_url,omitempty"`
 	IsActive    bool      `json:"is_active"`
+	VatRate     *float64  `json:"vat_rate,omitempty"` // overrides the category rate
+	SoldOut     bool      `json:"sold_out"`           // 86'd by the kitchen by hand
 	Category    *Category `json:"category,omitempty"`
+
+	// PortionsLeft is how many portions the free stock is enough for;
+	// nil for dishes without a recipe. Available is false when the dish
+	// is switched off, 86'd or out of stock.
+	PortionsLeft *int `json:"portions_left,omitempty"`
+	Available    bool `json:"available"`
+
+	ModifierGroups []ModifierGroup `json:"modifier_groups,omitempty"`
+}
+
+// SetPortionsLeft fills PortionsLeft and Available from the portions computed by stock
+func (d *Dish) SetPortionsLeft(portions map[int]int) {
+	d.PortionsLeft = nil
+	if left, ok := portions[d.ID]; ok {
+		d.PortionsLeft = &left
+	}
+	d.Available = d.IsActive && !d.SoldOut && (d.PortionsLeft == nil || *d.PortionsLeft > 0)
+}
+
+// EffectiveVatRate returns the dish's own VAT rate or, failing that, its category's
+func (d *Dish) EffectiveVatRate() float64 {
+	if d.VatRate != nil {
+		return *d.VatRate
+	}
+	if d.Category != nil {
+		return d.Category.VatRate
+	}
+	return 0
 }
 
 type DishIngredient struct {
@@ -12,13 +12,110 @@ var (
 var ErrCategoryNotFound = errors.New("category not found")
 
 // Dish errors
-var ErrDishNotFound = errors.New("dish not found")
+var (
+	ErrDishNotFound     = errors.New("dish not found")
+	ErrDishNotAvailable = errors.New("dish is not on any menu open now")
+	ErrDishSoldOut      = errors.New("dish is sold out")
+)
+
+// Menu errors
+var (
+	ErrMenuNotFound = errors.New("menu not found")
+	ErrInvalidMenu  = errors.New("invalid menu")
+)
+
+// Reservation errors
+var (
+	ErrReservationNotFound  = errors.New("reservation not found")
+	ErrInvalidReservation   = errors.New("invalid reservation")
+	ErrReservationConflict  = errors.New("table is already booked for this time")
+	ErrTableTooSmall        = errors.New("table is too small for the party")
+	ErrReservationNotBooked = errors.New("reservation is no longer active")
+	ErrReservationInThePast = errors.New("reservation time is in the past")
+)
+
+// Modifier errors
+var (
+	ErrModifierGroupNotFound    = errors.New("modifier group not found")
+	ErrModifierOptionNotFound   = errors.New("modifier option not found")
+	ErrInvalidModifier          = errors.New("invalid modifier group or option")
+	ErrInvalidModifierSelection = errors.New("selected modifiers do not match the dish options")
+)
+
+// Combo errors
+var (
+	ErrComboNotFound        = errors.New("combo not found")
+	ErrInvalidCombo         = errors.New("invalid combo")
+	ErrInvalidComboChoice   = errors.New("selected dishes do not match the combo slots")
+	ErrComboLineNotFound    = errors.New("combo line not found")
+	ErrComboItemNotEditable = errors.New("item is part of a combo; change the combo line instead")
+)
+
+// Waitlist errors
+var (
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrInvalidWaitlistEntry  = errors.New("invalid waitlist entry")
+	ErrWaitlistEntryClosed   = errors.New("party is no longer waiting")
+	ErrNoTableForParty       = errors.New("no table can seat the party")
+)
+
+// Section errors
+var (
+	ErrNotYourTable       = errors.New("table is assigned to another waiter")
+	ErrNotYourOrder       = errors.New("order belongs to another waiter")
+	ErrInvalidSection     = errors.New("invalid section")
+	ErrInvalidHandover    = errors.New("invalid handover")
+	ErrHandoverNotAllowed = errors.New("only a manager or the waiter leaving can hand over tables")
+)
+
+// Tax errors
+var ErrInvalidRate = errors.New("rate must be between 0 and 100")
 
 // Order errors
 var (
 	ErrOrderNotFound       = errors.New("order not found")
 	ErrInsufficientStock   = errors.New("insufficient stock for ingredient")
 	ErrInvalidStatusChange = errors.New("invalid status change")
+	ErrOrderItemNotFound   = errors.New("order item not found")
+	ErrOrderNotEditable    = errors.New("order can no longer be edited")
+	ErrLastOrderItem       = errors.New("order must have at least one item")
+)
+
+// Payment errors
+var (
+	ErrOrderNotReady         = errors.New("order must be in ready status to be paid")
+	ErrOutstandingBalance    = errors.New("order has an outstanding balance")
+	ErrPaymentExceedsBalance = errors.New("payment exceeds the remaining balance")
+	ErrInsufficientTender    = errors.New("tendered amount is less than payment amount")
+	ErrInvalidPayment        = errors.New("invalid payment")
+	ErrInvalidSplit          = errors.New("invalid bill split")
+	ErrSplitNotFound         = errors.New("bill split not found")
+	ErrBillHasPayments       = errors.New("bill already has payments")
+)
+
+// Move and merge errors
+var (
+	ErrInvalidMerge    = errors.New("nothing to merge")
+	ErrNoOpenOrder     = errors.New("table has no open order")
+	ErrHasPendingVoids = errors.New("order has pending void requests")
+)
+
+// Promotion errors
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrInvalidPromotion  = errors.New("invalid promotion")
+	ErrInvalidPromoCode  = errors.New("promo code is invalid or not active")
+	ErrPromoCodeExists   = errors.New("promo code is already used by another promotion")
+	ErrDiscountNotFound  = errors.New("discount not found")
+	ErrInvalidDiscount   = errors.New("invalid discount")
+)
+
+// Void errors
+var (
+	ErrVoidNotFound     = errors.New("void not found")
+	ErrVoidNotPending   = errors.New("void is already resolved")
+	ErrVoidQtyExceeded  = errors.New("void quantity exceeds item quantity")
+	ErrInvalidVoidInput = errors.New("invalid void reason or stock action")
 )
 
 // User errors
@@ -31,10 +128,55 @@ var (
 var (
 	ErrTableNotFound = errors.New("table not found")
 	ErrTableBusy     = errors.New("table is busy")
+	ErrInvalidTable  = errors.New("invalid table")
 )
 
 // Ingredient errors
-var ErrIngredientNotFound = errors.New("ingredient not found")
+var (
+	ErrIngredientNotFound = errors.New("ingredient not found")
+	ErrIngredientInUse    = errors.New("ingredient has stock history and cannot be deleted")
+)
 
 // Supply errors
-var ErrSupplyNotFound = errors.New("supply not found")
+var (
+	ErrSupplyNotFound  = errors.New("supply not found")
+	ErrInvalidCurrency = errors.New("currency must be a 3-letter ISO 4217 code")
+)
+
+// Supplier errors
+var (
+	ErrSupplierNotFound = errors.New("supplier not found")
+	ErrInvalidSupplier  = errors.New("invalid supplier")
+	ErrSupplierInactive = errors.New("supplier is inactive")
+)
+
+// Purchase order errors
+var (
+	ErrPurchaseOrderNotFound = errors.New("purchase order not found")
+	ErrInvalidPurchaseOrder  = errors.New("invalid purchase order")
+	ErrPurchaseOrderStatus   = errors.New("purchase order cannot be changed in its current status")
+	ErrInvalidReceipt        = errors.New("received ingredients must be on the order with qty > 0")
+)
+
+// Prep item errors
+var (
+	ErrPrepItemNotFound = errors.New("prep item not found")
+	ErrInvalidPrepItem  = errors.New("invalid prep item")
+	ErrPrepCycle        = errors.New("prep item cannot contain itself")
+	ErrPrepMadeToOrder  = errors.New("made-to-order prep item is not produced in batches")
+)
+
+// Waste errors
+var (
+	ErrWasteEntryNotFound = errors.New("waste entry not found")
+	ErrInvalidWasteEntry  = errors.New("invalid waste entry")
+)
+
+// Stocktake errors
+var (
+	ErrStocktakeNotFound     = errors.New("stocktake not found")
+	ErrStocktakeLineNotFound = errors.New("ingredient is not counted in this stocktake")
+	ErrStocktakeOpen         = errors.New("another stocktake is already open")
+	ErrStocktakeClosed       = errors.New("stocktake is already posted or cancelled")
+	ErrInvalidStocktakeCount = errors.New("counted quantity must be zero or more")
+)
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventOrderCreated           EventType = "order.created"
+	EventOrderStatusChanged     EventType = "order.status_changed"
+	EventOrderReady             EventType = "order.ready"
+	EventOrderItemsChanged      EventType = "order.items_changed"
+	EventOrderItemStatusChanged EventType = "order.item_status_changed"
+	EventOrderClosed            EventType = "order.closed"
+	EventOrderMoved             EventType = "order.moved" // the order now belongs to another table
+	EventTableStatusChanged     EventType = "table.status_changed"
+	EventReservationChanged     EventType = "reservation.changed"
+	EventWaitlistChanged        EventType = "waitlist.changed"
+	EventWaitlistTableReady     EventType = "waitlist.table_ready" // a freed table fits the next walk-in party
+	EventVoidRequested          EventType = "void.requested"
+	EventStockChanged           EventType = "stock.changed"             // ingredients were received or corrected
+	EventDishAvailability       EventType = "dish.availability_changed" // a dish ran out or is back on sale
+)
+
+// Event is a change in orders or tables pushed to connected clients
+type Event struct {
+	Type      EventType `json:"type"`
+	OrderID   int       `json:"order_id,omitempty"`
+	ItemID    int       `json:"item_id,omitempty"`
+	DishID    int       `json:"dish_id,omitempty"`
+	TableID   int       `json:"table_id,omitempty"`
+	WaiterID  int       `json:"waiter_id,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// VisibleTo reports whether a user with the given role should receive the event.
+// Cooks follow the kitchen flow, waiters hear that their own orders are ready and
+// that a table is free for a waiting party, both hear when a dish runs out,
+// managers and admins get everything.
+func (e Event) VisibleTo(role Role, userID int) bool {
+	switch role {
+	case RoleAdmin, RoleManager:
+		return true
+	case RoleCook:
+		switch e.Type {
+		case EventOrderCreated, EventOrderStatusChanged, EventOrderItemsChanged, EventOrderItemStatusChanged,
+			EventOrderMoved, EventDishAvailability:
+			return true
+		}
+		return false
+	case RoleWaiter:
+		return e.Type == EventOrderReady && e.WaiterID == userID || e.Type == EventWaitlistTableReady ||
+			e.Type == EventDishAvailability
+	default:
+		return false
+	}
+}
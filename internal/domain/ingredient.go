@@ -1,13 +1,33 @@
 package domain
 
 type Ingredient struct {
-	ID     int     `json:"id"`
-	Name   string  `json:"name"`
-	Unit   string  `json:"unit"`
-	Qty    float64 `json:"qty"`
-	MinQty float64 `json:"min_qty"`
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Unit        string  `json:"unit"`
+	Qty         float64 `json:"qty"`
+	ReservedQty float64 `json:"reserved_qty"` // held by orders that are not cooking yet
+	MinQty      float64 `json:"min_qty"`
+	UnitCost    float64 `json:"unit_cost"` // weighted average purchase price per unit
 }
 
 func (i *Ingredient) IsLowStock() bool {
 	return i.Qty <= i.MinQty
 }
+
+// Available returns the quantity that can still be promised to new orders
+func (i *Ingredient) Available() float64 {
+	return i.Qty - i.ReservedQty
+}
+
+// Receive averages the unit cost over the stock on hand and a delivery of qty
+// bought at price per unit
+func (i *Ingredient) Receive(qty, price float64) {
+	onHand := i.Qty
+	if onHand < 0 {
+		onHand = 0
+	}
+	if onHand+qty > 0 {
+		i.UnitCost = (onHand*i.UnitCost + qty*price) / (onHand + qty)
+	}
+	i.Qty += qty
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// KitchenItem is a single dish waiting on the kitchen display
+type KitchenItem struct {
+	ItemID         int             `json:"item_id"`
+	OrderID        int             `json:"order_id"`
+	TableNumber    int             `json:"table_number"`
+	TableName      string          `json:"table_name"`
+	DishID         int             `json:"dish_id"`
+	DishName       string          `json:"dish_name"`
+	Qty            int             `json:"qty"`
+	Notes          *string         `json:"notes,omitempty"`
+	Modifiers      []string        `json:"modifiers,omitempty"`
+	Combo          *string         `json:"combo,omitempty"` // name of the combo the dish belongs to
+	Status         OrderItemStatus `json:"status"`
+	Station        string          `json:"station"`
+	CreatedAt      time.Time       `json:"created_at"`
+	WaitingMinutes int             `json:"waiting_minutes"`
+}
+
+// KitchenStation groups queued and cooking items by station, oldest first
+type KitchenStation struct {
+	Station string        `json:"station"`
+	Items   []KitchenItem `json:"items"`
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Menu is a named set of dishes (breakfast, lunch, a seasonal menu) that is
+// orderable on the given days of the week and, if set, within a time window.
+// Days lists weekdays (0 = Sunday); empty means every day. StartTime/EndTime
+// are "HH:MM" and may wrap past midnight: the part after midnight still belongs
+// to the day the window opened. StartsAt/EndsAt bound a seasonal menu.
+type Menu struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	Days        []time.Weekday `json:"days,omitempty"`
+	StartTime   *string        `json:"start_time,omitempty"`
+	EndTime     *string        `json:"end_time,omitempty"`
+	StartsAt    *time.Time     `json:"starts_at,omitempty"`
+	EndsAt      *time.Time     `json:"ends_at,omitempty"`
+	IsActive    bool           `json:"is_active"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Dishes      []MenuDish     `json:"dishes,omitempty"`
+}
+
+// IsValid checks the name, the weekdays and the schedule bounds
+func (m *Menu) IsValid() bool {
+	m.Name = strings.TrimSpace(m.Name)
+	if m.Name == "" {
+		return false
+	}
+	for _, d := range m.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return false
+		}
+	}
+	if (m.StartTime == nil) != (m.EndTime == nil) {
+		return false
+	}
+	if m.StartTime != nil && (!isClock(*m.StartTime) || !isClock(*m.EndTime) || *m.StartTime == *m.EndTime) {
+		return false
+	}
+	if m.StartsAt != nil && m.EndsAt != nil && !m.EndsAt.After(*m.StartsAt) {
+		return false
+	}
+	return true
+}
+
+// IsOpenAt reports whether dishes can be ordered from the menu at t
+func (m *Menu) IsOpenAt(t time.Time) bool {
+	if !m.IsActive {
+		return false
+	}
+	if m.StartsAt != nil && t.Before(*m.StartsAt) {
+		return false
+	}
+	if m.EndsAt != nil && !t.Before(*m.EndsAt) {
+		return false
+	}
+	if m.StartTime == nil || m.EndTime == nil {
+		return m.servesOn(t.Weekday())
+	}
+
+	now := t.Format("15:04")
+	from, to := *m.StartTime, *m.EndTime
+	if from < to {
+		return now >= from && now < to && m.servesOn(t.Weekday())
+	}
+	if now >= from {
+		return m.servesOn(t.Weekday())
+	}
+	// после полуночи окно ещё принадлежит вчерашнему дню
+	return now < to && m.servesOn(t.AddDate(0, 0, -1).Weekday())
+}
+
+func (m *Menu) servesOn(day time.Weekday) bool {
+	if len(m.Days) == 0 {
+		return true
+	}
+	for _, d := range m.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// DaysMask packs Days into a bit mask (bit 0 = Sunday); 0 means every day
+func (m *Menu) DaysMask() int {
+	mask := 0
+	for _, d := range m.Days {
+		mask |= 1 << uint(d)
+	}
+	return mask
+}
+
+// SetDaysMask unpacks a mask stored by DaysMask
+func (m *Menu) SetDaysMask(mask int) {
+	m.Days = nil
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if mask&(1<<uint(d)) != 0 {
+			m.Days = append(m.Days, d)
+		}
+	}
+}
+
+// MenuDish puts a dish on a menu. Price overrides the dish price on this menu;
+// nil keeps the dish price.
+type MenuDish struct {
+	MenuID int      `json:"menu_id"`
+	DishID int      `json:"dish_id"`
+	Price  *float64 `json:"price,omitempty"`
+	Dish   *Dish    `json:"dish,omitempty"`
+}
+
+// MenuSchedule is the set of menus that decides what can be ordered and at what price
+type MenuSchedule []Menu
+
+// Orderable reports whether the dish can be ordered at t and at what price.
+// A dish on no menu follows only its IsActive flag and keeps its own price.
+// The schedule must include inactive menus: a dish only on a switched-off menu
+// is not orderable.
+// A dish on menus is orderable while one of them is open; if several are open,
+// the guest gets the lowest of their prices.
+func (s MenuSchedule) Orderable(dish *Dish, t time.Time) (float64, bool) {
+	if !dish.IsActive {
+		return 0, false
+	}
+
+	scheduled, open := false, false
+	price := dish.Price
+	for i := range s {
+		for _, md := range s[i].Dishes {
+			if md.DishID != dish.ID {
+				continue
+			}
+			scheduled = true
+			if !s[i].IsOpenAt(t) {
+				continue
+			}
+
+			p := dish.Price
+			if md.Price != nil {
+				p = *md.Price
+			}
+			if !open || p < price {
+				price = p
+			}
+			open = true
+		}
+	}
+
+	if !scheduled {
+		return dish.Price, true
+	}
+	return price, open
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "strings"
+
+// ModifierGroup is a choice offered on a dish: a size, extras, removals.
+// A required group needs at least MinSelect options (at least one);
+// MaxSelect 0 means there is no upper limit.
+type ModifierGroup struct {
+	ID        int              `json:"id"`
+	DishID    int              `json:"dish_id"`
+	Name      string           `json:"name"`
+	Required  bool             `json:"required"`
+	MinSelect int              `json:"min_select"`
+	MaxSelect int              `json:"max_select"`
+	Position  int              `json:"position"`
+	Options   []ModifierOption `json:"options,omitempty"`
+}
+
+// IsValid checks the name and the selection limits, normalising MinSelect to Required
+func (g *ModifierGroup) IsValid() bool {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Required && g.MinSelect == 0 {
+		g.MinSelect = 1
+	}
+	if !g.Required {
+		g.MinSelect = 0
+	}
+	return g.Name != "" && g.MinSelect >= 0 && g.MaxSelect >= 0 &&
+		(g.MaxSelect == 0 || g.MaxSelect >= g.MinSelect)
+}
+
+// ModifierOption is one choice in a group. PriceDelta is added to the dish price,
+// Ingredients change the recipe for one portion.
+type ModifierOption struct {
+	ID          int                  `json:"id"`
+	GroupID     int                  `json:"group_id"`
+	Name        string               `json:"name"`
+	PriceDelta  float64              `json:"price_delta"`
+	IsActive    bool                 `json:"is_active"`
+	Position    int                  `json:"position"`
+	Ingredients []ModifierIngredient `json:"ingredients,omitempty"`
+}
+
+// ModifierIngredient changes the recipe per portion: positive adds, negative removes
+type ModifierIngredient struct {
+	OptionID     int         `json:"option_id"`
+	IngredientID int         `json:"ingredient_id"`
+	QtyDelta     float64     `json:"qty_delta"`
+	Ingredient   *Ingredient `json:"ingredient,omitempty"`
+}
+
+// OrderItemModifier is an option chosen for an order item. The names and the
+// price delta are copied when the item is added, like the item price.
+type OrderItemModifier struct {
+	ID          int     `json:"id"`
+	OrderItemID int     `json:"order_item_id"`
+	OptionID    *int    `json:"option_id,omitempty"`
+	GroupName   string  `json:"group_name"`
+	Name        string  `json:"name"`
+	PriceDelta  float64 `json:"price_delta"`
+}
+
+// ModifierNames returns the chosen options as the kitchen and the receipt show them
+func ModifierNames(modifiers []OrderItemModifier) []string {
+	names := make([]string, 0, len(modifiers))
+	for _, m := range modifiers {
+		names = append(names, m.Name)
+	}
+	return names
+}
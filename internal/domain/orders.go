@@ -9,6 +9,30 @@ const (
 	OrderInProgress OrderStatus = "in_progress"
 	OrderReady      OrderStatus = "ready"
 	OrderPaid       OrderStatus = "paid"
+	OrderCancelled  OrderStatus = "cancelled"
+	OrderMerged     OrderStatus = "merged" // items were moved into another order
+)
+
+// IsOpen reports whether the order is still being served at its table
+func (s OrderStatus) IsOpen() bool {
+	return s == OrderNew || s == OrderInProgress || s == OrderReady
+}
+
+// MergeRequest lists what to merge into an order: other orders and/or
+// the open orders of other tables
+type MergeRequest struct {
+	OrderIDs []int `json:"order_ids"`
+	TableIDs []int `json:"table_ids"`
+}
+
+// OrderItemStatus tracks a single dish through the kitchen
+type OrderItemStatus string
+
+const (
+	ItemQueued  OrderItemStatus = "queued"
+	ItemCooking OrderItemStatus = "cooking"
+	ItemDone    OrderItemStatus = "done"
+	ItemServed  OrderItemStatus = "served"
 )
 
 type Order struct {
@@ -16,23 +40,44 @@ type Order struct {
 	WaiterID    int         `json:"waiter_id"`
 	TableNumber int         `json:"table_number"`
 	Status      OrderStatus `json:"status"`
-	Total       float64     `json:"total"`
-	Notes       *string     `json:"notes,omitempty"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	Guests      *int        `json:"guests,omitempty"`
+	Subtotal    float64     `json:"subtotal"` // before discounts
+	Discount    float64     `json:"discount"`
+	Tax         float64     `json:"tax"`
+	// ServiceChargeRate is a percent of the discounted subtotal
+	ServiceChargeRate float64   `json:"service_charge_rate"`
+	ServiceCharge     float64   `json:"service_charge"`
+	Total             float64   `json:"total"` // grand total to be paid
+	PromoCode         *string   `json:"promo_code,omitempty"`
+	Notes             *string   `json:"notes,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 
 	// Relations
-	Items  []OrderItem `json:"items,omitempty"`
-	Waiter *User       `json:"waiter,omitempty"`
-	Table  *Table      `json:"table,omitempty"`
+	Items     []OrderItem     `json:"items,omitempty"`
+	Combos    []OrderCombo    `json:"combos,omitempty"`
+	Discounts []OrderDiscount `json:"discounts,omitempty"`
+	Waiter    *User           `json:"waiter,omitempty"`
+	Table     *Table          `json:"table,omitempty"`
 }
 
 type OrderItem struct {
-	ID      int     `json:"id"`
-	OrderID int     `json:"order_id"`
-	DishID  int     `json:"dish_id"`
-	Qty     int     `json:"qty"`
-	Price   float64 `json:"price"`
-	Notes   *string `json:"notes,omitempty"`
-	Dish    *Dish   `json:"dish,omitempty"`
+	ID      int `json:"id"`
+	OrderID int `json:"order_id"`
+	DishID  int `json:"dish_id"`
+	// ComboLineID is set when the dish was ordered as part of a combo
+	ComboLineID *int            `json:"combo_line_id,omitempty"`
+	Qty         int             `json:"qty"`
+	Price       float64         `json:"price"`
+	Discount    float64         `json:"discount"` // total discount on the line
+	VatRate     float64         `json:"vat_rate"` // fixed when the item is added
+	Tax         float64         `json:"tax"`
+	Notes       *string         `json:"notes,omitempty"`
+	Seat        *int            `json:"seat,omitempty"`
+	Status      OrderItemStatus `json:"status"`
+	// Modifiers are the options chosen for the dish; Price already includes their deltas
+	Modifiers []OrderItemModifier `json:"modifiers,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	Dish      *Dish               `json:"dish,omitempty"`
 }
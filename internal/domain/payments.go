@@ -0,0 +1,93 @@
+package domain
+
+import "time"
+
+type PaymentMethod string
+
+const (
+	PaymentCash     PaymentMethod = "cash"
+	PaymentCard     PaymentMethod = "card"
+	PaymentTransfer PaymentMethod = "transfer"
+)
+
+func (m PaymentMethod) IsValid() bool {
+	return m == PaymentCash || m == PaymentCard || m == PaymentTransfer
+}
+
+// SplitMode is how an order's bill is divided between payers
+type SplitMode string
+
+const (
+	SplitByItem SplitMode = "item"
+	SplitBySeat SplitMode = "seat"
+	SplitEqual  SplitMode = "equal"
+)
+
+// Payment is one payer's contribution to an order's bill
+type Payment struct {
+	ID            int           `json:"id"`
+	OrderID       int           `json:"order_id"`
+	SplitID       *int          `json:"split_id,omitempty"`
+	Method        PaymentMethod `json:"method"`
+	Amount        float64       `json:"amount"` // applied to the bill
+	Tip           float64       `json:"tip"`
+	ServiceCharge float64       `json:"service_charge"` // share of the order's service charge, included in Amount
+	Tendered      float64       `json:"tendered"`       // handed over by the guest
+	Change        float64       `json:"change"`
+	CreatedBy     int           `json:"created_by"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// Charged is what the guest pays: the bill amount plus tip. The service charge
+// is already part of the bill.
+func (p *Payment) Charged() float64 {
+	return p.Amount + p.Tip
+}
+
+// BillSplit is the part of the bill one payer is responsible for
+type BillSplit struct {
+	ID        int             `json:"id"`
+	OrderID   int             `json:"order_id"`
+	Label     string          `json:"label"`
+	Amount    float64         `json:"amount"`
+	Paid      float64         `json:"paid"`
+	Items     []BillSplitItem `json:"items,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (s *BillSplit) Remaining() float64 {
+	return s.Amount - s.Paid
+}
+
+// BillSplitItem assigns qty of an order item to a split
+type BillSplitItem struct {
+	SplitID     int     `json:"split_id"`
+	OrderItemID int     `json:"order_item_id"`
+	DishName    string  `json:"dish_name,omitempty"`
+	Qty         int     `json:"qty"`
+	Amount      float64 `json:"amount"`
+}
+
+// SplitRequest describes how to divide a bill.
+// Parts is used for equal splits, Groups for splits by item.
+type SplitRequest struct {
+	Mode   SplitMode    `json:"mode"`
+	Parts  int          `json:"parts,omitempty"`
+	Groups []SplitGroup `json:"groups,omitempty"`
+}
+
+type SplitGroup struct {
+	Label string          `json:"label"`
+	Items []BillSplitItem `json:"items"`
+}
+
+// Bill is the payment state of an order
+type Bill struct {
+	OrderID  int         `json:"order_id"`
+	Status   OrderStatus `json:"status"`
+	Total    float64     `json:"total"`
+	Paid     float64     `json:"paid"`
+	Balance  float64     `json:"balance"`
+	Splits   []BillSplit `json:"splits"`
+	Payments []Payment   `json:"payments"`
+}
@@ -0,0 +1,136 @@
+package domain
+
+import "time"
+
+// maxPrepDepth bounds how deep prep items may nest inside each other
+const maxPrepDepth = 8
+
+// PrepItem is an ingredient the kitchen makes itself in batches (dough,
+// tomato sauce, pesto). One batch of Components gives BatchYield units of it.
+// A MadeToOrder prep item is not kept in stock: orders use its components
+// directly, in proportion to the yield.
+type PrepItem struct {
+	IngredientID int             `json:"ingredient_id"`
+	BatchYield   float64         `json:"batch_yield"`
+	MadeToOrder  bool            `json:"made_to_order"`
+	Components   []PrepComponent `json:"components"`
+	Ingredient   *Ingredient     `json:"ingredient,omitempty"`
+}
+
+// PrepComponent is an ingredient (raw or another prep item) in one batch
+type PrepComponent struct {
+	PrepID       int         `json:"prep_id"`
+	IngredientID int         `json:"ingredient_id"`
+	QtyPerBatch  float64     `json:"qty_per_batch"`
+	Ingredient   *Ingredient `json:"ingredient,omitempty"`
+}
+
+// IsValid checks the yield and that every component is used once and not the item itself
+func (p *PrepItem) IsValid() bool {
+	if p.BatchYield <= 0 || len(p.Components) == 0 {
+		return false
+	}
+	seen := make(map[int]bool, len(p.Components))
+	for _, c := range p.Components {
+		if c.IngredientID <= 0 || c.IngredientID == p.IngredientID || c.QtyPerBatch <= 0 || seen[c.IngredientID] {
+			return false
+		}
+		seen[c.IngredientID] = true
+	}
+	return true
+}
+
+// PrepRecipes indexes prep items by their ingredient id
+type PrepRecipes map[int]*PrepItem
+
+func NewPrepRecipes(preps []PrepItem) PrepRecipes {
+	recipes := make(PrepRecipes, len(preps))
+	for i := range preps {
+		recipes[preps[i].IngredientID] = &preps[i]
+	}
+	return recipes
+}
+
+// Resolve replaces made-to-order prep items in needs with their components,
+// recursively, so that only stocked items are left
+func (r PrepRecipes) Resolve(needs map[int]float64) map[int]float64 {
+	resolved := make(map[int]float64, len(needs))
+	for id, qty := range needs {
+		r.resolve(resolved, id, qty, 0)
+	}
+	return resolved
+}
+
+// Explode returns the stocked items that batches of the prep item use
+func (r PrepRecipes) Explode(prep *PrepItem, batches float64) map[int]float64 {
+	needs := make(map[int]float64, len(prep.Components))
+	for _, c := range prep.Components {
+		r.resolve(needs, c.IngredientID, c.QtyPerBatch*batches, 1)
+	}
+	return needs
+}
+
+func (r PrepRecipes) resolve(into map[int]float64, id int, qty float64, depth int) {
+	prep, ok := r[id]
+	if !ok || !prep.MadeToOrder || depth >= maxPrepDepth {
+		into[id] += qty
+		return
+	}
+	for _, c := range prep.Components {
+		r.resolve(into, c.IngredientID, qty*c.QtyPerBatch/prep.BatchYield, depth+1)
+	}
+}
+
+// UnitCost returns the unit cost of the ingredient. Made-to-order prep items
+// are not produced at a cost of their own, so theirs is worked out from the
+// current costs of their components, recursively.
+func (r PrepRecipes) UnitCost(ingredient *Ingredient) float64 {
+	return r.unitCost(ingredient, 0)
+}
+
+func (r PrepRecipes) unitCost(ingredient *Ingredient, depth int) float64 {
+	prep, ok := r[ingredient.ID]
+	if !ok || !prep.MadeToOrder || depth >= maxPrepDepth {
+		return ingredient.UnitCost
+	}
+	total := 0.0
+	for _, c := range prep.Components {
+		if c.Ingredient != nil {
+			total += c.QtyPerBatch * r.unitCost(c.Ingredient, depth+1)
+		}
+	}
+	return total / prep.BatchYield
+}
+
+// Uses reports whether the prep item needs ingredientID, directly or through
+// the prep items it is made of
+func (r PrepRecipes) Uses(prepID, ingredientID int) bool {
+	return r.uses(prepID, ingredientID, 0)
+}
+
+func (r PrepRecipes) uses(prepID, ingredientID, depth int) bool {
+	prep, ok := r[prepID]
+	if !ok || depth >= maxPrepDepth {
+		return false
+	}
+	for _, c := range prep.Components {
+		if c.IngredientID == ingredientID || r.uses(c.IngredientID, ingredientID, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductionBatch records making a prep item: its components leave the stock
+// and YieldQty units of the prep item arrive
+type ProductionBatch struct {
+	ID        int         `json:"id"`
+	PrepID    int         `json:"prep_id"`
+	Batches   float64     `json:"batches"`
+	YieldQty  float64     `json:"yield_qty"` // actually produced; defaults to batches × batch yield
+	Cost      float64     `json:"cost"`
+	UserID    *int        `json:"user_id,omitempty"`
+	Notes     *string     `json:"notes,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	Prep      *Ingredient `json:"prep,omitempty"`
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// PromotionType is the rule a promotion applies
+type PromotionType string
+
+const (
+	PromoOrderPercent    PromotionType = "order_percent"    // Value % off the whole order
+	PromoOrderFixed      PromotionType = "order_fixed"      // Value off the whole order
+	PromoCategoryPercent PromotionType = "category_percent" // Value % off dishes of CategoryID
+	PromoBuyXGetY        PromotionType = "buy_x_get_y"      // every BuyQty+GetQty of DishID, GetQty are free
+)
+
+func (t PromotionType) IsValid() bool {
+	switch t {
+	case PromoOrderPercent, PromoOrderFixed, PromoCategoryPercent, PromoBuyXGetY:
+		return true
+	}
+	return false
+}
+
+// IsItemLevel reports whether the promotion discounts single items rather than the order
+func (t PromotionType) IsItemLevel() bool {
+	return t == PromoCategoryPercent || t == PromoBuyXGetY
+}
+
+// Promotion is a pricing rule. Promotions without a code apply automatically,
+// coded ones only to orders carrying that promo code. HappyHourStart/End ("HH:MM")
+// limit the promotion to a time of day; a window may wrap past midnight.
+type Promotion struct {
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	Type           PromotionType `json:"type"`
+	Value          float64       `json:"value"`
+	CategoryID     *int          `json:"category_id,omitempty"`
+	DishID         *int          `json:"dish_id,omitempty"`
+	BuyQty         int           `json:"buy_qty,omitempty"`
+	GetQty         int           `json:"get_qty,omitempty"`
+	MinSubtotal    float64       `json:"min_subtotal"`
+	Code           *string       `json:"code,omitempty"`
+	StartsAt       *time.Time    `json:"starts_at,omitempty"`
+	EndsAt         *time.Time    `json:"ends_at,omitempty"`
+	HappyHourStart *string       `json:"happy_hour_start,omitempty"`
+	HappyHourEnd   *string       `json:"happy_hour_end,omitempty"`
+	IsActive       bool          `json:"is_active"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// IsValid checks that the promotion has the fields its type needs
+func (p *Promotion) IsValid() bool {
+	if p.Name == "" || !p.Type.IsValid() || p.Value < 0 || p.MinSubtotal < 0 {
+		return false
+	}
+	if (p.HappyHourStart == nil) != (p.HappyHourEnd == nil) {
+		return false
+	}
+	if p.HappyHourStart != nil && (!isClock(*p.HappyHourStart) || !isClock(*p.HappyHourEnd)) {
+		return false
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return false
+	}
+
+	switch p.Type {
+	case PromoOrderPercent:
+		return p.Value > 0 && p.Value <= 100
+	case PromoOrderFixed:
+		return p.Value > 0
+	case PromoCategoryPercent:
+		return p.CategoryID != nil && p.Value > 0 && p.Value <= 100
+	case PromoBuyXGetY:
+		return p.DishID != nil && p.BuyQty > 0 && p.GetQty > 0
+	}
+	return false
+}
+
+// AppliesAt reports whether the promotion is running at t
+func (p *Promotion) AppliesAt(t time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartsAt != nil && t.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !t.Before(*p.EndsAt) {
+		return false
+	}
+	if p.HappyHourStart == nil || p.HappyHourEnd == nil {
+		return true
+	}
+
+	now := t.Format("15:04")
+	from, to := *p.HappyHourStart, *p.HappyHourEnd
+	if from <= to {
+		return now >= from && now < to
+	}
+	return now >= from || now < to
+}
+
+// MatchesCode reports whether an order with the given promo code may use the promotion
+func (p *Promotion) MatchesCode(code *string) bool {
+	if p.Code == nil {
+		return true
+	}
+	return code != nil && strings.EqualFold(*p.Code, *code)
+}
+
+func isClock(s string) bool {
+	_, err := time.Parse("15:04", s)
+	return err == nil && len(s) == 5
+}
+
+// DiscountSource tells where an applied discount came from
+type DiscountSource string
+
+const (
+	DiscountPromotion DiscountSource = "promotion"
+	DiscountComp      DiscountSource = "comp" // one-off discount granted by a manager
+)
+
+// DiscountType is how a comp's value is interpreted
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent"
+	DiscountFixed   DiscountType = "fixed"
+)
+
+func (t DiscountType) IsValid() bool {
+	return t == DiscountPercent || t == DiscountFixed
+}
+
+// OrderDiscount is a discount applied to an order, or to one of its items
+// when OrderItemID is set. Promotion discounts are recomputed on every change
+// of the order; comps are kept and only their Amount is recomputed.
+type OrderDiscount struct {
+	ID          int            `json:"id"`
+	OrderID     int            `json:"order_id"`
+	OrderItemID *int           `json:"order_item_id,omitempty"`
+	PromotionID *int           `json:"promotion_id,omitempty"`
+	Source      DiscountSource `json:"source"`
+	Name        string         `json:"name"`
+	Type        DiscountType   `json:"type"`
+	Value       float64        `json:"value"`
+	Amount      float64        `json:"amount"`
+	Reason      *string        `json:"reason,omitempty"`
+	CreatedBy   *int           `json:"created_by,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// IsValidComp checks a comp before it is applied
+func (d *OrderDiscount) IsValidComp() bool {
+	if !d.Type.IsValid() || d.Value <= 0 {
+		return false
+	}
+	return d.Type != DiscountPercent || d.Value <= 100
+}
@@ -0,0 +1,136 @@
+package domain
+
+import "time"
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderSent              PurchaseOrderStatus = "sent"
+	PurchaseOrderPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+func (s PurchaseOrderStatus) IsValid() bool {
+	switch s {
+	case PurchaseOrderDraft, PurchaseOrderSent, PurchaseOrderPartiallyReceived,
+		PurchaseOrderReceived, PurchaseOrderCancelled:
+		return true
+	}
+	return false
+}
+
+// PurchaseOrder is an order to a supplier. Only a draft can be edited; once
+// sent it is received, possibly in several deliveries, each of which becomes
+// a Supply.
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name,omitempty"`
+	Status       PurchaseOrderStatus `json:"status"`
+	ExpectedAt   *time.Time          `json:"expected_at,omitempty"`
+	Notes        *string             `json:"notes,omitempty"`
+	CreatedBy    *int                `json:"created_by,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	SentAt       *time.Time          `json:"sent_at,omitempty"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+	Items        []PurchaseOrderItem `json:"items"`
+	Total        float64             `json:"total"` // ordered qty × price over priced items
+}
+
+type PurchaseOrderItem struct {
+	ID              int         `json:"id"`
+	PurchaseOrderID int         `json:"purchase_order_id"`
+	IngredientID    int         `json:"ingredient_id"`
+	QtyOrdered      float64     `json:"qty_ordered"`
+	QtyReceived     float64     `json:"qty_received"`
+	UnitPrice       *float64    `json:"unit_price,omitempty"`
+	Ingredient      *Ingredient `json:"ingredient,omitempty"`
+}
+
+// Remaining is what is still to be delivered
+func (i *PurchaseOrderItem) Remaining() float64 {
+	if i.QtyReceived >= i.QtyOrdered {
+		return 0
+	}
+	return roundCents(i.QtyOrdered - i.QtyReceived)
+}
+
+// IsValid checks the lines of a draft: at least one, each ingredient once
+func (po *PurchaseOrder) IsValid() bool {
+	if po.SupplierID <= 0 || len(po.Items) == 0 {
+		return false
+	}
+	seen := make(map[int]bool, len(po.Items))
+	for _, item := range po.Items {
+		if item.IngredientID <= 0 || item.QtyOrdered <= 0 || seen[item.IngredientID] {
+			return false
+		}
+		if item.UnitPrice != nil && *item.UnitPrice < 0 {
+			return false
+		}
+		seen[item.IngredientID] = true
+	}
+	return true
+}
+
+func (po *PurchaseOrder) CanReceive() bool {
+	return po.Status == PurchaseOrderSent || po.Status == PurchaseOrderPartiallyReceived
+}
+
+func (po *PurchaseOrder) CanCancel() bool {
+	return po.Status == PurchaseOrderDraft || po.Status == PurchaseOrderSent
+}
+
+// Item returns the line for the ingredient
+func (po *PurchaseOrder) Item(ingredientID int) *PurchaseOrderItem {
+	for i := range po.Items {
+		if po.Items[i].IngredientID == ingredientID {
+			return &po.Items[i]
+		}
+	}
+	return nil
+}
+
+// ReceivedStatus is the status after a delivery: received once every line
+// is delivered in full
+func (po *PurchaseOrder) ReceivedStatus() PurchaseOrderStatus {
+	for i := range po.Items {
+		if po.Items[i].Remaining() > 0 {
+			return PurchaseOrderPartiallyReceived
+		}
+	}
+	return PurchaseOrderReceived
+}
+
+func (po *PurchaseOrder) CalculateTotal() {
+	po.Total = 0
+	for _, item := range po.Items {
+		if item.UnitPrice != nil {
+			po.Total += item.QtyOrdered * *item.UnitPrice
+		}
+	}
+	po.Total = roundCents(po.Total)
+}
+
+// PurchaseOrderFilter narrows the list of purchase orders; nil fields match everything
+type PurchaseOrderFilter struct {
+	SupplierID *int
+	Status     *PurchaseOrderStatus
+}
+
+// Receipt is one delivery against a purchase order, booked from one invoice
+type Receipt struct {
+	InvoiceNumber *string       `json:"invoice_number,omitempty"`
+	Currency      string        `json:"currency"`
+	Lines         []ReceiveLine `json:"lines"`
+}
+
+// ReceiveLine is one ingredient of a delivery against a purchase order.
+// UnitPrice overrides the ordered price when the invoice differs.
+type ReceiveLine struct {
+	IngredientID int      `json:"ingredient_id"`
+	Qty          float64  `json:"qty"`
+	UnitPrice    *float64 `json:"unit_price,omitempty"`
+}
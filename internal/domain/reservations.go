@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationBooked    ReservationStatus = "booked"
+	ReservationSeated    ReservationStatus = "seated"
+	ReservationCancelled ReservationStatus = "cancelled"
+	ReservationNoShow    ReservationStatus = "no_show"
+)
+
+// Reservation is a table booked for a party over [StartsAt, EndsAt)
+type Reservation struct {
+	ID         int               `json:"id"`
+	TableID    int               `json:"table_id"`
+	GuestName  string            `json:"guest_name"`
+	GuestPhone *string           `json:"guest_phone,omitempty"`
+	PartySize  int               `json:"party_size"`
+	StartsAt   time.Time         `json:"starts_at"`
+	EndsAt     time.Time         `json:"ends_at"`
+	Status     ReservationStatus `json:"status"`
+	Notes      *string           `json:"notes,omitempty"`
+	CreatedBy  *int              `json:"created_by,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Table      *Table            `json:"table,omitempty"`
+}
+
+// IsValid checks the guest details and the time slot
+func (r *Reservation) IsValid() bool {
+	r.GuestName = strings.TrimSpace(r.GuestName)
+	return r.GuestName != "" && r.TableID > 0 && r.PartySize > 0 && r.EndsAt.After(r.StartsAt)
+}
+
+// ReservationFilter narrows the reservation list; nil fields are not applied
+type ReservationFilter struct {
+	From    *time.Time
+	To      *time.Time
+	TableID *int
+	Status  *ReservationStatus
+}
+
+// ReservationPolicy describes how long a booking lasts and how tables are held for it
+type ReservationPolicy struct {
+	Duration    time.Duration // default length of a booking
+	HoldBefore  time.Duration // the table turns "reserve" this long before the slot
+	NoShowAfter time.Duration // a party this late is marked no-show and the table released
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Actor is the authenticated user a service call is made on behalf of.
+// Calls without an actor (background jobs) are not restricted by sections.
+type Actor struct {
+	UserID int
+	Role   Role
+}
+
+// CanOverride reports whether the actor may act outside their own section
+func (a Actor) CanOverride() bool {
+	return a.Role == RoleManager || a.Role == RoleAdmin
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// TableAssignment puts a table into a waiter's section for one shift
+type TableAssignment struct {
+	ShiftDate  time.Time `json:"shift_date"`
+	TableID    int       `json:"table_id"`
+	WaiterID   int       `json:"waiter_id"`
+	AssignedBy *int      `json:"assigned_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Section is the set of tables a waiter serves during a shift
+type Section struct {
+	WaiterID   int       `json:"waiter_id"`
+	WaiterName string    `json:"waiter_name"`
+	ShiftDate  time.Time `json:"shift_date"`
+	TableIDs   []int     `json:"table_ids"`
+}
+
+// Handover passes open tables of one waiter to another at shift change.
+// An empty TableIDs hands over the whole section and all open orders.
+type Handover struct {
+	FromWaiterID int   `json:"from_waiter_id"`
+	ToWaiterID   int   `json:"to_waiter_id"`
+	TableIDs     []int `json:"table_ids"`
+}
+
+// ShiftDate returns the shift a moment belongs to; shifts are calendar days
+func ShiftDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
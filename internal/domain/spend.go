@@ -0,0 +1,117 @@
+package domain
+
+import "time"
+
+// SpendPeriod is the bucket size of spend over time
+type SpendPeriod string
+
+const (
+	SpendByDay   SpendPeriod = "day"
+	SpendByWeek  SpendPeriod = "week"
+	SpendByMonth SpendPeriod = "month"
+)
+
+func (p SpendPeriod) IsValid() bool {
+	return p == SpendByDay || p == SpendByWeek || p == SpendByMonth
+}
+
+// SpendReport is what was paid for supplies over a period. Amounts in
+// different currencies are never added together, so every line carries its
+// currency. Supplies without a unit price are only counted.
+type SpendReport struct {
+	From             time.Time         `json:"from"`
+	To               time.Time         `json:"to"`
+	Period           SpendPeriod       `json:"period"`
+	Totals           []CurrencySpend   `json:"totals"`
+	BySupplier       []SupplierSpend   `json:"by_supplier"`
+	ByIngredient     []IngredientSpend `json:"by_ingredient"`
+	ByPeriod         []PeriodSpend     `json:"by_period"`
+	UnpricedSupplies int               `json:"unpriced_supplies"`
+}
+
+type CurrencySpend struct {
+	Currency string  `json:"currency"`
+	Supplies int     `json:"supplies"`
+	Total    float64 `json:"total"`
+}
+
+type SupplierSpend struct {
+	SupplierID   *int    `json:"supplier_id,omitempty"` // nil for free-text suppliers
+	SupplierName string  `json:"supplier_name"`
+	Currency     string  `json:"currency"`
+	Supplies     int     `json:"supplies"`
+	Total        float64 `json:"total"`
+	Share        float64 `json:"share"` // % of the spend in this currency
+}
+
+type IngredientSpend struct {
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name"`
+	Unit           string  `json:"unit"`
+	Currency       string  `json:"currency"`
+	Qty            float64 `json:"qty"`
+	Total          float64 `json:"total"`
+	AvgUnitPrice   float64 `json:"avg_unit_price"`
+}
+
+type PeriodSpend struct {
+	Period   string  `json:"period"` // YYYY-MM-DD of the start of the bucket
+	Currency string  `json:"currency"`
+	Total    float64 `json:"total"`
+}
+
+// Summarize fills the totals per currency and the share of each supplier
+func (r *SpendReport) Summarize() {
+	r.Totals = nil
+	index := make(map[string]int)
+	for _, s := range r.BySupplier {
+		i, ok := index[s.Currency]
+		if !ok {
+			i = len(r.Totals)
+			index[s.Currency] = i
+			r.Totals = append(r.Totals, CurrencySpend{Currency: s.Currency})
+		}
+		r.Totals[i].Supplies += s.Supplies
+		r.Totals[i].Total += s.Total
+	}
+	for i := range r.Totals {
+		r.Totals[i].Total = roundCents(r.Totals[i].Total)
+	}
+	for i := range r.BySupplier {
+		s := &r.BySupplier[i]
+		s.Share = percentOf(s.Total, r.Totals[index[s.Currency]].Total)
+	}
+	for i := range r.ByIngredient {
+		ing := &r.ByIngredient[i]
+		if ing.Qty > 0 {
+			ing.AvgUnitPrice = roundCents(ing.Total / ing.Qty)
+		}
+	}
+}
+
+// PricePoint is one priced supply of an ingredient, compared with the
+// previous price of the same supplier in the same currency
+type PricePoint struct {
+	SupplyID      int       `json:"supply_id"`
+	SupplierID    *int      `json:"supplier_id,omitempty"`
+	SupplierName  string    `json:"supplier_name"`
+	Currency      string    `json:"currency"`
+	UnitPrice     float64   `json:"unit_price"`
+	PreviousPrice *float64  `json:"previous_price,omitempty"`
+	Change        *float64  `json:"change,omitempty"`
+	ChangePct     *float64  `json:"change_pct,omitempty"`
+	InvoiceNumber *string   `json:"invoice_number,omitempty"`
+	ReceivedAt    time.Time `json:"received_at"`
+}
+
+func (p *PricePoint) CalculateChange() {
+	if p.PreviousPrice == nil {
+		return
+	}
+	change := roundCents(p.UnitPrice - *p.PreviousPrice)
+	p.Change = &change
+	if *p.PreviousPrice > 0 {
+		pct := percentOf(change, *p.PreviousPrice)
+		p.ChangePct = &pct
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// StockMovementType says why the quantity of an ingredient changed
+type StockMovementType string
+
+const (
+	MovementSupply      StockMovementType = "supply"            // delivery received
+	MovementConsumption StockMovementType = "order_consumption" // cooked for an order
+	MovementVoidReturn  StockMovementType = "void_return"       // voided or reduced dish put back to stock
+	MovementWaste       StockMovementType = "waste"             // spoiled or dropped
+	MovementAdjustment  StockMovementType = "count_adjustment"  // quantity corrected by hand or by a count
+	MovementTransfer    StockMovementType = "transfer"          // moved to or from another storage
+	MovementProduction  StockMovementType = "production"        // used for or made by a prep batch
+)
+
+func (t StockMovementType) IsValid() bool {
+	switch t {
+	case MovementSupply, MovementConsumption, MovementVoidReturn, MovementWaste,
+		MovementAdjustment, MovementTransfer, MovementProduction:
+		return true
+	}
+	return false
+}
+
+// StockMovement is one entry of the append-only stock ledger. Qty is signed:
+// positive adds stock, negative takes it. RefID points to the order, supply,
+// batch or count behind the movement.
+type StockMovement struct {
+	ID           int               `json:"id"`
+	IngredientID int               `json:"ingredient_id"`
+	Type         StockMovementType `json:"type"`
+	Qty          float64           `json:"qty"`
+	QtyBefore    float64           `json:"qty_before"`
+	QtyAfter     float64           `json:"qty_after"`
+	RefID        *int              `json:"ref_id,omitempty"`
+	UserID       *int              `json:"user_id,omitempty"`
+	UserName     *string           `json:"user_name,omitempty"`
+	Note         *string           `json:"note,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Ingredient   *Ingredient       `json:"ingredient,omitempty"`
+}
+
+// StockMovementFilter narrows the ledger; zero fields match everything
+type StockMovementFilter struct {
+	IngredientID *int
+	Type         *StockMovementType
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+type StocktakeStatus string
+
+const (
+	StocktakeOpen      StocktakeStatus = "open"
+	StocktakePosted    StocktakeStatus = "posted"
+	StocktakeCancelled StocktakeStatus = "cancelled"
+)
+
+// Stocktake is a count of the stock. While it is open, counts can be entered
+// and re-entered by anyone; posting turns the variances into count
+// adjustments in the ledger and locks the session.
+type Stocktake struct {
+	ID        int             `json:"id"`
+	Note      *string         `json:"note,omitempty"`
+	Status    StocktakeStatus `json:"status"`
+	StartedBy *int            `json:"started_by,omitempty"`
+	StartedAt time.Time       `json:"started_at"`
+	ClosedBy  *int            `json:"closed_by,omitempty"`
+	ClosedAt  *time.Time      `json:"closed_at,omitempty"`
+	Lines     []StocktakeLine `json:"lines,omitempty"`
+
+	// Totals over Lines, see Summarize
+	CountedItems  int     `json:"counted_items"`
+	VarianceItems int     `json:"variance_items"` // lines where the count differs from the system
+	VarianceCost  float64 `json:"variance_cost"`  // net cost of all variances; negative is a loss
+}
+
+// StocktakeLine is the counted quantity of one ingredient. SystemQty and
+// UnitCost are taken when the count is entered, so stock that moves between
+// counting and posting does not end up in the variance.
+type StocktakeLine struct {
+	StocktakeID  int         `json:"stocktake_id"`
+	IngredientID int         `json:"ingredient_id"`
+	CountedQty   float64     `json:"counted_qty"`
+	CountedBy    *int        `json:"counted_by,omitempty"`
+	CountedAt    time.Time   `json:"counted_at"`
+	SystemQty    float64     `json:"system_qty"`
+	UnitCost     float64     `json:"unit_cost"`
+	Variance     float64     `json:"variance"`      // counted - system
+	VarianceCost float64     `json:"variance_cost"` // variance × unit cost
+	Ingredient   *Ingredient `json:"ingredient,omitempty"`
+}
+
+// Summarize computes the variances of the lines and the totals of the stocktake
+func (s *Stocktake) Summarize() {
+	s.CountedItems = len(s.Lines)
+	s.VarianceItems = 0
+	s.VarianceCost = 0
+	for i := range s.Lines {
+		l := &s.Lines[i]
+		l.Variance = roundCents(l.CountedQty - l.SystemQty)
+		l.VarianceCost = roundCents(l.Variance * l.UnitCost)
+		if l.Variance != 0 {
+			s.VarianceItems++
+		}
+		s.VarianceCost += l.VarianceCost
+	}
+	s.VarianceCost = roundCents(s.VarianceCost)
+}
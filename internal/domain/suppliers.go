@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type Supplier struct {
+	ID           int                  `json:"id"`
+	Name         string               `json:"name"`
+	ContactName  *string              `json:"contact_name,omitempty"`
+	Phone        *string              `json:"phone,omitempty"`
+	Email        *string              `json:"email,omitempty"`
+	Address      *string              `json:"address,omitempty"`
+	Notes        *string              `json:"notes,omitempty"`
+	LeadTimeDays int                  `json:"lead_time_days"` // days from order to delivery
+	IsActive     bool                 `json:"is_active"`
+	CreatedAt    time.Time            `json:"created_at"`
+	Catalog      []SupplierIngredient `json:"catalog,omitempty"`
+}
+
+func (s *Supplier) IsValid() bool {
+	return strings.TrimSpace(s.Name) != "" && s.LeadTimeDays >= 0
+}
+
+// SupplierIngredient is an ingredient the supplier delivers, priced per
+// ingredient unit
+type SupplierIngredient struct {
+	SupplierID   int         `json:"supplier_id"`
+	IngredientID int         `json:"ingredient_id"`
+	UnitPrice    float64     `json:"unit_price"`
+	SKU          *string     `json:"sku,omitempty"`
+	MinOrderQty  float64     `json:"min_order_qty"`
+	Ingredient   *Ingredient `json:"ingredient,omitempty"`
+}
+
+func (i *SupplierIngredient) IsValid() bool {
+	return i.IngredientID > 0 && i.UnitPrice >= 0 && i.MinOrderQty >= 0
+}
+
+// CatalogPrice returns the catalog price of the ingredient, if the supplier has it
+func (s *Supplier) CatalogPrice(ingredientID int) (float64, bool) {
+	for _, item := range s.Catalog {
+		if item.IngredientID == ingredientID {
+			return item.UnitPrice, true
+		}
+	}
+	return 0, false
+}
@@ -3,10 +3,33 @@ package domain
 import "time"
 
 type Supply struct {
-	ID           int         `json:"id"`
-	IngredientID int         `json:"ingredient_id"`
-	Qty          float64     `json:"qty"`
-	SupplierName string      `json:"supplier_name"`
-	CreatedAt    time.Time   `json:"created_at"`
-	Ingredient   *Ingredient `json:"ingredient,omitempty"`
+	ID              int         `json:"id"`
+	IngredientID    int         `json:"ingredient_id"`
+	Qty             float64     `json:"qty"`
+	SupplierName    string      `json:"supplier_name"`
+	SupplierID      *int        `json:"supplier_id,omitempty"`
+	PurchaseOrderID *int        `json:"purchase_order_id,omitempty"` // received against a purchase order
+	UnitPrice       *float64    `json:"unit_price,omitempty"`        // purchase price per ingredient unit
+	Currency        string      `json:"currency"`                    // ISO 4217 code of unit_price
+	InvoiceNumber   *string     `json:"invoice_number,omitempty"`
+	InvoiceURL      *string     `json:"invoice_url,omitempty"`
+	ReceivedBy      *int        `json:"received_by,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+	Ingredient      *Ingredient `json:"ingredient,omitempty"`
+}
+
+// DefaultCurrency is the currency of the menu and of ingredient unit costs
+const DefaultCurrency = "KZT"
+
+// IsValidCurrency checks the shape of an ISO 4217 code
+func IsValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
 }
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type TableStatus string
 
 const (
@@ -8,8 +10,90 @@ const (
 	TableFree    TableStatus = "free"
 )
 
+// TableZone is the part of the floor a table stands in
+type TableZone string
+
+const (
+	ZoneHall    TableZone = "hall"
+	ZoneTerrace TableZone = "terrace"
+	ZoneBar     TableZone = "bar"
+)
+
+func (z TableZone) IsValid() bool {
+	return z == ZoneHall || z == ZoneTerrace || z == ZoneBar
+}
+
+type TableShape string
+
+const (
+	ShapeSquare    TableShape = "square"
+	ShapeRound     TableShape = "round"
+	ShapeRectangle TableShape = "rectangle"
+)
+
+func (s TableShape) IsValid() bool {
+	return s == ShapeSquare || s == ShapeRound || s == ShapeRectangle
+}
+
+const (
+	DefaultTableCapacity = 4
+	DefaultTableSize     = 80 // plan units
+)
+
 type Table struct {
-	ID     int         `json:"id"`
-	Name   string      `json:"name"`
-	Status TableStatus `json:"status"`
+	ID       int         `json:"id"`
+	Name     string      `json:"name"`
+	Capacity int         `json:"capacity"` // seats
+	Status   TableStatus `json:"status"`
+	TableLayout
+}
+
+// TableLayout is where and how a table is drawn on the floor plan.
+// Coordinates are in plan units of the frontend, the origin is the top left corner.
+type TableLayout struct {
+	Zone     TableZone  `json:"zone"`
+	Shape    TableShape `json:"shape"`
+	PosX     float64    `json:"pos_x"`
+	PosY     float64    `json:"pos_y"`
+	Width    float64    `json:"width"`
+	Height   float64    `json:"height"`
+	Rotation int        `json:"rotation"` // degrees clockwise
+}
+
+// IsValid checks the layout, filling in defaults for an empty zone, shape and size
+func (l *TableLayout) IsValid() bool {
+	if l.Zone == "" {
+		l.Zone = ZoneHall
+	}
+	if l.Shape == "" {
+		l.Shape = ShapeSquare
+	}
+	if l.Width == 0 {
+		l.Width = DefaultTableSize
+	}
+	if l.Height == 0 {
+		l.Height = DefaultTableSize
+	}
+	return l.Zone.IsValid() && l.Shape.IsValid() &&
+		l.PosX >= 0 && l.PosY >= 0 && l.Width > 0 && l.Height > 0 &&
+		l.Rotation >= 0 && l.Rotation < 360
+}
+
+// TablePosition moves one table in a bulk layout update
+type TablePosition struct {
+	ID int `json:"id"`
+	TableLayout
+}
+
+// FloorTable is a table on the floor plan with the order currently served at it
+type FloorTable struct {
+	Table
+	OrderID        *int         `json:"order_id,omitempty"`
+	OrderStatus    *OrderStatus `json:"order_status,omitempty"`
+	OrderTotal     *float64     `json:"order_total,omitempty"`
+	Guests         *int         `json:"guests,omitempty"`
+	WaiterID       *int         `json:"waiter_id,omitempty"`
+	WaiterName     *string      `json:"waiter_name,omitempty"`
+	SeatedAt       *time.Time   `json:"seated_at,omitempty"`
+	ElapsedMinutes int          `json:"elapsed_minutes"`
 }
@@ -0,0 +1,21 @@
+package domain
+
+// IsValidRate reports whether a VAT or service charge percentage is usable
+func IsValidRate(rate float64) bool {
+	return rate >= 0 && rate <= 100
+}
+
+// ServiceChargePolicy adds a service charge to orders of large parties.
+// A zero Rate disables the automatic charge.
+type ServiceChargePolicy struct {
+	Rate      float64 // percent of the discounted subtotal
+	MinGuests int     // parties of at least this many guests are charged
+}
+
+// RateFor returns the service charge rate for an order with the given party size
+func (p ServiceChargePolicy) RateFor(guests *int) float64 {
+	if p.Rate <= 0 || guests == nil || p.MinGuests <= 0 || *guests < p.MinGuests {
+		return 0
+	}
+	return p.Rate
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// VoidReason is the reason code recorded for a cancelled order or voided item
+type VoidReason string
+
+const (
+	VoidCustomerRequest VoidReason = "customer_request"
+	VoidWrongOrder      VoidReason = "wrong_order"
+	VoidQualityIssue    VoidReason = "quality_issue"
+	VoidLongWait        VoidReason = "long_wait"
+	VoidDuplicate       VoidReason = "duplicate"
+	VoidOther           VoidReason = "other"
+)
+
+// StockAction says what happens to ingredients already taken for the voided dishes
+type StockAction string
+
+const (
+	StockReturn StockAction = "return" // ингредиенты возвращаются на склад
+	StockWaste  StockAction = "waste"  // ингредиенты списываются как отходы
+)
+
+type VoidStatus string
+
+const (
+	VoidPending  VoidStatus = "pending"
+	VoidApproved VoidStatus = "approved"
+	VoidRejected VoidStatus = "rejected"
+)
+
+// OrderVoid records who cancelled an order (OrderItemID == nil) or voided an item, and why.
+// Voids after the kitchen has started need manager approval and wait as pending.
+type OrderVoid struct {
+	ID          int         `json:"id"`
+	OrderID     int         `json:"order_id"`
+	OrderItemID *int        `json:"order_item_id,omitempty"`
+	DishID      *int        `json:"dish_id,omitempty"`
+	Qty         int         `json:"qty"`
+	Amount      float64     `json:"amount"`
+	ReasonCode  VoidReason  `json:"reason_code"`
+	ReasonText  *string     `json:"reason_text,omitempty"`
+	StockAction StockAction `json:"stock_action"`
+	Status      VoidStatus  `json:"status"`
+	VoidedBy    int         `json:"voided_by"`
+	ApprovedBy  *int        `json:"approved_by,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	ResolvedAt  *time.Time  `json:"resolved_at,omitempty"`
+}
+
+// IsOrderCancel reports whether the void cancels the whole order
+func (v *OrderVoid) IsOrderCancel() bool {
+	return v.OrderItemID == nil
+}
+
+func (r VoidReason) IsValid() bool {
+	switch r {
+	case VoidCustomerRequest, VoidWrongOrder, VoidQualityIssue, VoidLongWait, VoidDuplicate, VoidOther:
+		return true
+	}
+	return false
+}
+
+func (a StockAction) IsValid() bool {
+	return a == StockReturn || a == StockWaste
+}
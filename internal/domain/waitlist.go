@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting   WaitlistStatus = "waiting"
+	WaitlistNotified  WaitlistStatus = "notified" // a fitting table was freed for the party
+	WaitlistSeated    WaitlistStatus = "seated"
+	WaitlistCancelled WaitlistStatus = "cancelled"
+)
+
+// IsActive reports whether the party is still in the queue
+func (s WaitlistStatus) IsActive() bool {
+	return s == WaitlistWaiting || s == WaitlistNotified
+}
+
+// WaitlistEntry is a walk-in party waiting for a table
+type WaitlistEntry struct {
+	ID            int            `json:"id"`
+	GuestName     string         `json:"guest_name"`
+	GuestPhone    *string        `json:"guest_phone,omitempty"`
+	PartySize     int            `json:"party_size"`
+	Status        WaitlistStatus `json:"status"`
+	QuotedWait    int            `json:"quoted_wait"` // minutes, quoted when the party was added
+	TableID       *int           `json:"table_id,omitempty"`
+	Notes         *string        `json:"notes,omitempty"`
+	CreatedBy     *int           `json:"created_by,omitempty"`
+	NotifiedAt    *time.Time     `json:"notified_at,omitempty"`
+	SeatedAt      *time.Time     `json:"seated_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	Position      int            `json:"position,omitempty"` // place in the queue, 1-based
+	EstimatedWait int            `json:"estimated_wait"`     // current estimate in minutes
+}
+
+// IsValid checks the guest details
+func (e *WaitlistEntry) IsValid() bool {
+	e.GuestName = strings.TrimSpace(e.GuestName)
+	return e.GuestName != "" && e.PartySize > 0
+}
+
+// WaitEstimate is the quoted wait for a party of a given size
+type WaitEstimate struct {
+	PartySize    int `json:"party_size"`
+	PartiesAhead int `json:"parties_ahead"`
+	Minutes      int `json:"minutes"`
+}
+
+// WaitlistPolicy describes how waits are estimated
+type WaitlistPolicy struct {
+	Lookback    time.Duration // history used for table turn times
+	DefaultTurn time.Duration // turn time when there is no history yet
+}
@@ -0,0 +1,108 @@
+package domain
+
+import "time"
+
+type WasteReason string
+
+const (
+	WasteSpoiled  WasteReason = "spoiled"
+	WasteExpired  WasteReason = "expired"
+	WasteDropped  WasteReason = "dropped"
+	WasteBurnt    WasteReason = "burnt"
+	WasteReturned WasteReason = "returned" // sent back by a guest and thrown away
+	WasteOther    WasteReason = "other"
+)
+
+func (r WasteReason) IsValid() bool {
+	switch r {
+	case WasteSpoiled, WasteExpired, WasteDropped, WasteBurnt, WasteReturned, WasteOther:
+		return true
+	}
+	return false
+}
+
+// WasteEntry writes off either an ingredient or finished portions of a dish.
+// A dish is taken off stock through its recipe; Items hold what was actually
+// deducted and at which cost.
+type WasteEntry struct {
+	ID           int         `json:"id"`
+	IngredientID *int        `json:"ingredient_id,omitempty"`
+	DishID       *int        `json:"dish_id,omitempty"`
+	Qty          float64     `json:"qty"` // ingredient units or dish portions
+	Reason       WasteReason `json:"reason"`
+	Note         *string     `json:"note,omitempty"`
+	PhotoURL     *string     `json:"photo_url,omitempty"`
+	Cost         float64     `json:"cost"`
+	UserID       *int        `json:"user_id,omitempty"`
+	UserName     *string     `json:"user_name,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	ItemName     string      `json:"item_name"` // ingredient or dish name
+	Items        []WasteItem `json:"items,omitempty"`
+}
+
+func (w *WasteEntry) IsValid() bool {
+	if (w.IngredientID == nil) == (w.DishID == nil) {
+		return false
+	}
+	return w.Qty > 0 && w.Reason.IsValid()
+}
+
+type WasteItem struct {
+	IngredientID int         `json:"ingredient_id"`
+	Qty          float64     `json:"qty"`
+	UnitCost     float64     `json:"unit_cost"`
+	Ingredient   *Ingredient `json:"ingredient,omitempty"`
+}
+
+// WasteFilter narrows the waste log; zero fields match everything
+type WasteFilter struct {
+	Reason *WasteReason
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+}
+
+// WasteReport is the cost of waste over a period
+type WasteReport struct {
+	From         time.Time           `json:"from"`
+	To           time.Time           `json:"to"`
+	Entries      int                 `json:"entries"`
+	TotalCost    float64             `json:"total_cost"`
+	ByReason     []WasteByReason     `json:"by_reason"`
+	ByIngredient []WasteByIngredient `json:"by_ingredient"`
+	ByDay        []WasteByDay        `json:"by_day"`
+}
+
+type WasteByReason struct {
+	Reason  WasteReason `json:"reason"`
+	Entries int         `json:"entries"`
+	Cost    float64     `json:"cost"`
+	Share   float64     `json:"share"` // % of total waste cost
+}
+
+type WasteByIngredient struct {
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name"`
+	Unit           string  `json:"unit"`
+	Qty            float64 `json:"qty"`
+	Cost           float64 `json:"cost"`
+}
+
+type WasteByDay struct {
+	Date string  `json:"date"` // YYYY-MM-DD
+	Cost float64 `json:"cost"`
+}
+
+// Summarize fills the totals and the share of each reason
+func (r *WasteReport) Summarize() {
+	r.Entries = 0
+	r.TotalCost = 0
+	for _, w := range r.ByReason {
+		r.Entries += w.Entries
+		r.TotalCost += w.Cost
+	}
+	r.TotalCost = roundCents(r.TotalCost)
+	for i := range r.ByReason {
+		r.ByReason[i].Share = percentOf(r.ByReason[i].Cost, r.TotalCost)
+	}
+}
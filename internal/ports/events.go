@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/YelzhanWeb/uno-spicchio/internal/domain"
+
+// EventPublisher broadcasts domain events to live subscribers
+type EventPublisher interface {
+	Publish(event domain.Event)
+}
+
+// EventSubscriber hands out event streams; the returned func unsubscribes
+type EventSubscriber interface {
+	Subscribe() (<-chan domain.Event, func())
+}
@@ -7,6 +7,12 @@ import (
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 )
 
+// TxManager runs a unit of work inside a single database transaction.
+// Repository calls made with the ctx passed to fn join that transaction.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // UserRepository defines methods for user data access
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
@@ -21,11 +27,49 @@ type UserRepository interface {
 type TableRepository interface {
 	GetAll(ctx context.Context) ([]domain.Table, error)
 	GetByID(ctx context.Context, id int) (*domain.Table, error)
+	GetByIDForUpdate(ctx context.Context, id int) (*domain.Table, error)
+	GetFloor(ctx context.Context) ([]domain.FloorTable, error)
 	Create(ctx context.Context, table *domain.Table) error
+	Update(ctx context.Context, table *domain.Table) error
+	UpdateLayout(ctx context.Context, id int, layout domain.TableLayout) error
 	UpdateStatus(ctx context.Context, id int, status domain.TableStatus) error
 	Delete(ctx context.Context, id int) error
 }
 
+// ReservationRepository defines methods for table bookings
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation *domain.Reservation) error
+	GetByID(ctx context.Context, id int) (*domain.Reservation, error)
+	GetAll(ctx context.Context, filter domain.ReservationFilter) ([]domain.Reservation, error)
+	Update(ctx context.Context, reservation *domain.Reservation) error
+	UpdateStatus(ctx context.Context, id int, status domain.ReservationStatus) error
+	// GetConflicts returns active bookings of the table overlapping [from, to), except excludeID
+	GetConflicts(ctx context.Context, tableID int, from, to time.Time, excludeID int) ([]domain.Reservation, error)
+	// GetBookedBefore returns bookings still waiting for their guests that start before t
+	GetBookedBefore(ctx context.Context, t time.Time) ([]domain.Reservation, error)
+	// GetAvailableTables returns tables seating the party with no active booking in [from, to)
+	GetAvailableTables(ctx context.Context, from, to time.Time, partySize int) ([]domain.Table, error)
+}
+
+// WaitlistRepository defines methods for the walk-in queue
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry *domain.WaitlistEntry) error
+	GetByID(ctx context.Context, id int) (*domain.WaitlistEntry, error)
+	GetActive(ctx context.Context) ([]domain.WaitlistEntry, error)
+	// GetNextFitting locks the earliest waiting party that fits at a table of the given capacity
+	GetNextFitting(ctx context.Context, capacity int) (*domain.WaitlistEntry, error)
+	GetNotifiedForTable(ctx context.Context, tableID int) (*domain.WaitlistEntry, error)
+	UpdateStatus(ctx context.Context, id int, status domain.WaitlistStatus, tableID *int) error
+}
+
+// SectionRepository defines methods for waiter sections (table assignments per shift)
+type SectionRepository interface {
+	Assign(ctx context.Context, shiftDate time.Time, waiterID int, tableIDs []int, assignedBy *int) error
+	GetByDate(ctx context.Context, shiftDate time.Time) ([]domain.Section, error)
+	GetTableWaiter(ctx context.Context, shiftDate time.Time, tableID int) (*int, error)
+	Reassign(ctx context.Context, shiftDate time.Time, fromWaiterID, toWaiterID int, tableID *int) error
+}
+
 // CategoryRepository defines methods for category data access
 type CategoryRepository interface {
 	GetAll(ctx context.Context) ([]domain.Category, error)
@@ -47,17 +91,103 @@ type DishRepository interface {
 	AddIngredient(ctx context.Context, dishIngredient *domain.DishIngredient) error
 	RemoveIngredient(ctx context.Context, dishID, ingredientID int) error
 	UpdateIngredient(ctx context.Context, dishIngredient *domain.DishIngredient) error
+	SetSoldOut(ctx context.Context, id int, soldOut bool) error
+	// GetPortionsLeft returns portions left by free stock for every dish with a recipe
+	GetPortionsLeft(ctx context.Context) (map[int]int, error)
+}
+
+// ModifierRepository defines methods for dish modifier groups and options
+type ModifierRepository interface {
+	GetGroupsByDish(ctx context.Context, dishID int, activeOnly bool) ([]domain.ModifierGroup, error)
+	GetGroupByID(ctx context.Context, id int) (*domain.ModifierGroup, error)
+	CreateGroup(ctx context.Context, group *domain.ModifierGroup) error
+	UpdateGroup(ctx context.Context, group *domain.ModifierGroup) error
+	DeleteGroup(ctx context.Context, id int) error
+	GetOptionByID(ctx context.Context, id int) (*domain.ModifierOption, error)
+	CreateOption(ctx context.Context, option *domain.ModifierOption) error
+	UpdateOption(ctx context.Context, option *domain.ModifierOption) error
+	DeleteOption(ctx context.Context, id int) error
+	GetOptionIngredients(ctx context.Context, optionID int) ([]domain.ModifierIngredient, error)
+	SetOptionIngredients(ctx context.Context, optionID int, ingredients []domain.ModifierIngredient) error
+}
+
+// MenuRepository defines methods for menus and their dishes
+type MenuRepository interface {
+	GetAll(ctx context.Context, activeOnly bool) ([]domain.Menu, error)
+	GetByID(ctx context.Context, id int) (*domain.Menu, error)
+	Create(ctx context.Context, menu *domain.Menu) error
+	Update(ctx context.Context, menu *domain.Menu) error
+	Delete(ctx context.Context, id int) error
+	SetDish(ctx context.Context, menuDish *domain.MenuDish) error
+	RemoveDish(ctx context.Context, menuID, dishID int) error
+}
+
+// ComboRepository defines methods for combo meals and their slots
+type ComboRepository interface {
+	GetAll(ctx context.Context, activeOnly bool) ([]domain.Combo, error)
+	GetByID(ctx context.Context, id int) (*domain.Combo, error)
+	// Create and Update store the combo together with its slots and choices
+	Create(ctx context.Context, combo *domain.Combo) error
+	Update(ctx context.Context, combo *domain.Combo) error
+	Delete(ctx context.Context, id int) error
 }
 
 // IngredientRepository defines methods for ingredient data access
 type IngredientRepository interface {
 	GetAll(ctx context.Context) ([]domain.Ingredient, error)
 	GetByID(ctx context.Context, id int) (*domain.Ingredient, error)
+	GetByIDForUpdate(ctx context.Context, id int) (*domain.Ingredient, error)
 	GetLowStock(ctx context.Context) ([]domain.Ingredient, error)
 	Create(ctx context.Context, ingredient *domain.Ingredient) error
 	Update(ctx context.Context, ingredient *domain.Ingredient) error
-	UpdateQuantity(ctx context.Context, id int, qty float64) error
+	// GetReservations returns what the order holds in reserve, by ingredient
+	GetReservations(ctx context.Context, orderID int) (map[int]float64, error)
+	// Reserve shifts the order's reservation of the ingredient and reserved_qty
+	// by qty; a negative qty releases it
+	Reserve(ctx context.Context, orderID, ingredientID int, qty float64) error
+	// MoveReservations hands the reservation of one order over to another
+	MoveReservations(ctx context.Context, fromOrderID, toOrderID int) error
+	SetUnitCost(ctx context.Context, id int, unitCost float64) error
 	Delete(ctx context.Context, id int) error
+	// ApplyMovement is the only way to change qty: it shifts the stock by m.Qty
+	// and appends the movement to the ledger
+	ApplyMovement(ctx context.Context, m *domain.StockMovement) error
+	GetMovements(ctx context.Context, filter domain.StockMovementFilter) ([]domain.StockMovement, error)
+}
+
+// PrepRepository defines methods for prep items (sub-recipes) and their production
+type PrepRepository interface {
+	GetAll(ctx context.Context) ([]domain.PrepItem, error)
+	GetByID(ctx context.Context, ingredientID int) (*domain.PrepItem, error)
+	// Save creates or replaces the prep item together with its components
+	Save(ctx context.Context, prep *domain.PrepItem) error
+	Delete(ctx context.Context, ingredientID int) error
+	CreateBatch(ctx context.Context, batch *domain.ProductionBatch) error
+	// GetBatches returns the latest batches, of one prep item when prepID is set
+	GetBatches(ctx context.Context, prepID *int, limit int) ([]domain.ProductionBatch, error)
+}
+
+// StocktakeRepository defines methods for stocktakes and their counts
+type StocktakeRepository interface {
+	Create(ctx context.Context, stocktake *domain.Stocktake) error
+	GetAll(ctx context.Context) ([]domain.Stocktake, error)
+	GetByID(ctx context.Context, id int) (*domain.Stocktake, error)
+	GetByIDForUpdate(ctx context.Context, id int) (*domain.Stocktake, error)
+	GetOpen(ctx context.Context) (*domain.Stocktake, error)
+	// SaveCount inserts or replaces the count of one ingredient together with
+	// the system qty and unit cost at counting time
+	SaveCount(ctx context.Context, line *domain.StocktakeLine) error
+	DeleteCount(ctx context.Context, stocktakeID, ingredientID int) error
+	// Close sets the final status together with who closed it and when
+	Close(ctx context.Context, stocktake *domain.Stocktake) error
+}
+
+// WasteRepository defines methods for the waste log
+type WasteRepository interface {
+	// Create inserts the entry together with its items
+	Create(ctx context.Context, entry *domain.WasteEntry) error
+	GetAll(ctx context.Context, filter domain.WasteFilter) ([]domain.WasteEntry, error)
+	GetByID(ctx context.Context, id int) (*domain.WasteEntry, error)
 }
 
 // OrderRepository defines methods for order data access
@@ -65,6 +195,10 @@ type OrderRepository interface {
 	Create(ctx context.Context, order *domain.Order) error
 	GetByID(ctx context.Context, id int) (*domain.Order, error)
 	GetAll(ctx context.Context, status *domain.OrderStatus) ([]domain.Order, error)
+	GetOpenIDsByTable(ctx context.Context, tableID int) ([]int, error)
+	// ReassignOpen gives open orders of one waiter to another; nil tableID reassigns all of them
+	ReassignOpen(ctx context.Context, fromWaiterID, toWaiterID int, tableID *int) (int, error)
+	Lock(ctx context.Context, id int) error
 	UpdateStatus(ctx context.Context, id int, status domain.OrderStatus) error
 	Update(ctx context.Context, order *domain.Order) error
 	Delete(ctx context.Context, id int) error
@@ -73,15 +207,93 @@ type OrderRepository interface {
 	AddItem(ctx context.Context, item *domain.OrderItem) error
 	GetItems(ctx context.Context, orderID int) ([]domain.OrderItem, error)
 	UpdateItem(ctx context.Context, item *domain.OrderItem) error
+	UpdateItemStatus(ctx context.Context, itemID int, status domain.OrderItemStatus) error
+	UpdateItemPricing(ctx context.Context, itemID int, discount, tax float64) error
 	DeleteItem(ctx context.Context, itemID int) error
+
+	// Combo lines; their component items are stored with AddItem
+	AddCombo(ctx context.Context, line *domain.OrderCombo) error
+	GetCombos(ctx context.Context, orderID int) ([]domain.OrderCombo, error)
+	DeleteCombo(ctx context.Context, lineID int) error
+
+	// MergeInto moves items, combo lines, comps and resolved voids of one order to another
+	MergeInto(ctx context.Context, fromOrderID, toOrderID int) error
+
+	// Kitchen display
+	GetKitchenQueue(ctx context.Context) ([]domain.KitchenItem, error)
+}
+
+// PaymentRepository defines methods for payment and bill split data access
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *domain.Payment) error
+	GetByOrderID(ctx context.Context, orderID int) ([]domain.Payment, error)
+	GetPaidTotal(ctx context.Context, orderID int) (float64, error)
+
+	// Bill splits
+	CreateSplit(ctx context.Context, split *domain.BillSplit) error
+	GetSplits(ctx context.Context, orderID int) ([]domain.BillSplit, error)
+	DeleteSplits(ctx context.Context, orderID int) error
+}
+
+// PromotionRepository defines methods for promotions and the discounts applied to orders
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *domain.Promotion) error
+	GetAll(ctx context.Context) ([]domain.Promotion, error)
+	GetActive(ctx context.Context) ([]domain.Promotion, error)
+	GetByID(ctx context.Context, id int) (*domain.Promotion, error)
+	GetByCode(ctx context.Context, code string) (*domain.Promotion, error)
+	Update(ctx context.Context, promotion *domain.Promotion) error
+	Delete(ctx context.Context, id int) error
+
+	// Applied discounts
+	CreateDiscount(ctx context.Context, discount *domain.OrderDiscount) error
+	GetDiscounts(ctx context.Context, orderID int) ([]domain.OrderDiscount, error)
+	UpdateDiscountAmount(ctx context.Context, id int, amount float64) error
+	DeleteDiscount(ctx context.Context, id int) error
+	DeletePromotionDiscounts(ctx context.Context, orderID int) error
+}
+
+// VoidRepository defines methods for order void data access
+type VoidRepository interface {
+	Create(ctx context.Context, v *domain.OrderVoid) error
+	GetByID(ctx context.Context, id int) (*domain.OrderVoid, error)
+	GetByOrderID(ctx context.Context, orderID int) ([]domain.OrderVoid, error)
+	GetPending(ctx context.Context) ([]domain.OrderVoid, error)
+	Resolve(ctx context.Context, id int, status domain.VoidStatus, approvedBy int) error
 }
 
 // SupplyRepository defines methods for supply data access
+// SupplierRepository defines methods for suppliers and their catalogs
+type SupplierRepository interface {
+	GetAll(ctx context.Context, activeOnly bool) ([]domain.Supplier, error)
+	// GetByID returns the supplier with its catalog
+	GetByID(ctx context.Context, id int) (*domain.Supplier, error)
+	Create(ctx context.Context, supplier *domain.Supplier) error
+	Update(ctx context.Context, supplier *domain.Supplier) error
+	Delete(ctx context.Context, id int) error
+	// SaveCatalogItem inserts or replaces the price of one ingredient
+	SaveCatalogItem(ctx context.Context, item *domain.SupplierIngredient) error
+	DeleteCatalogItem(ctx context.Context, supplierID, ingredientID int) error
+}
+
+// PurchaseOrderRepository defines methods for purchase orders to suppliers
+type PurchaseOrderRepository interface {
+	Create(ctx context.Context, po *domain.PurchaseOrder) error
+	// Update rewrites the header and replaces the items
+	Update(ctx context.Context, po *domain.PurchaseOrder) error
+	GetAll(ctx context.Context, filter domain.PurchaseOrderFilter) ([]domain.PurchaseOrder, error)
+	GetByID(ctx context.Context, id int) (*domain.PurchaseOrder, error)
+	GetByIDForUpdate(ctx context.Context, id int) (*domain.PurchaseOrder, error)
+	SetStatus(ctx context.Context, po *domain.PurchaseOrder) error
+	AddReceived(ctx context.Context, itemID int, qty float64) error
+}
+
 type SupplyRepository interface {
 	Create(ctx context.Context, supply *domain.Supply) error
 	GetAll(ctx context.Context) ([]domain.Supply, error)
 	GetByID(ctx context.Context, id int) (*domain.Supply, error)
 	GetByIngredientID(ctx context.Context, ingredientID int) ([]domain.Supply, error)
+	SetInvoiceURL(ctx context.Context, id int, url string) error
 }
 
 type AnalyticsRepository interface {
@@ -90,9 +302,20 @@ type AnalyticsRepository interface {
 	GetSalesByCategory(ctx context.Context, from, to time.Time) ([]domain.CategorySale, error)
 	GetPopularDishes(ctx context.Context, from, to time.Time, limit int) ([]domain.PopularDish, error)
 	GetWaiterPerformance(ctx context.Context, from, to time.Time) ([]domain.WaiterPerformance, error)
+	GetRevenueByPaymentMethod(ctx context.Context, from, to time.Time) ([]domain.PaymentMethodRevenue, error)
+	GetTipsByWaiter(ctx context.Context, from, to time.Time) ([]domain.WaiterTips, error)
+	GetDiscountCost(ctx context.Context, from, to time.Time) ([]domain.DiscountCost, error)
 	GetOrderStats(ctx context.Context, from, to time.Time) (*domain.OrderStats, error)
 	GetIngredientTurnover(ctx context.Context, from, to time.Time) ([]domain.IngredientTurnover, error)
 	GetTableUtilization(ctx context.Context, from, to time.Time) ([]domain.TableUtilization, error)
 	GetHourlyRevenue(ctx context.Context, date time.Time) ([]domain.HourlyRevenue, error)
 	GetDishAvailability(ctx context.Context) ([]domain.DishAvailability, error)
+	GetWasteByReason(ctx context.Context, from, to time.Time) ([]domain.WasteByReason, error)
+	GetWasteByIngredient(ctx context.Context, from, to time.Time) ([]domain.WasteByIngredient, error)
+	GetWasteByDay(ctx context.Context, from, to time.Time) ([]domain.WasteByDay, error)
+	GetSpendBySupplier(ctx context.Context, from, to time.Time) ([]domain.SupplierSpend, error)
+	GetSpendByIngredient(ctx context.Context, from, to time.Time) ([]domain.IngredientSpend, error)
+	GetSpendByPeriod(ctx context.Context, from, to time.Time, period domain.SpendPeriod) ([]domain.PeriodSpend, error)
+	CountUnpricedSupplies(ctx context.Context, from, to time.Time) (int, error)
+	GetPriceHistory(ctx context.Context, ingredientID int, supplierID *int) ([]domain.PricePoint, error)
 }
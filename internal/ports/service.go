@@ -25,12 +25,57 @@ type UserService interface {
 
 // OrderService defines methods for order management
 type OrderService interface {
-	Create(ctx context.Context, order *domain.Order, items []domain.OrderItem) error
+	Create(ctx context.Context, order *domain.Order, items []domain.OrderItem, combos []domain.OrderCombo) error
 	GetByID(ctx context.Context, id int) (*domain.Order, error)
 	GetAll(ctx context.Context, status *domain.OrderStatus) ([]domain.Order, error)
 	UpdateStatus(ctx context.Context, id int, newStatus domain.OrderStatus) error
-	CloseOrder(ctx context.Context, id int) error
+	CloseOrder(ctx context.Context, payment *domain.Payment) error
 	Delete(ctx context.Context, id int) error
+
+	// Order items
+	AddItem(ctx context.Context, orderID int, item *domain.OrderItem) error
+	UpdateItem(ctx context.Context, orderID int, item *domain.OrderItem) error
+	RemoveItem(ctx context.Context, orderID, itemID int) error
+	UpdateItemStatus(ctx context.Context, orderID, itemID int, status domain.OrderItemStatus) error
+	AddCombo(ctx context.Context, orderID int, line *domain.OrderCombo) error
+	RemoveCombo(ctx context.Context, orderID, lineID int) error
+
+	// Kitchen display
+	GetKitchenQueue(ctx context.Context) ([]domain.KitchenStation, error)
+
+	// Cancellations and voids
+	CancelOrder(ctx context.Context, v *domain.OrderVoid, byManager bool) error
+	VoidItem(ctx context.Context, v *domain.OrderVoid, byManager bool) error
+	ApproveVoid(ctx context.Context, voidID, managerID int) error
+	RejectVoid(ctx context.Context, voidID, managerID int) error
+	GetVoids(ctx context.Context, orderID int) ([]domain.OrderVoid, error)
+	GetPendingVoids(ctx context.Context) ([]domain.OrderVoid, error)
+
+	// Discounts
+	ApplyPromoCode(ctx context.Context, orderID int, code string) error
+	AddComp(ctx context.Context, discount *domain.OrderDiscount) error
+	RemoveDiscount(ctx context.Context, orderID, discountID int) error
+	SetServiceCharge(ctx context.Context, orderID int, rate float64) error
+
+	// Moving guests between tables
+	MoveOrder(ctx context.Context, orderID, tableID int) error
+	MergeOrders(ctx context.Context, targetID int, req domain.MergeRequest) error
+}
+
+// PromotionService defines methods for promotion management
+type PromotionService interface {
+	GetAll(ctx context.Context) ([]domain.Promotion, error)
+	GetByID(ctx context.Context, id int) (*domain.Promotion, error)
+	Create(ctx context.Context, promotion *domain.Promotion) error
+	Update(ctx context.Context, promotion *domain.Promotion) error
+	Delete(ctx context.Context, id int) error
+}
+
+// PaymentService defines methods for bill splitting and payments
+type PaymentService interface {
+	SplitOrder(ctx context.Context, orderID int, req *domain.SplitRequest) ([]domain.BillSplit, error)
+	GetBill(ctx context.Context, orderID int) (*domain.Bill, error)
+	Pay(ctx context.Context, payment *domain.Payment) error
 }
 
 // DishService defines methods for dish management
@@ -44,6 +89,35 @@ type DishService interface {
 	GetIngredients(ctx context.Context, dishID int) ([]domain.DishIngredient, error)
 	AddIngredient(ctx context.Context, dishIngredient *domain.DishIngredient) error
 	RemoveIngredient(ctx context.Context, dishID, ingredientID int) error
+	GetModifiers(ctx context.Context, dishID int) ([]domain.ModifierGroup, error)
+	CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error
+	UpdateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error
+	DeleteModifierGroup(ctx context.Context, dishID, groupID int) error
+	CreateModifierOption(ctx context.Context, dishID int, option *domain.ModifierOption) error
+	UpdateModifierOption(ctx context.Context, dishID int, option *domain.ModifierOption) error
+	DeleteModifierOption(ctx context.Context, dishID, groupID, optionID int) error
+	SetSoldOut(ctx context.Context, id int, soldOut bool) error
+}
+
+// MenuService defines methods for menus and their schedules
+type MenuService interface {
+	GetAll(ctx context.Context) ([]domain.Menu, error)
+	GetCurrent(ctx context.Context) ([]domain.Menu, error)
+	GetByID(ctx context.Context, id int) (*domain.Menu, error)
+	Create(ctx context.Context, menu *domain.Menu) error
+	Update(ctx context.Context, menu *domain.Menu) error
+	Delete(ctx context.Context, id int) error
+	SetDish(ctx context.Context, menuDish *domain.MenuDish) error
+	RemoveDish(ctx context.Context, menuID, dishID int) error
+}
+
+// ComboService defines methods for combo meal management
+type ComboService interface {
+	GetAll(ctx context.Context, activeOnly bool) ([]domain.Combo, error)
+	GetByID(ctx context.Context, id int) (*domain.Combo, error)
+	Create(ctx context.Context, combo *domain.Combo) error
+	Update(ctx context.Context, combo *domain.Combo) error
+	Delete(ctx context.Context, id int) error
 }
 
 // IngredientService defines methods for ingredient management
@@ -51,27 +125,109 @@ type IngredientService interface {
 	GetAll(ctx context.Context) ([]domain.Ingredient, error)
 	GetByID(ctx context.Context, id int) (*domain.Ingredient, error)
 	GetLowStock(ctx context.Context) ([]domain.Ingredient, error)
-	Create(ctx context.Context, ingredient *domain.Ingredient) error
-	Update(ctx context.Context, ingredient *domain.Ingredient) error
+	// Create and Update record a stock change as a count adjustment by userID
+	Create(ctx context.Context, ingredient *domain.Ingredient, userID int) error
+	Update(ctx context.Context, ingredient *domain.Ingredient, userID int) error
 	Delete(ctx context.Context, id int) error
+	GetMovements(ctx context.Context, filter domain.StockMovementFilter) ([]domain.StockMovement, error)
+}
+
+// PrepService defines methods for prep items and production batches
+type PrepService interface {
+	GetAll(ctx context.Context) ([]domain.PrepItem, error)
+	GetByID(ctx context.Context, ingredientID int) (*domain.PrepItem, error)
+	Save(ctx context.Context, prep *domain.PrepItem) error
+	Delete(ctx context.Context, ingredientID int) error
+	Produce(ctx context.Context, batch *domain.ProductionBatch) error
+	GetBatches(ctx context.Context, prepID *int, limit int) ([]domain.ProductionBatch, error)
+}
+
+// StocktakeService defines methods for the stocktake (inventory count) workflow
+type StocktakeService interface {
+	Start(ctx context.Context, note *string, userID int) (*domain.Stocktake, error)
+	GetAll(ctx context.Context) ([]domain.Stocktake, error)
+	GetByID(ctx context.Context, id int) (*domain.Stocktake, error)
+	SaveCounts(ctx context.Context, id int, counts []domain.StocktakeLine, userID int) (*domain.Stocktake, error)
+	RemoveCount(ctx context.Context, id, ingredientID int) error
+	Post(ctx context.Context, id int, userID int) (*domain.Stocktake, error)
+	Cancel(ctx context.Context, id int, userID int) (*domain.Stocktake, error)
+}
+
+// WasteService defines methods for logging waste and spoilage
+type WasteService interface {
+	Log(ctx context.Context, entry *domain.WasteEntry) error
+	GetAll(ctx context.Context, filter domain.WasteFilter) ([]domain.WasteEntry, error)
+	GetByID(ctx context.Context, id int) (*domain.WasteEntry, error)
+}
+
+// SupplierService defines methods for suppliers and their catalogs
+type SupplierService interface {
+	GetAll(ctx context.Context, activeOnly bool) ([]domain.Supplier, error)
+	GetByID(ctx context.Context, id int) (*domain.Supplier, error)
+	Create(ctx context.Context, supplier *domain.Supplier) error
+	Update(ctx context.Context, supplier *domain.Supplier) error
+	Delete(ctx context.Context, id int) error
+	SaveCatalogItem(ctx context.Context, item *domain.SupplierIngredient) error
+	RemoveCatalogItem(ctx context.Context, supplierID, ingredientID int) error
+}
+
+// PurchaseOrderService defines methods for the purchase order lifecycle
+type PurchaseOrderService interface {
+	Create(ctx context.Context, po *domain.PurchaseOrder) error
+	Update(ctx context.Context, po *domain.PurchaseOrder) error
+	GetAll(ctx context.Context, filter domain.PurchaseOrderFilter) ([]domain.PurchaseOrder, error)
+	GetByID(ctx context.Context, id int) (*domain.PurchaseOrder, error)
+	Send(ctx context.Context, id int) (*domain.PurchaseOrder, error)
+	Receive(ctx context.Context, id int, receipt domain.Receipt, userID int) (*domain.PurchaseOrder, error)
+	Cancel(ctx context.Context, id int) (*domain.PurchaseOrder, error)
 }
 
 // SupplyService defines methods for supply management
 type SupplyService interface {
 	Create(ctx context.Context, supply *domain.Supply) error
 	GetAll(ctx context.Context) ([]domain.Supply, error)
+	GetByID(ctx context.Context, id int) (*domain.Supply, error)
 	GetByIngredientID(ctx context.Context, ingredientID int) ([]domain.Supply, error)
+	AttachInvoice(ctx context.Context, id int, url string) (*domain.Supply, error)
 }
 
 // TableService defines methods for table management
 type TableService interface {
 	GetAll(ctx context.Context) ([]domain.Table, error)
 	GetByID(ctx context.Context, id int) (*domain.Table, error)
+	GetFloor(ctx context.Context) ([]domain.FloorTable, error)
 	Create(ctx context.Context, table *domain.Table) error
+	Update(ctx context.Context, table *domain.Table) error
+	UpdateLayout(ctx context.Context, positions []domain.TablePosition) error
 	UpdateStatus(ctx context.Context, id int, status domain.TableStatus) error
 	Delete(ctx context.Context, id int) error
 }
 
+// ReservationService defines methods for table bookings
+type ReservationService interface {
+	GetAll(ctx context.Context, filter domain.ReservationFilter) ([]domain.Reservation, error)
+	GetByID(ctx context.Context, id int) (*domain.Reservation, error)
+	FindAvailable(ctx context.Context, from time.Time, partySize int) ([]domain.Table, error)
+	Create(ctx context.Context, reservation *domain.Reservation) error
+	Update(ctx context.Context, reservation *domain.Reservation) error
+	Cancel(ctx context.Context, id int) error
+	Seat(ctx context.Context, id int) error
+}
+
+type WaitlistService interface {
+	GetQueue(ctx context.Context) ([]domain.WaitlistEntry, error)
+	Estimate(ctx context.Context, partySize int) (*domain.WaitEstimate, error)
+	Add(ctx context.Context, entry *domain.WaitlistEntry) error
+	Cancel(ctx context.Context, id int) error
+	Seat(ctx context.Context, id, tableID int) error
+}
+
+type SectionService interface {
+	GetSections(ctx context.Context, shiftDate time.Time) ([]domain.Section, error)
+	Assign(ctx context.Context, section *domain.Section, assignedBy int) error
+	Handover(ctx context.Context, handover *domain.Handover) error
+}
+
 type CategoryService interface {
 	GetAll(ctx context.Context) ([]domain.Category, error)
 	GetByID(ctx context.Context, id int) (*domain.Category, error)
@@ -86,9 +242,18 @@ type AnalyticsService interface {
 	GetSalesByCategory(ctx context.Context, from, to time.Time) ([]domain.CategorySale, error)
 	GetPopularDishes(ctx context.Context, from, to time.Time, limit int) ([]domain.PopularDish, error)
 	GetWaiterPerformance(ctx context.Context, from, to time.Time) ([]domain.WaiterPerformance, error)
+	GetRevenueByPaymentMethod(ctx context.Context, from, to time.Time) ([]domain.PaymentMethodRevenue, error)
+	GetTipsByWaiter(ctx context.Context, from, to time.Time) ([]domain.WaiterTips, error)
+	GetDiscountCost(ctx context.Context, from, to time.Time) ([]domain.DiscountCost, error)
 	GetOrderStats(ctx context.Context, from, to time.Time) (*domain.OrderStats, error)
 	GetIngredientTurnover(ctx context.Context, from, to time.Time) ([]domain.IngredientTurnover, error)
 	GetTableUtilization(ctx context.Context, from, to time.Time) ([]domain.TableUtilization, error)
 	GetHourlyRevenue(ctx context.Context, date time.Time) ([]domain.HourlyRevenue, error)
 	GetDishAvailability(ctx context.Context) ([]domain.DishAvailability, error)
+	GetDishCosts(ctx context.Context) ([]domain.DishCost, error)
+	GetCategoryMargins(ctx context.Context) ([]domain.CategoryMargin, error)
+	GetMenuEngineering(ctx context.Context, from, to time.Time) (*domain.MenuEngineering, error)
+	GetWasteReport(ctx context.Context, from, to time.Time) (*domain.WasteReport, error)
+	GetSpendReport(ctx context.Context, from, to time.Time, period domain.SpendPeriod) (*domain.SpendReport, error)
+	GetPriceHistory(ctx context.Context, ingredientID int, supplierID *int) ([]domain.PricePoint, error)
 }
@@ -12,10 +12,12 @@ import (
 
 type AnalyticsService struct {
 	analyticsRepo ports.AnalyticsRepository
+	dishRepo      ports.DishRepository
+	prepRepo      ports.PrepRepository
 }
 
-func NewAnalyticsService(analyticsRepo ports.AnalyticsRepository) *AnalyticsService {
-	return &AnalyticsService{analyticsRepo: analyticsRepo}
+func NewAnalyticsService(analyticsRepo ports.AnalyticsRepository, dishRepo ports.DishRepository, prepRepo ports.PrepRepository) *AnalyticsService {
+	return &AnalyticsService{analyticsRepo: analyticsRepo, dishRepo: dishRepo, prepRepo: prepRepo}
 }
 
 // GetDashboard returns complete dashboard data for the specified period
@@ -88,6 +90,21 @@ func (s *AnalyticsService) GetWaiterPerformance(ctx context.Context, from, to ti
 	return s.analyticsRepo.GetWaiterPerformance(ctx, from, to)
 }
 
+// GetRevenueByPaymentMethod returns payments grouped by payment method
+func (s *AnalyticsService) GetRevenueByPaymentMethod(ctx context.Context, from, to time.Time) ([]domain.PaymentMethodRevenue, error) {
+	return s.analyticsRepo.GetRevenueByPaymentMethod(ctx, from, to)
+}
+
+// GetTipsByWaiter returns tips collected per waiter
+func (s *AnalyticsService) GetTipsByWaiter(ctx context.Context, from, to time.Time) ([]domain.WaiterTips, error) {
+	return s.analyticsRepo.GetTipsByWaiter(ctx, from, to)
+}
+
+// GetDiscountCost returns the revenue given away by promotions and comps
+func (s *AnalyticsService) GetDiscountCost(ctx context.Context, from, to time.Time) ([]domain.DiscountCost, error) {
+	return s.analyticsRepo.GetDiscountCost(ctx, from, to)
+}
+
 // GetOrderStats returns order statistics
 func (s *AnalyticsService) GetOrderStats(ctx context.Context, from, to time.Time) (*domain.OrderStats, error) {
 	return s.analyticsRepo.GetOrderStats(ctx, from, to)
@@ -158,3 +175,156 @@ func roundFloat(val float64) float64 {
 func (s *AnalyticsService) GetDishAvailability(ctx context.Context) ([]domain.DishAvailability, error) {
 	return s.analyticsRepo.GetDishAvailability(ctx)
 }
+
+// GetDishCosts returns the recipe cost, food-cost % and margin of every active dish
+func (s *AnalyticsService) GetDishCosts(ctx context.Context) ([]domain.DishCost, error) {
+	dishes, err := s.dishRepo.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.dishCosts(ctx, dishes)
+}
+
+// GetCategoryMargins returns food cost and margin per category of active dishes
+func (s *AnalyticsService) GetCategoryMargins(ctx context.Context) ([]domain.CategoryMargin, error) {
+	costs, err := s.GetDishCosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.MarginsByCategory(costs), nil
+}
+
+// GetMenuEngineering combines dish margins with sales volume for the period.
+// Dishes without a recipe have no known cost and are left out.
+func (s *AnalyticsService) GetMenuEngineering(ctx context.Context, from, to time.Time) (*domain.MenuEngineering, error) {
+	dishes, err := s.dishRepo.GetAll(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(dishes) == 0 {
+		report := domain.ClassifyMenu(nil)
+		return &report, nil
+	}
+
+	sales, err := s.analyticsRepo.GetPopularDishes(ctx, from, to, len(dishes))
+	if err != nil {
+		return nil, err
+	}
+	sold := make(map[int]domain.PopularDish, len(sales))
+	for _, sale := range sales {
+		sold[sale.DishID] = sale
+	}
+
+	costs, err := s.dishCosts(ctx, dishes)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []domain.MenuEngineeringItem
+	for i, dc := range costs {
+		sale, ok := sold[dc.DishID]
+		if !dc.HasRecipe || (!dishes[i].IsActive && !ok) {
+			continue
+		}
+
+		avgPrice := dc.Price
+		if sale.QtySold > 0 {
+			avgPrice = sale.Revenue / float64(sale.QtySold)
+		}
+		items = append(items, domain.MenuEngineeringItem{
+			DishID:       dc.DishID,
+			DishName:     dc.DishName,
+			CategoryName: dc.CategoryName,
+			QtySold:      sale.QtySold,
+			Revenue:      sale.Revenue,
+			AvgPrice:     roundMoney(avgPrice),
+			UnitCost:     dc.Cost,
+			UnitMargin:   roundMoney(avgPrice - dc.Cost),
+			TotalMargin:  roundMoney(sale.Revenue - dc.Cost*float64(sale.QtySold)),
+		})
+	}
+
+	report := domain.ClassifyMenu(items)
+	return &report, nil
+}
+
+// GetWasteReport returns the cost of waste for the period by reason, by
+// ingredient and by day
+func (s *AnalyticsService) GetWasteReport(ctx context.Context, from, to time.Time) (*domain.WasteReport, error) {
+	report := &domain.WasteReport{From: from, To: to}
+
+	var err error
+	if report.ByReason, err = s.analyticsRepo.GetWasteByReason(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.ByIngredient, err = s.analyticsRepo.GetWasteByIngredient(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.ByDay, err = s.analyticsRepo.GetWasteByDay(ctx, from, to); err != nil {
+		return nil, err
+	}
+
+	report.Summarize()
+	return report, nil
+}
+
+// GetSpendReport returns what was paid for supplies in the period by
+// supplier, by ingredient and per day, week or month
+func (s *AnalyticsService) GetSpendReport(ctx context.Context, from, to time.Time, period domain.SpendPeriod) (*domain.SpendReport, error) {
+	report := &domain.SpendReport{From: from, To: to, Period: period}
+
+	var err error
+	if report.BySupplier, err = s.analyticsRepo.GetSpendBySupplier(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.ByIngredient, err = s.analyticsRepo.GetSpendByIngredient(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.ByPeriod, err = s.analyticsRepo.GetSpendByPeriod(ctx, from, to, period); err != nil {
+		return nil, err
+	}
+	if report.UnpricedSupplies, err = s.analyticsRepo.CountUnpricedSupplies(ctx, from, to); err != nil {
+		return nil, err
+	}
+
+	report.Summarize()
+	return report, nil
+}
+
+// GetPriceHistory returns how the purchase price of an ingredient changed,
+// per supplier and currency
+func (s *AnalyticsService) GetPriceHistory(ctx context.Context, ingredientID int, supplierID *int) ([]domain.PricePoint, error) {
+	history, err := s.analyticsRepo.GetPriceHistory(ctx, ingredientID, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range history {
+		history[i].CalculateChange()
+	}
+	return history, nil
+}
+
+// dishCosts prices the recipe of each dish, keeping the order of dishes.
+// Made-to-order prep items are priced by their components at today's costs.
+func (s *AnalyticsService) dishCosts(ctx context.Context, dishes []domain.Dish) ([]domain.DishCost, error) {
+	preps, err := s.prepRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recipes := domain.NewPrepRecipes(preps)
+
+	costs := make([]domain.DishCost, 0, len(dishes))
+	for i := range dishes {
+		recipe, err := s.dishRepo.GetIngredients(ctx, dishes[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		for j := range recipe {
+			if recipe[j].Ingredient != nil {
+				recipe[j].Ingredient.UnitCost = recipes.UnitCost(recipe[j].Ingredient)
+			}
+		}
+		costs = append(costs, domain.NewDishCost(&dishes[i], recipe))
+	}
+	return costs, nil
+}
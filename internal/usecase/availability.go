@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+)
+
+// SetSoldOut снимает блюдо с продажи вручную (86) или возвращает его;
+// официанты и кухня узнают об этом сразу.
+func (s *DishService) SetSoldOut(ctx context.Context, id int, soldOut bool) error {
+	dish, err := s.dishRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if dish == nil {
+		return domain.ErrDishNotFound
+	}
+	if dish.SoldOut == soldOut {
+		return nil
+	}
+
+	if err := s.dishRepo.SetSoldOut(ctx, id, soldOut); err != nil {
+		return err
+	}
+
+	if soldOut {
+		s.logger.Warning("Dish '%s' 86'd by hand", dish.Name)
+	} else {
+		s.logger.Info("Dish '%s' is back on sale", dish.Name)
+	}
+	s.publishAvailability(dish.ID, dish.Name, !soldOut)
+	return nil
+}
+
+// Run следит за остатками: после каждого изменения склада (заказы, отмены,
+// поставки) пересчитывает порции и сообщает о блюдах, которые закончились
+// или снова появились. Блокирует до отмены ctx.
+func (s *DishService) Run(ctx context.Context, subscriber ports.EventSubscriber) {
+	events, unsubscribe := subscriber.Subscribe()
+	defer unsubscribe()
+
+	inStock, err := s.dishRepo.GetPortionsLeft(ctx)
+	if err != nil {
+		s.logger.Error("Failed to compute portions left: %v", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !changesStock(event.Type) {
+				continue
+			}
+
+			portions, err := s.dishRepo.GetPortionsLeft(ctx)
+			if err != nil {
+				s.logger.Error("Failed to recompute portions left: %v", err)
+				continue
+			}
+			if inStock != nil {
+				s.announceStockChanges(ctx, inStock, portions)
+			}
+			inStock = portions
+		}
+	}
+}
+
+// announceStockChanges публикует события по блюдам, у которых остаток
+// перешёл через ноль в ту или другую сторону.
+func (s *DishService) announceStockChanges(ctx context.Context, before, after map[int]int) {
+	for dishID, left := range after {
+		prev, known := before[dishID]
+		if known && (prev > 0) == (left > 0) {
+			continue
+		}
+		if !known && left > 0 {
+			continue
+		}
+
+		dish, err := s.dishRepo.GetByID(ctx, dishID)
+		if err != nil || dish == nil || !dish.IsActive || dish.SoldOut {
+			continue
+		}
+		if left > 0 {
+			s.logger.Info("Dish '%s' is back in stock (%d portions)", dish.Name, left)
+		} else {
+			s.logger.Warning("Dish '%s' ran out of stock — 86'd automatically", dish.Name)
+		}
+		s.publishAvailability(dish.ID, dish.Name, left > 0)
+	}
+}
+
+func (s *DishService) publishAvailability(dishID int, name string, available bool) {
+	status := "available"
+	if !available {
+		status = "sold_out"
+	}
+	s.events.Publish(domain.Event{
+		Type:    domain.EventDishAvailability,
+		DishID:  dishID,
+		Status:  status,
+		Message: name,
+	})
+}
+
+// attachPortions заполняет остаток порций и доступность блюд
+func (s *DishService) attachPortions(ctx context.Context, dishes []domain.Dish) error {
+	portions, err := s.dishRepo.GetPortionsLeft(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range dishes {
+		dishes[i].SetPortionsLeft(portions)
+	}
+	return nil
+}
+
+// changesStock reports whether the event may have moved free ingredient stock
+func changesStock(t domain.EventType) bool {
+	switch t {
+	case domain.EventOrderCreated, domain.EventOrderItemsChanged, domain.EventOrderStatusChanged,
+		domain.EventStockChanged:
+		return true
+	}
+	return false
+}
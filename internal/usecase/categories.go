@@ -31,10 +31,19 @@ func (s *CategoryService) GetByID(ctx context.Context, id int) (*domain.Category
 }
 
 func (s *CategoryService) Create(ctx context.Context, category *domain.Category) error {
+	if category.Station == "" {
+		category.Station = domain.DefaultStation
+	}
+	if !domain.IsValidRate(category.VatRate) {
+		return domain.ErrInvalidRate
+	}
 	return s.categoryRepo.Create(ctx, category)
 }
 
 func (s *CategoryService) Update(ctx context.Context, category *domain.Category) error {
+	if !domain.IsValidRate(category.VatRate) {
+		return domain.ErrInvalidRate
+	}
 	existing, err := s.categoryRepo.GetByID(ctx, category.ID)
 	if err != nil {
 		return err
@@ -42,6 +51,9 @@ func (s *CategoryService) Update(ctx context.Context, category *domain.Category)
 	if existing == nil {
 		return domain.ErrCategoryNotFound
 	}
+	if category.Station == "" {
+		category.Station = existing.Station
+	}
 	return s.categoryRepo.Update(ctx, category)
 }
 
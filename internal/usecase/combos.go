@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+)
+
+type ComboService struct {
+	comboRepo ports.ComboRepository
+	dishRepo  ports.DishRepository
+	txManager ports.TxManager
+}
+
+func NewComboService(comboRepo ports.ComboRepository, dishRepo ports.DishRepository, txManager ports.TxManager) *ComboService {
+	return &ComboService{
+		comboRepo: comboRepo,
+		dishRepo:  dishRepo,
+		txManager: txManager,
+	}
+}
+
+func (s *ComboService) GetAll(ctx context.Context, activeOnly bool) ([]domain.Combo, error) {
+	return s.comboRepo.GetAll(ctx, activeOnly)
+}
+
+func (s *ComboService) GetByID(ctx context.Context, id int) (*domain.Combo, error) {
+	combo, err := s.comboRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if combo == nil {
+		return nil, domain.ErrComboNotFound
+	}
+	return combo, nil
+}
+
+// Create сохраняет комбо вместе со слотами и блюдами на выбор
+func (s *ComboService) Create(ctx context.Context, combo *domain.Combo) error {
+	if !combo.IsValid() {
+		return domain.ErrInvalidCombo
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkDishes(ctx, combo); err != nil {
+			return err
+		}
+		combo.IsActive = true
+		return s.comboRepo.Create(ctx, combo)
+	})
+}
+
+// Update заменяет комбо и его слоты; в уже открытых заказах набор не меняется
+func (s *ComboService) Update(ctx context.Context, combo *domain.Combo) error {
+	if !combo.IsValid() {
+		return domain.ErrInvalidCombo
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.GetByID(ctx, combo.ID); err != nil {
+			return err
+		}
+		if err := s.checkDishes(ctx, combo); err != nil {
+			return err
+		}
+		return s.comboRepo.Update(ctx, combo)
+	})
+}
+
+// Delete снимает комбо с продажи
+func (s *ComboService) Delete(ctx context.Context, id int) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.comboRepo.Delete(ctx, id)
+}
+
+func (s *ComboService) checkDishes(ctx context.Context, combo *domain.Combo) error {
+	for _, slot := range combo.Slots {
+		for _, choice := range slot.Choices {
+			dish, err := s.dishRepo.GetByID(ctx, choice.DishID)
+			if err != nil {
+				return err
+			}
+			if dish == nil {
+				return domain.ErrDishNotFound
+			}
+		}
+	}
+	return nil
+}
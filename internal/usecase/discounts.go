@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+// ApplyPromoCode привязывает промокод к заказу и пересчитывает скидки.
+// Пустой код снимает промокод с заказа.
+func (s *OrderService) ApplyPromoCode(ctx context.Context, orderID int, code string) error {
+	s.logger.Order("Applying promo code %q to order #%d", code, orderID)
+
+	var order *domain.Order
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getUnpaidOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		order.PromoCode = nil
+		if code != "" {
+			promo, err := s.getPromotionByCode(ctx, code)
+			if err != nil {
+				return err
+			}
+			order.PromoCode = promo.Code
+		}
+
+		return s.recalculateTotal(ctx, order)
+	})
+	if err != nil {
+		s.logger.Error("Failed to apply promo code to order #%d: %v", orderID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Order #%d repriced: subtotal %.2f ₸, discount %.2f ₸, total %.2f ₸",
+		orderID, order.Subtotal, order.Discount, order.Total)
+	return nil
+}
+
+// AddComp применяет разовую скидку менеджера на заказ или позицию.
+func (s *OrderService) AddComp(ctx context.Context, d *domain.OrderDiscount) error {
+	if !d.IsValidComp() {
+		return domain.ErrInvalidDiscount
+	}
+	s.logger.Order("Comping order #%d: %s %.2f (%s)", d.OrderID, d.Type, d.Value, d.Name)
+
+	var order *domain.Order
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getUnpaidOrder(ctx, d.OrderID)
+		if err != nil {
+			return err
+		}
+
+		if d.OrderItemID != nil {
+			if _, err := s.findItem(ctx, order.ID, *d.OrderItemID); err != nil {
+				return err
+			}
+		}
+
+		d.Source = domain.DiscountComp
+		d.PromotionID = nil
+		if d.Name == "" {
+			d.Name = "Comp"
+		}
+		if err := s.promotionRepo.CreateDiscount(ctx, d); err != nil {
+			s.logger.Error("Failed to save comp for order #%d: %v", order.ID, err)
+			return err
+		}
+
+		if err := s.recalculateTotal(ctx, order); err != nil {
+			return err
+		}
+
+		// Подтягиваем посчитанную сумму comp
+		for _, applied := range order.Discounts {
+			if applied.ID == d.ID {
+				d.Amount = applied.Amount
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to comp order #%d: %v", d.OrderID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Comp #%d applied to order #%d: -%.2f ₸ (Total: %.2f ₸)", d.ID, order.ID, d.Amount, order.Total)
+	return nil
+}
+
+// RemoveDiscount снимает comp. Скидки по акциям снять нельзя — они пересчитываются сами.
+func (s *OrderService) RemoveDiscount(ctx context.Context, orderID, discountID int) error {
+	s.logger.Warning("Removing discount #%d from order #%d", discountID, orderID)
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.getUnpaidOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		discounts, err := s.promotionRepo.GetDiscounts(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, d := range discounts {
+			if d.ID == discountID && d.Source == domain.DiscountComp {
+				found = true
+			}
+		}
+		if !found {
+			return domain.ErrDiscountNotFound
+		}
+
+		if err := s.promotionRepo.DeleteDiscount(ctx, discountID); err != nil {
+			return err
+		}
+		return s.recalculateTotal(ctx, order)
+	})
+	if err != nil {
+		s.logger.Error("Failed to remove discount #%d: %v", discountID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Discount #%d removed from order #%d", discountID, orderID)
+	return nil
+}
+
+// SetServiceCharge задаёт ставку сервисного сбора заказа вручную,
+// например для банкета; 0 снимает сбор.
+func (s *OrderService) SetServiceCharge(ctx context.Context, orderID int, rate float64) error {
+	if !domain.IsValidRate(rate) {
+		return domain.ErrInvalidRate
+	}
+	s.logger.Order("Setting service charge of order #%d to %.2f%%", orderID, rate)
+
+	var order *domain.Order
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getUnpaidOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		order.ServiceChargeRate = rate
+		return s.recalculateTotal(ctx, order)
+	})
+	if err != nil {
+		s.logger.Error("Failed to set service charge of order #%d: %v", orderID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Order #%d service charge %.2f ₸ (Total: %.2f ₸)", orderID, order.ServiceCharge, order.Total)
+	return nil
+}
+
+func (s *OrderService) getPromotionByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	promo, err := s.promotionRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if promo == nil || !promo.AppliesAt(time.Now()) {
+		return nil, domain.ErrInvalidPromoCode
+	}
+	return promo, nil
+}
@@ -3,21 +3,72 @@ package usecase
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
 )
 
 type DishService struct {
-	dishRepo ports.DishRepository
+	dishRepo       ports.DishRepository
+	modifierRepo   ports.ModifierRepository
+	menuRepo       ports.MenuRepository
+	ingredientRepo ports.IngredientRepository
+	txManager      ports.TxManager
+	events         ports.EventPublisher
+	logger         *logger.Logger
 }
 
-func NewDishService(dishRepo ports.DishRepository) *DishService {
-	return &DishService{dishRepo: dishRepo}
+func NewDishService(
+	dishRepo ports.DishRepository,
+	modifierRepo ports.ModifierRepository,
+	menuRepo ports.MenuRepository,
+	ingredientRepo ports.IngredientRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+) *DishService {
+	return &DishService{
+		dishRepo:       dishRepo,
+		modifierRepo:   modifierRepo,
+		menuRepo:       menuRepo,
+		ingredientRepo: ingredientRepo,
+		txManager:      txManager,
+		events:         events,
+		logger:         logger.New("DishService"),
+	}
 }
 
+// GetAll отдаёт блюда с остатком порций. С activeOnly возвращает только то,
+// что можно заказать прямо сейчас: по открытым меню, не снятое (86) и не
+// закончившееся на складе; цены — из открытых меню.
 func (s *DishService) GetAll(ctx context.Context, activeOnly bool) ([]domain.Dish, error) {
-	return s.dishRepo.GetAll(ctx, activeOnly)
+	dishes, err := s.dishRepo.GetAll(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachPortions(ctx, dishes); err != nil {
+		return nil, err
+	}
+	if !activeOnly {
+		return dishes, nil
+	}
+
+	// Все меню, включая выключенные: открыто ли меню, решает Menu.IsOpenAt
+	menus, err := s.menuRepo.GetAll(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	orderable := dishes[:0]
+	for _, dish := range dishes {
+		if dish.Available && orderableDish(menus, &dish, now) == nil {
+			orderable = append(orderable, dish)
+		}
+	}
+	return orderable, nil
 }
 
 func (s *DishService) GetByID(ctx context.Context, id int) (*domain.Dish, error) {
@@ -28,19 +79,40 @@ func (s *DishService) GetByID(ctx context.Context, id int) (*domain.Dish, error)
 	if dish == nil {
 		return nil, domain.ErrDishNotFound
 	}
+
+	dish.ModifierGroups, err = s.modifierRepo.GetGroupsByDish(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	portions, err := s.dishRepo.GetPortionsLeft(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dish.SetPortionsLeft(portions)
 	return dish, nil
 }
 
 func (s *DishService) GetByCategoryID(ctx context.Context, categoryID int) ([]domain.Dish, error) {
-	return s.dishRepo.GetByCategoryID(ctx, categoryID)
+	dishes, err := s.dishRepo.GetByCategoryID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return dishes, s.attachPortions(ctx, dishes)
 }
 
 func (s *DishService) Create(ctx context.Context, dish *domain.Dish) error {
+	if dish.VatRate != nil && !domain.IsValidRate(*dish.VatRate) {
+		return domain.ErrInvalidRate
+	}
 	dish.IsActive = true
 	return s.dishRepo.Create(ctx, dish)
 }
 
 func (s *DishService) Update(ctx context.Context, dish *domain.Dish) error {
+	if dish.VatRate != nil && !domain.IsValidRate(*dish.VatRate) {
+		return domain.ErrInvalidRate
+	}
 	existing, err := s.dishRepo.GetByID(ctx, dish.ID)
 	if err != nil {
 		return err
@@ -67,3 +139,166 @@ func (s *DishService) AddIngredient(ctx context.Context, dishIngredient *domain.
 func (s *DishService) RemoveIngredient(ctx context.Context, dishID, ingredientID int) error {
 	return s.dishRepo.RemoveIngredient(ctx, dishID, ingredientID)
 }
+
+// GetModifiers возвращает группы модификаторов блюда вместе со снятыми с продажи вариантами
+func (s *DishService) GetModifiers(ctx context.Context, dishID int) ([]domain.ModifierGroup, error) {
+	if _, err := s.GetByID(ctx, dishID); err != nil {
+		return nil, err
+	}
+	return s.modifierRepo.GetGroupsByDish(ctx, dishID, false)
+}
+
+// CreateModifierGroup добавляет группу к блюду; варианты можно передать сразу
+func (s *DishService) CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	if !group.IsValid() {
+		return domain.ErrInvalidModifier
+	}
+	for i := range group.Options {
+		if !isValidModifierOption(&group.Options[i]) {
+			return domain.ErrInvalidModifier
+		}
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.GetByID(ctx, group.DishID); err != nil {
+			return err
+		}
+		if err := s.modifierRepo.CreateGroup(ctx, group); err != nil {
+			return err
+		}
+		for i := range group.Options {
+			group.Options[i].GroupID = group.ID
+			group.Options[i].IsActive = true
+			if err := s.saveModifierOption(ctx, &group.Options[i], true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateModifierGroup меняет название и правила выбора; варианты правятся отдельно
+func (s *DishService) UpdateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	if !group.IsValid() {
+		return domain.ErrInvalidModifier
+	}
+
+	existing, err := s.getModifierGroup(ctx, group.DishID, group.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.modifierRepo.UpdateGroup(ctx, group); err != nil {
+		return err
+	}
+	group.Options = existing.Options
+	return nil
+}
+
+func (s *DishService) DeleteModifierGroup(ctx context.Context, dishID, groupID int) error {
+	if _, err := s.getModifierGroup(ctx, dishID, groupID); err != nil {
+		return err
+	}
+	return s.modifierRepo.DeleteGroup(ctx, groupID)
+}
+
+func (s *DishService) CreateModifierOption(ctx context.Context, dishID int, option *domain.ModifierOption) error {
+	if !isValidModifierOption(option) {
+		return domain.ErrInvalidModifier
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.getModifierGroup(ctx, dishID, option.GroupID); err != nil {
+			return err
+		}
+		option.IsActive = true
+		return s.saveModifierOption(ctx, option, true)
+	})
+}
+
+// UpdateModifierOption меняет вариант и его влияние на рецепт. Уже заказанные
+// позиции сохраняют цену; списываются они по новому рецепту, а резерв
+// снимается ровно тот, что был сделан при заказе.
+func (s *DishService) UpdateModifierOption(ctx context.Context, dishID int, option *domain.ModifierOption) error {
+	if !isValidModifierOption(option) {
+		return domain.ErrInvalidModifier
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := s.modifierRepo.GetOptionByID(ctx, option.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil || existing.GroupID != option.GroupID {
+			return domain.ErrModifierOptionNotFound
+		}
+		if _, err := s.getModifierGroup(ctx, dishID, option.GroupID); err != nil {
+			return err
+		}
+		return s.saveModifierOption(ctx, option, false)
+	})
+}
+
+// DeleteModifierOption снимает вариант с продажи; в прошлых заказах он остаётся
+func (s *DishService) DeleteModifierOption(ctx context.Context, dishID, groupID, optionID int) error {
+	option, err := s.modifierRepo.GetOptionByID(ctx, optionID)
+	if err != nil {
+		return err
+	}
+	if option == nil || option.GroupID != groupID {
+		return domain.ErrModifierOptionNotFound
+	}
+	if _, err := s.getModifierGroup(ctx, dishID, groupID); err != nil {
+		return err
+	}
+	return s.modifierRepo.DeleteOption(ctx, optionID)
+}
+
+func (s *DishService) saveModifierOption(ctx context.Context, option *domain.ModifierOption, create bool) error {
+	for _, mi := range option.Ingredients {
+		ingredient, err := s.ingredientRepo.GetByID(ctx, mi.IngredientID)
+		if err != nil {
+			return err
+		}
+		if ingredient == nil {
+			return domain.ErrIngredientNotFound
+		}
+	}
+
+	var err error
+	if create {
+		err = s.modifierRepo.CreateOption(ctx, option)
+	} else {
+		err = s.modifierRepo.UpdateOption(ctx, option)
+	}
+	if err != nil {
+		return err
+	}
+	return s.modifierRepo.SetOptionIngredients(ctx, option.ID, option.Ingredients)
+}
+
+func (s *DishService) getModifierGroup(ctx context.Context, dishID, groupID int) (*domain.ModifierGroup, error) {
+	group, err := s.modifierRepo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil || group.DishID != dishID {
+		return nil, domain.ErrModifierGroupNotFound
+	}
+	return group, nil
+}
+
+func isValidModifierOption(option *domain.ModifierOption) bool {
+	option.Name = strings.TrimSpace(option.Name)
+	if option.Name == "" {
+		return false
+	}
+
+	seen := make(map[int]bool, len(option.Ingredients))
+	for _, mi := range option.Ingredients {
+		if mi.QtyDelta == 0 || seen[mi.IngredientID] {
+			return false
+		}
+		seen[mi.IngredientID] = true
+	}
+	return true
+}
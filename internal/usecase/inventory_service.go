@@ -9,10 +9,12 @@ import (
 
 type IngredientService struct {
 	ingredientRepo ports.IngredientRepository
+	txManager      ports.TxManager
+	events         ports.EventPublisher
 }
 
-func NewIngredientService(ingredientRepo ports.IngredientRepository) *IngredientService {
-	return &IngredientService{ingredientRepo: ingredientRepo}
+func NewIngredientService(ingredientRepo ports.IngredientRepository, txManager ports.TxManager, events ports.EventPublisher) *IngredientService {
+	return &IngredientService{ingredientRepo: ingredientRepo, txManager: txManager, events: events}
 }
 
 func (s *IngredientService) GetAll(ctx context.Context) ([]domain.Ingredient, error) {
@@ -27,14 +29,93 @@ func (s *IngredientService) GetLowStock(ctx context.Context) ([]domain.Ingredien
 	return s.ingredientRepo.GetLowStock(ctx)
 }
 
-func (s *IngredientService) Create(ctx context.Context, ingredient *domain.Ingredient) error {
-	return s.ingredientRepo.Create(ctx, ingredient)
+// Create заводит ингредиент; начальный остаток попадает в журнал как корректировка
+func (s *IngredientService) Create(ctx context.Context, ingredient *domain.Ingredient, userID int) error {
+	opening := ingredient.Qty
+	ingredient.Qty = 0
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ingredientRepo.Create(ctx, ingredient); err != nil {
+			return err
+		}
+		if opening == 0 {
+			return nil
+		}
+
+		note := "opening stock"
+		m := &domain.StockMovement{
+			IngredientID: ingredient.ID,
+			Type:         domain.MovementAdjustment,
+			Qty:          opening,
+			UserID:       &userID,
+			Note:         &note,
+		}
+		if err := s.ingredientRepo.ApplyMovement(ctx, m); err != nil {
+			return err
+		}
+		ingredient.Qty = m.QtyAfter
+		return nil
+	})
 }
 
-func (s *IngredientService) Update(ctx context.Context, ingredient *domain.Ingredient) error {
-	return s.ingredientRepo.Update(ctx, ingredient)
+// Update может поправить остаток вручную — разница пишется в журнал
+// корректировкой, доступность блюд пересчитывается
+func (s *IngredientService) Update(ctx context.Context, ingredient *domain.Ingredient, userID int) error {
+	counted := ingredient.Qty
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.ingredientRepo.GetByIDForUpdate(ctx, ingredient.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return domain.ErrIngredientNotFound
+		}
+
+		ingredient.Qty = current.Qty
+		ingredient.ReservedQty = current.ReservedQty
+		if err := s.ingredientRepo.Update(ctx, ingredient); err != nil {
+			return err
+		}
+		if counted == current.Qty {
+			return nil
+		}
+
+		m := &domain.StockMovement{
+			IngredientID: ingredient.ID,
+			Type:         domain.MovementAdjustment,
+			Qty:          counted - current.Qty,
+			UserID:       &userID,
+		}
+		if err := s.ingredientRepo.ApplyMovement(ctx, m); err != nil {
+			return err
+		}
+		ingredient.Qty = m.QtyAfter
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Publish(domain.Event{Type: domain.EventStockChanged})
+	return nil
 }
 
 func (s *IngredientService) Delete(ctx context.Context, id int) error {
+	existing, err := s.ingredientRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return domain.ErrIngredientNotFound
+	}
 	return s.ingredientRepo.Delete(ctx, id)
 }
+
+// GetMovements returns the stock ledger, newest first
+func (s *IngredientService) GetMovements(ctx context.Context, filter domain.StockMovementFilter) ([]domain.StockMovement, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	return s.ingredientRepo.GetMovements(ctx, filter)
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+// validItemTransitions описывает жизненный цикл позиции на кухне
+var validItemTransitions = map[domain.OrderItemStatus][]domain.OrderItemStatus{
+	domain.ItemQueued:  {domain.ItemCooking, domain.ItemDone},
+	domain.ItemCooking: {domain.ItemDone},
+	domain.ItemDone:    {domain.ItemServed},
+}
+
+func (s *OrderService) UpdateItemStatus(ctx context.Context, orderID, itemID int, status domain.OrderItemStatus) error {
+	s.logger.Order("Updating item #%d of order #%d status to: %s", itemID, orderID, status)
+
+	var from domain.OrderItemStatus
+	var order *domain.Order
+	var orderStatus domain.OrderStatus
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Блокируем заказ: выход из new списывает склад и не должен
+		// пересечься со сменой статуса, отменой или объединением
+		if err := s.orderRepo.Lock(ctx, orderID); err != nil {
+			return err
+		}
+
+		var err error
+		order, err = s.orderRepo.GetByID(ctx, orderID)
+		if err != nil {
+			s.logger.Error("Failed to get order #%d: %v", orderID, err)
+			return err
+		}
+		if order == nil {
+			return domain.ErrOrderNotFound
+		}
+		if !order.Status.IsOpen() {
+			return domain.ErrOrderNotEditable
+		}
+		if err := s.sections.checkOrder(ctx, order); err != nil {
+			return err
+		}
+		orderStatus = order.Status
+
+		item, err := s.findItem(ctx, orderID, itemID)
+		if err != nil {
+			return err
+		}
+		from = item.Status
+
+		if item.Status == status {
+			return nil
+		}
+		if !isValidItemTransition(item.Status, status) {
+			s.logger.Error("Invalid item status transition from %s to %s", item.Status, status)
+			return domain.ErrInvalidStatusChange
+		}
+
+		if err := s.orderRepo.UpdateItemStatus(ctx, itemID, status); err != nil {
+			s.logger.Error("Failed to update item #%d status: %v", itemID, err)
+			return err
+		}
+
+		return s.syncOrderStatus(ctx, order)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Success("✓ Item #%d of order #%d status updated: %s → %s", itemID, orderID, from, status)
+
+	if from != status {
+		event := newOrderEvent(domain.EventOrderItemStatusChanged, order)
+		event.ItemID = itemID
+		event.Status = string(status)
+		s.events.Publish(event)
+	}
+	if order.Status != orderStatus {
+		s.publishStatusEvent(order)
+	}
+	return nil
+}
+
+// GetKitchenQueue возвращает очередь кухни, сгруппированную по станциям.
+// Внутри станции позиции идут от самых старых; станции — по самой старой позиции.
+func (s *OrderService) GetKitchenQueue(ctx context.Context) ([]domain.KitchenStation, error) {
+	items, err := s.orderRepo.GetKitchenQueue(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get kitchen queue: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	stations := []domain.KitchenStation{}
+	index := make(map[string]int)
+	for _, item := range items {
+		item.WaitingMinutes = int(now.Sub(item.CreatedAt).Minutes())
+
+		i, ok := index[item.Station]
+		if !ok {
+			i = len(stations)
+			index[item.Station] = i
+			stations = append(stations, domain.KitchenStation{Station: item.Station})
+		}
+		stations[i].Items = append(stations[i].Items, item)
+	}
+
+	return stations, nil
+}
+
+// syncOrderStatus выводит статус заказа из статусов его позиций и сохраняет его.
+// При выходе из new списываются ингредиенты, как и при ручной смене статуса.
+func (s *OrderService) syncOrderStatus(ctx context.Context, order *domain.Order) error {
+	items, err := s.orderRepo.GetItems(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	next := deriveOrderStatus(order.Status, items)
+	if next == order.Status {
+		return nil
+	}
+
+	if order.Status == domain.OrderNew {
+		if err := s.consumeIngredientsForOrder(ctx, order.ID); err != nil {
+			s.logger.Error("Failed to consume ingredients for order #%d: %v", order.ID, err)
+			return err
+		}
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, order.ID, next); err != nil {
+		s.logger.Error("Failed to update status: %v", err)
+		return err
+	}
+
+	s.logger.Success("✓ Order #%d status derived from items: %s → %s", order.ID, order.Status, next)
+	order.Status = next
+	return nil
+}
+
+// markItemsDone переводит все неготовые позиции заказа в done.
+func (s *OrderService) markItemsDone(ctx context.Context, orderID int) error {
+	items, err := s.orderRepo.GetItems(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.Status != domain.ItemQueued && item.Status != domain.ItemCooking {
+			continue
+		}
+		if err := s.orderRepo.UpdateItemStatus(ctx, item.ID, domain.ItemDone); err != nil {
+			s.logger.Error("Failed to mark item #%d as done: %v", item.ID, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// deriveOrderStatus выводит статус открытого заказа из статусов позиций.
+// Оплаченный, отменённый и слитый заказ свой статус уже не меняет.
+func deriveOrderStatus(current domain.OrderStatus, items []domain.OrderItem) domain.OrderStatus {
+	if !current.IsOpen() || len(items) == 0 {
+		return current
+	}
+
+	allDone := true
+	anyStarted := false
+	for _, item := range items {
+		if item.Status == domain.ItemQueued || item.Status == domain.ItemCooking {
+			allDone = false
+		}
+		if item.Status != domain.ItemQueued {
+			anyStarted = true
+		}
+	}
+
+	switch {
+	case allDone:
+		return domain.OrderReady
+	case anyStarted || current == domain.OrderReady:
+		return domain.OrderInProgress
+	default:
+		return current
+	}
+}
+
+func isValidItemTransition(from, to domain.OrderItemStatus) bool {
+	for _, allowed := range validItemTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+)
+
+type MenuService struct {
+	menuRepo ports.MenuRepository
+	dishRepo ports.DishRepository
+}
+
+func NewMenuService(menuRepo ports.MenuRepository, dishRepo ports.DishRepository) *MenuService {
+	return &MenuService{
+		menuRepo: menuRepo,
+		dishRepo: dishRepo,
+	}
+}
+
+func (s *MenuService) GetAll(ctx context.Context) ([]domain.Menu, error) {
+	return s.menuRepo.GetAll(ctx, false)
+}
+
+// GetCurrent возвращает меню, открытые сейчас, только с доступными блюдами:
+// снятые вручную (86) и закончившиеся на складе скрыты
+func (s *MenuService) GetCurrent(ctx context.Context) ([]domain.Menu, error) {
+	menus, err := s.menuRepo.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	portions, err := s.dishRepo.GetPortionsLeft(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var current []domain.Menu
+	for _, m := range menus {
+		if !m.IsOpenAt(now) {
+			continue
+		}
+		dishes := m.Dishes[:0]
+		for _, md := range m.Dishes {
+			md.Dish.SetPortionsLeft(portions)
+			if md.Dish.Available {
+				dishes = append(dishes, md)
+			}
+		}
+		m.Dishes = dishes
+		current = append(current, m)
+	}
+	return current, nil
+}
+
+func (s *MenuService) GetByID(ctx context.Context, id int) (*domain.Menu, error) {
+	menu, err := s.menuRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if menu == nil {
+		return nil, domain.ErrMenuNotFound
+	}
+	return menu, nil
+}
+
+func (s *MenuService) Create(ctx context.Context, menu *domain.Menu) error {
+	if !menu.IsValid() {
+		return domain.ErrInvalidMenu
+	}
+	menu.IsActive = true
+	return s.menuRepo.Create(ctx, menu)
+}
+
+func (s *MenuService) Update(ctx context.Context, menu *domain.Menu) error {
+	if !menu.IsValid() {
+		return domain.ErrInvalidMenu
+	}
+	if _, err := s.GetByID(ctx, menu.ID); err != nil {
+		return err
+	}
+	return s.menuRepo.Update(ctx, menu)
+}
+
+func (s *MenuService) Delete(ctx context.Context, id int) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.menuRepo.Delete(ctx, id)
+}
+
+// SetDish добавляет блюдо в меню или меняет его цену в этом меню
+func (s *MenuService) SetDish(ctx context.Context, menuDish *domain.MenuDish) error {
+	if menuDish.Price != nil && *menuDish.Price < 0 {
+		return domain.ErrInvalidMenu
+	}
+	if _, err := s.GetByID(ctx, menuDish.MenuID); err != nil {
+		return err
+	}
+
+	dish, err := s.dishRepo.GetByID(ctx, menuDish.DishID)
+	if err != nil {
+		return err
+	}
+	if dish == nil {
+		return domain.ErrDishNotFound
+	}
+
+	menuDish.Dish = dish
+	return s.menuRepo.SetDish(ctx, menuDish)
+}
+
+func (s *MenuService) RemoveDish(ctx context.Context, menuID, dishID int) error {
+	if _, err := s.GetByID(ctx, menuID); err != nil {
+		return err
+	}
+	return s.menuRepo.RemoveDish(ctx, menuID, dishID)
+}
+
+// orderableDish проверяет, можно ли заказать блюдо сейчас, и подставляет
+// в dish.Price цену из открытого меню.
+func orderableDish(menus domain.MenuSchedule, dish *domain.Dish, at time.Time) error {
+	if dish.SoldOut {
+		return domain.ErrDishSoldOut
+	}
+	price, ok := menus.Orderable(dish, at)
+	if !ok {
+		return domain.ErrDishNotAvailable
+	}
+	dish.Price = price
+	return nil
+}
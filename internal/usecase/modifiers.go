@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"math"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+// priceItem фиксирует в позиции блюдо, ставку НДС и цену за порцию вместе
+// с надбавками выбранных модификаторов. В item.Modifiers на входе достаточно
+// OptionID; названия и надбавки копируются из меню.
+func (s *OrderService) priceItem(ctx context.Context, item *domain.OrderItem, dish *domain.Dish) error {
+	modifiers, delta, err := s.resolveModifiers(ctx, dish.ID, item.Modifiers)
+	if err != nil {
+		s.logger.Error("Invalid modifiers for dish '%s': %v", dish.Name, err)
+		return err
+	}
+
+	item.Modifiers = modifiers
+	item.Price = math.Max(roundMoney(dish.Price+delta), 0)
+	item.VatRate = dish.EffectiveVatRate()
+	item.Dish = dish
+	return nil
+}
+
+// resolveModifiers проверяет выбор по правилам групп блюда (обязательность,
+// минимум и максимум) и возвращает выбранные варианты в порядке меню и сумму надбавок.
+func (s *OrderService) resolveModifiers(ctx context.Context, dishID int, selected []domain.OrderItemModifier) ([]domain.OrderItemModifier, float64, error) {
+	groups, err := s.modifierRepo.GetGroupsByDish(ctx, dishID, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	chosen := make(map[int]bool, len(selected))
+	for _, m := range selected {
+		if m.OptionID == nil || chosen[*m.OptionID] {
+			return nil, 0, domain.ErrInvalidModifierSelection
+		}
+		chosen[*m.OptionID] = true
+	}
+
+	var modifiers []domain.OrderItemModifier
+	var delta float64
+	for _, g := range groups {
+		count := 0
+		for _, o := range g.Options {
+			if !chosen[o.ID] {
+				continue
+			}
+			optionID := o.ID
+			modifiers = append(modifiers, domain.OrderItemModifier{
+				OptionID:   &optionID,
+				GroupName:  g.Name,
+				Name:       o.Name,
+				PriceDelta: o.PriceDelta,
+			})
+			delta += o.PriceDelta
+			delete(chosen, o.ID)
+			count++
+		}
+
+		if count < g.MinSelect || (g.MaxSelect > 0 && count > g.MaxSelect) {
+			return nil, 0, domain.ErrInvalidModifierSelection
+		}
+	}
+
+	// Остались варианты чужих блюд или снятые с продажи
+	if len(chosen) > 0 {
+		return nil, 0, domain.ErrInvalidModifierSelection
+	}
+	return modifiers, delta, nil
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+// AddCombo добавляет в открытый заказ комбо одной строкой счёта.
+// Кухня, склад и аналитика работают с блюдами набора как с обычными позициями.
+func (s *OrderService) AddCombo(ctx context.Context, orderID int, line *domain.OrderCombo) error {
+	s.logger.Order("Adding combo (x%d) to order #%d", line.Qty, orderID)
+
+	var order *domain.Order
+	reopened := false
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getUnpaidOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		menus, err := s.menuSchedule(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.explodeCombo(ctx, line, menus); err != nil {
+			return err
+		}
+		if err := s.adjustStockForItems(ctx, order, line.Items); err != nil {
+			return err
+		}
+
+		line.OrderID = orderID
+		if err := s.addComboLine(ctx, line); err != nil {
+			return err
+		}
+
+		if order.Status == domain.OrderReady {
+			order.Status = domain.OrderInProgress
+			reopened = true
+		}
+
+		return s.recalculateTotal(ctx, order)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyKitchen(order, fmt.Sprintf("added %s x%d", line.Name, line.Qty))
+	if reopened {
+		s.publishStatusEvent(order)
+	}
+
+	s.logger.Success("✓ Combo '%s' added to order #%d (Total: %.2f ₸)", line.Name, orderID, order.Total)
+	return nil
+}
+
+// RemoveCombo убирает строку комбо вместе со всеми блюдами набора.
+func (s *OrderService) RemoveCombo(ctx context.Context, orderID, lineID int) error {
+	s.logger.Order("Removing combo line #%d from order #%d", lineID, orderID)
+
+	var order *domain.Order
+	var removed []domain.OrderItem
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getUnpaidOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		items, err := s.orderRepo.GetItems(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.ComboLineID != nil && *item.ComboLineID == lineID {
+				delta := item
+				delta.Qty = -item.Qty
+				removed = append(removed, delta)
+			}
+		}
+		if len(removed) == 0 {
+			return domain.ErrComboLineNotFound
+		}
+		if len(removed) == len(items) {
+			return domain.ErrLastOrderItem
+		}
+
+		if err := s.adjustStockForItems(ctx, order, removed); err != nil {
+			return err
+		}
+		if err := s.orderRepo.DeleteCombo(ctx, lineID); err != nil {
+			s.logger.Error("Failed to delete combo line #%d: %v", lineID, err)
+			return err
+		}
+
+		return s.recalculateTotal(ctx, order)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyKitchen(order, fmt.Sprintf("removed combo #%d (%d dishes)", lineID, len(removed)))
+
+	s.logger.Success("✓ Combo line #%d removed from order #%d (Total: %.2f ₸)", lineID, orderID, order.Total)
+	return nil
+}
+
+// explodeCombo проверяет выбор гостя по слотам комбо и раскладывает набор
+// на позиции-блюда. Цена набора делится между блюдами пропорционально их цене
+// в меню; доплата за выбор и модификаторы остаются на своём блюде.
+// Блюда набора должны быть доступны по открытым сейчас меню.
+// Итоговая цена одного набора записывается в line.Price.
+func (s *OrderService) explodeCombo(ctx context.Context, line *domain.OrderCombo, menus domain.MenuSchedule) error {
+	if line.ComboID == nil || line.Qty <= 0 {
+		return domain.ErrInvalidComboChoice
+	}
+
+	combo, err := s.comboRepo.GetByID(ctx, *line.ComboID)
+	if err != nil {
+		s.logger.Error("Failed to get combo #%d: %v", *line.ComboID, err)
+		return err
+	}
+	if combo == nil || !combo.IsActive {
+		return domain.ErrComboNotFound
+	}
+
+	selected := make(map[int]domain.ComboSelection, len(line.Choices))
+	for _, sel := range line.Choices {
+		if _, dup := selected[sel.SlotID]; dup {
+			return domain.ErrInvalidComboChoice
+		}
+		selected[sel.SlotID] = sel
+	}
+	if len(selected) != len(combo.Slots) {
+		return domain.ErrInvalidComboChoice
+	}
+
+	items := make([]domain.OrderItem, 0, len(combo.Slots))
+	weights := make([]float64, 0, len(combo.Slots))
+	extras := make([]float64, 0, len(combo.Slots))
+	for _, slot := range combo.Slots {
+		sel, ok := selected[slot.ID]
+		if !ok {
+			return domain.ErrInvalidComboChoice
+		}
+		choice, ok := slot.Choice(sel.DishID)
+		if !ok {
+			return domain.ErrInvalidComboChoice
+		}
+
+		dish, err := s.dishRepo.GetByID(ctx, sel.DishID)
+		if err != nil {
+			return err
+		}
+		if dish == nil {
+			return domain.ErrInvalidComboChoice
+		}
+		if err := orderableDish(menus, dish, time.Now()); err != nil {
+			return err
+		}
+
+		modifiers, delta, err := s.resolveModifiers(ctx, dish.ID, sel.Modifiers)
+		if err != nil {
+			s.logger.Error("Invalid modifiers for dish '%s' in combo '%s': %v", dish.Name, combo.Name, err)
+			return err
+		}
+
+		items = append(items, domain.OrderItem{
+			DishID:    dish.ID,
+			Qty:       line.Qty,
+			Notes:     sel.Notes,
+			Seat:      line.Seat,
+			Modifiers: modifiers,
+			VatRate:   dish.EffectiveVatRate(),
+			Dish:      dish,
+		})
+		weights = append(weights, dish.Price)
+		extras = append(extras, choice.PriceDelta+delta)
+	}
+
+	line.Name = combo.Name
+	line.Price = 0
+	for i, share := range splitBundlePrice(combo.Price, weights) {
+		items[i].Price = math.Max(roundMoney(share+extras[i]), 0)
+		line.Price += items[i].Price
+	}
+	line.Price = roundMoney(line.Price)
+	line.Items = items
+	return nil
+}
+
+// addComboLine сохраняет строку комбо и её блюда.
+func (s *OrderService) addComboLine(ctx context.Context, line *domain.OrderCombo) error {
+	if err := s.orderRepo.AddCombo(ctx, line); err != nil {
+		s.logger.Error("Failed to add combo to order #%d: %v", line.OrderID, err)
+		return err
+	}
+
+	for i := range line.Items {
+		item := &line.Items[i]
+		item.OrderID = line.OrderID
+		item.ComboLineID = &line.ID
+		if err := s.orderRepo.AddItem(ctx, item); err != nil {
+			s.logger.Error("Failed to add combo item to order #%d: %v", line.OrderID, err)
+			return err
+		}
+	}
+
+	s.logger.Info("Added combo: %s (x%d) - %.2f ₸", line.Name, line.Qty, line.Price)
+	return nil
+}
+
+// splitBundlePrice делит цену набора по весам (цены блюд в меню) с округлением
+// до копеек; остаток от округления достаётся самому дорогому блюду,
+// чтобы доли в сумме давали ровно цену набора.
+func splitBundlePrice(price float64, weights []float64) []float64 {
+	shares := make([]float64, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+
+	total := 0.0
+	heaviest := 0
+	for i, w := range weights {
+		total += w
+		if w > weights[heaviest] {
+			heaviest = i
+		}
+	}
+
+	allocated := 0.0
+	for i, w := range weights {
+		if i == heaviest {
+			continue
+		}
+		if total > 0 {
+			shares[i] = roundMoney(price * w / total)
+		} else {
+			shares[i] = roundMoney(price / float64(len(weights)))
+		}
+		allocated += shares[i]
+	}
+	shares[heaviest] = roundMoney(price - allocated)
+	return shares
+}
@@ -3,6 +3,9 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"time"
 
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
@@ -13,128 +16,201 @@ import (
 type OrderService struct {
 	orderRepo      ports.OrderRepository
 	dishRepo       ports.DishRepository
+	modifierRepo   ports.ModifierRepository
+	comboRepo      ports.ComboRepository
+	menuRepo       ports.MenuRepository
 	ingredientRepo ports.IngredientRepository
+	prepRepo       ports.PrepRepository
 	tableRepo      ports.TableRepository
+	voidRepo       ports.VoidRepository
+	wasteRepo      ports.WasteRepository
+	paymentRepo    ports.PaymentRepository
+	promotionRepo  ports.PromotionRepository
+	txManager      ports.TxManager
+	events         ports.EventPublisher
+	serviceCharge  domain.ServiceChargePolicy
+	sections       sectionGuard
 	logger         *logger.Logger
 }
 
 func NewOrderService(
 	orderRepo ports.OrderRepository,
 	dishRepo ports.DishRepository,
+	modifierRepo ports.ModifierRepository,
+	comboRepo ports.ComboRepository,
+	menuRepo ports.MenuRepository,
 	ingredientRepo ports.IngredientRepository,
+	prepRepo ports.PrepRepository,
 	tableRepo ports.TableRepository,
+	voidRepo ports.VoidRepository,
+	wasteRepo ports.WasteRepository,
+	paymentRepo ports.PaymentRepository,
+	promotionRepo ports.PromotionRepository,
+	sectionRepo ports.SectionRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+	serviceCharge domain.ServiceChargePolicy,
 ) *OrderService {
 	return &OrderService{
 		orderRepo:      orderRepo,
 		dishRepo:       dishRepo,
+		modifierRepo:   modifierRepo,
+		comboRepo:      comboRepo,
+		menuRepo:       menuRepo,
 		ingredientRepo: ingredientRepo,
+		prepRepo:       prepRepo,
 		tableRepo:      tableRepo,
+		voidRepo:       voidRepo,
+		wasteRepo:      wasteRepo,
+		paymentRepo:    paymentRepo,
+		promotionRepo:  promotionRepo,
+		txManager:      txManager,
+		events:         events,
+		serviceCharge:  serviceCharge,
+		sections:       sectionGuard{sectionRepo: sectionRepo},
 		logger:         logger.New("OrderService"),
 	}
 }
 
-func (s *OrderService) Create(ctx context.Context, order *domain.Order, items []domain.OrderItem) error {
+func (s *OrderService) Create(ctx context.Context, order *domain.Order, items []domain.OrderItem, combos []domain.OrderCombo) error {
 	s.logger.Order("Creating new order for table #%d", order.TableNumber)
 
-	// Проверяем существование стола
-	table, err := s.tableRepo.GetByID(ctx, order.TableNumber)
-	if err != nil {
-		s.logger.Error("Failed to get table #%d: %v", order.TableNumber, err)
-		return err
-	}
-	if table == nil {
-		s.logger.Error("Table #%d not found", order.TableNumber)
-		return domain.ErrTableNotFound
-	}
-
-	s.logger.Info("Table #%d found: %s", table.ID, table.Name)
-
-	// Проверяем наличие ингредиентов для всех блюд
-	for _, item := range items {
-		// Проверяем существование блюда
-		dish, err := s.dishRepo.GetByID(ctx, item.DishID)
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Проверяем существование стола
+		table, err := s.tableRepo.GetByID(ctx, order.TableNumber)
 		if err != nil {
-			s.logger.Error("Failed to get dish #%d: %v", item.DishID, err)
+			s.logger.Error("Failed to get table #%d: %v", order.TableNumber, err)
 			return err
 		}
-		if dish == nil {
-			s.logger.Error("Dish #%d not found", item.DishID)
-			return fmt.Errorf("dish with id %d not found", item.DishID)
+		if table == nil {
+			s.logger.Error("Table #%d not found", order.TableNumber)
+			return domain.ErrTableNotFound
 		}
 
-		s.logger.Info("Adding dish '%s' (x%d) to order", dish.Name, item.Qty)
+		s.logger.Info("Table #%d found: %s", table.ID, table.Name)
 
-		// Получаем ингредиенты блюда
-		ingredients, err := s.dishRepo.GetIngredients(ctx, item.DishID)
+		if err := s.sections.checkTable(ctx, table.ID); err != nil {
+			s.logger.Error("Table #%d is not in the waiter's section", table.ID)
+			return err
+		}
+
+		// Проверяем блюда по открытым сейчас меню, фиксируем цены и считаем общую сумму заказа
+		menus, err := s.menuSchedule(ctx)
 		if err != nil {
-			s.logger.Error("Failed to get ingredients for dish #%d: %v", item.DishID, err)
 			return err
 		}
+		now := time.Now()
 
-		// Проверяем достаточность запасов
-		for _, ing := range ingredients {
-			needed := ing.QtyPerDish * float64(item.Qty)
-			ingredient, err := s.ingredientRepo.GetByID(ctx, ing.IngredientID)
+		var total float64
+		for i := range items {
+			dish, err := s.dishRepo.GetByID(ctx, items[i].DishID)
 			if err != nil {
-				s.logger.Error("Failed to get ingredient #%d: %v", ing.IngredientID, err)
+				s.logger.Error("Failed to get dish #%d: %v", items[i].DishID, err)
+				return err
+			}
+			if dish == nil {
+				s.logger.Error("Dish #%d not found", items[i].DishID)
+				return fmt.Errorf("dish with id %d not found", items[i].DishID)
+			}
+			if err := orderableDish(menus, dish, now); err != nil {
+				s.logger.Error("Dish '%s' cannot be ordered now", dish.Name)
 				return err
 			}
 
-			if ingredient.Qty < needed {
-				s.logger.Error("Insufficient stock for ingredient '%s': needed %.2f, available %.2f",
-					ingredient.Name, needed, ingredient.Qty)
-				return domain.ErrInsufficientStock
+			s.logger.Info("Adding dish '%s' (x%d) to order", dish.Name, items[i].Qty)
+
+			if err := s.priceItem(ctx, &items[i], dish); err != nil {
+				return err
 			}
+			total += items[i].Price * float64(items[i].Qty)
+		}
 
-			s.logger.Debug("Ingredient '%s': needed %.2f%s, available %.2f%s",
-				ingredient.Name, needed, ingredient.Unit, ingredient.Qty, ingredient.Unit)
+		// Комбо раскладываем на блюда набора — резерв идёт по ним
+		all := append([]domain.OrderItem(nil), items...)
+		for i := range combos {
+			if err := s.explodeCombo(ctx, &combos[i], menus); err != nil {
+				return err
+			}
+			s.logger.Info("Adding combo '%s' (x%d) to order", combos[i].Name, combos[i].Qty)
+			total += combos[i].Price * float64(combos[i].Qty)
+			all = append(all, combos[i].Items...)
 		}
-	}
 
-	// Подсчитываем общую сумму заказа
-	var total float64
-	for _, item := range items {
-		dish, err := s.dishRepo.GetByID(ctx, item.DishID)
+		needs, err := s.ingredientNeeds(ctx, all)
 		if err != nil {
 			return err
 		}
-		total += dish.Price * float64(item.Qty)
-	}
 
-	order.Status = domain.OrderNew
-	order.Total = total
+		// Промокод проверяем сразу, чтобы официант узнал об ошибке при создании заказа
+		if order.PromoCode != nil {
+			promo, err := s.getPromotionByCode(ctx, *order.PromoCode)
+			if err != nil {
+				return err
+			}
+			order.PromoCode = promo.Code
+		}
 
-	s.logger.Order("Order total: %.2f ₸", total)
-	// Создаем заказ
-	if err := s.orderRepo.Create(ctx, order); err != nil {
-		s.logger.Error("Failed to create order: %v", err)
-		return err
-	}
+		order.Status = domain.OrderNew
+		order.Subtotal = total
+		order.Total = total
+		order.ServiceChargeRate = s.serviceCharge.RateFor(order.Guests)
+
+		s.logger.Order("Order subtotal: %.2f ₸", total)
+		// Создаем заказ
+		if err := s.orderRepo.Create(ctx, order); err != nil {
+			s.logger.Error("Failed to create order: %v", err)
+			return err
+		}
 
-	s.logger.Success("✓ Order #%d created successfully", order.ID)
+		// Резервируем ингредиенты под заказ (строки блокируются до конца транзакции)
+		if err := s.reserveIngredients(ctx, order.ID, needs); err != nil {
+			return err
+		}
 
-	// Добавляем позиции заказа
-	for _, item := range items {
-		item.OrderID = order.ID
-		dish, _ := s.dishRepo.GetByID(ctx, item.DishID)
-		item.Price = dish.Price
+		// Добавляем позиции заказа
+		for i := range items {
+			items[i].OrderID = order.ID
+			if err := s.orderRepo.AddItem(ctx, &items[i]); err != nil {
+				s.logger.Error("Failed to add item to order: %v", err)
+				return err
+			}
 
-		if err := s.orderRepo.AddItem(ctx, &item); err != nil {
-			s.logger.Error("Failed to add item to order: %v", err)
+			s.logger.Info("Added item: %s (x%d) - %.2f ₸", items[i].Dish.Name, items[i].Qty, items[i].Price)
+		}
+		for i := range combos {
+			combos[i].OrderID = order.ID
+			if err := s.addComboLine(ctx, &combos[i]); err != nil {
+				return err
+			}
+		}
+
+		// Применяем акции и промокод к сохранённым позициям
+		if err := s.recalculateTotal(ctx, order); err != nil {
 			return err
 		}
 
-		s.logger.Info("Added item: %s (x%d) - %.2f ₸", dish.Name, item.Qty, item.Price)
-	}
+		// Обновляем статус стола на "занят"
+		if err := s.tableRepo.UpdateStatus(ctx, order.TableNumber, domain.TableBusy); err != nil {
+			s.logger.Error("Failed to update table status: %v", err)
+			return err
+		}
 
-	// Обновляем статус стола на "занят"
-	if err := s.tableRepo.UpdateStatus(ctx, order.TableNumber, domain.TableBusy); err != nil {
-		s.logger.Error("Failed to update table status: %v", err)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Order for table #%d rolled back: %v", order.TableNumber, err)
 		return err
 	}
 
 	s.logger.Success("✓ Table #%d marked as busy", order.TableNumber)
-	s.logger.Order("Order #%d created successfully with %d items", order.ID, len(items))
+	s.logger.Order("Order #%d created successfully with %d items and %d combos", order.ID, len(items), len(combos))
+
+	s.publishOrderEvent(domain.EventOrderCreated, order)
+	s.events.Publish(domain.Event{
+		Type:    domain.EventTableStatusChanged,
+		TableID: order.TableNumber,
+		Status:  string(domain.TableBusy),
+	})
 
 	return nil
 }
@@ -160,6 +236,20 @@ func (s *OrderService) GetByID(ctx context.Context, id int) (*domain.Order, erro
 	}
 	order.Items = items
 
+	combos, err := s.orderRepo.GetCombos(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get combos for order #%d: %v", id, err)
+		return nil, err
+	}
+	order.Combos = combos
+
+	discounts, err := s.promotionRepo.GetDiscounts(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get discounts for order #%d: %v", id, err)
+		return nil, err
+	}
+	order.Discounts = discounts
+
 	s.logger.Success("✓ Order #%d fetched successfully (%d items)", id, len(items))
 	return order, nil
 }
@@ -191,6 +281,181 @@ func (s *OrderService) GetAll(ctx context.Context, status *domain.OrderStatus) (
 func (s *OrderService) UpdateStatus(ctx context.Context, id int, newStatus domain.OrderStatus) error {
 	s.logger.Order("Updating order #%d status to: %s", id, newStatus)
 
+	// Валидация переходов статусов. В paid заказ попадает только через оплату
+	// (CloseOrder или PaymentService.Pay), иначе деньги не попадут в payments.
+	validTransitions := map[domain.OrderStatus][]domain.OrderStatus{
+		domain.OrderNew:        {domain.OrderInProgress},
+		domain.OrderInProgress: {domain.OrderReady},
+	}
+
+	var order *domain.Order
+	var oldStatus domain.OrderStatus
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Блокируем заказ и проверяем переход по заблокированной строке:
+		// иначе два параллельных new -> in_progress спишут склад дважды,
+		// а закрытый тем временем заказ снова откроется
+		if err := s.orderRepo.Lock(ctx, id); err != nil {
+			return err
+		}
+		var err error
+		order, err = s.orderRepo.GetByID(ctx, id)
+		if err != nil {
+			s.logger.Error("Failed to get order #%d: %v", id, err)
+			return err
+		}
+		if order == nil {
+			s.logger.Error("Order #%d not found", id)
+			return domain.ErrOrderNotFound
+		}
+		if err := s.sections.checkOrder(ctx, order); err != nil {
+			return err
+		}
+		oldStatus = order.Status
+
+		valid := false
+		for _, allowedStatus := range validTransitions[order.Status] {
+			if allowedStatus == newStatus {
+				valid = true
+				break
+			}
+		}
+
+		// Разрешаем не менять статус, если он уже установлен
+		if !valid && newStatus != order.Status {
+			s.logger.Error("Invalid status transition from %s to %s", order.Status, newStatus)
+			return domain.ErrInvalidStatusChange
+		}
+
+		// 🔥 ВАЖНО: если переходим new -> in_progress, списываем ингредиенты
+		if order.Status == domain.OrderNew && newStatus == domain.OrderInProgress {
+			if err := s.consumeIngredientsForOrder(ctx, order.ID); err != nil {
+				s.logger.Error("Failed to consume ingredients for order #%d: %v", id, err)
+				return err
+			}
+		}
+
+		// Заказ целиком готов — все его позиции тоже готовы
+		if newStatus == domain.OrderReady {
+			if err := s.markItemsDone(ctx, order.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.orderRepo.UpdateStatus(ctx, id, newStatus); err != nil {
+			s.logger.Error("Failed to update status: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Success("✓ Order #%d status updated: %s → %s", id, oldStatus, newStatus)
+
+	if newStatus != oldStatus {
+		order.Status = newStatus
+		s.publishStatusEvent(order)
+	}
+	return nil
+}
+
+// CloseOrder оплачивает весь счёт одним платежом: записывает его в payments
+// (способ оплаты, чаевые, сервисный сбор), закрывает заказ и освобождает стол.
+func (s *OrderService) CloseOrder(ctx context.Context, payment *domain.Payment) error {
+	id := payment.OrderID
+	s.logger.Order("Closing order #%d (%s)", id, payment.Method)
+
+	if !isValidPayment(payment) {
+		return domain.ErrInvalidPayment
+	}
+
+	// 1-4. Записываем оплату, помечаем заказ оплаченным и освобождаем стол одной транзакцией.
+	// Если счёт уже оплачивается частями, закрыть его можно только через оплату остатка.
+	var order *domain.Order
+	var freed bool
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.Lock(ctx, id); err != nil {
+			return err
+		}
+
+		// Берём заказ из репозитория (без items)
+		var err error
+		order, err = s.orderRepo.GetByID(ctx, id)
+		if err != nil {
+			s.logger.Error("Failed to get order #%d: %v", id, err)
+			return err
+		}
+		if order == nil {
+			s.logger.Error("Order #%d not found", id)
+			return domain.ErrOrderNotFound
+		}
+		if err := s.sections.checkOrder(ctx, order); err != nil {
+			return err
+		}
+
+		// Проверяем, что заказ в статусе ready
+		if order.Status != domain.OrderReady {
+			s.logger.Error("Order #%d must be in 'ready' status to close, current: %s", id, order.Status)
+			return domain.ErrOrderNotReady
+		}
+
+		paid, err := s.paymentRepo.GetPaidTotal(ctx, id)
+		if err != nil {
+			return err
+		}
+		if paid > 0 {
+			s.logger.Error("Order #%d has partial payments (%.2f of %.2f ₸)", id, paid, order.Total)
+			return domain.ErrOutstandingBalance
+		}
+
+		payment.Amount = order.Total
+		payment.ServiceCharge = order.ServiceCharge
+		if payment.Amount > 0 {
+			if err := fillTender(payment); err != nil {
+				return err
+			}
+			if err := s.paymentRepo.Create(ctx, payment); err != nil {
+				s.logger.Error("Failed to save payment for order #%d: %v", id, err)
+				return err
+			}
+		} else if !isZeroPayment(payment) {
+			// Счёт полностью скомпенсирован: платить нечего, чаевые провести не к чему
+			return domain.ErrInvalidPayment
+		}
+
+		if err := s.orderRepo.UpdateStatus(ctx, id, domain.OrderPaid); err != nil {
+			s.logger.Error("Failed to update order status: %v", err)
+			return err
+		}
+
+		// Стол освобождается, только если за ним не осталось других открытых заказов
+		freed, err = freeTableIfEmpty(ctx, s.orderRepo, s.tableRepo, s.logger, order.TableNumber)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.logger.Success("✓ Order #%d marked as paid: %.2f ₸ by %s, tip %.2f ₸, change %.2f ₸",
+		id, payment.Amount, payment.Method, payment.Tip, payment.Change)
+
+	order.Status = domain.OrderPaid
+	s.publishOrderEvent(domain.EventOrderClosed, order)
+	if freed {
+		s.logger.Success("✓ Table #%d freed", order.TableNumber)
+		s.publishTableStatus(order.TableNumber, domain.TableFree)
+	}
+
+	// 5. Генерация PDF-чека в папку "receipts"
+	generateOrderReceipt(ctx, s.orderRepo, s.logger, order)
+
+	s.logger.Order("Order #%d closed successfully (Total: %.2f ₸)", id, order.Total)
+	return nil
+}
+
+func (s *OrderService) Delete(ctx context.Context, id int) error {
+	s.logger.Warning("Deleting order #%d", id)
+
 	order, err := s.orderRepo.GetByID(ctx, id)
 	if err != nil {
 		s.logger.Error("Failed to get order #%d: %v", id, err)
@@ -201,136 +466,487 @@ func (s *OrderService) UpdateStatus(ctx context.Context, id int, newStatus domai
 		return domain.ErrOrderNotFound
 	}
 
-	// Валидация переходов статусов
-	validTransitions := map[domain.OrderStatus][]domain.OrderStatus{
-		domain.OrderNew:        {domain.OrderInProgress},
-		domain.OrderInProgress: {domain.OrderReady},
-		domain.OrderReady:      {domain.OrderPaid},
+	// Можно добавить проверку: разрешить удалять только новые заказы
+	if order.Status != domain.OrderNew {
+		s.logger.Error("Cannot delete order #%d in status: %s", id, order.Status)
+		return fmt.Errorf("cannot delete order in status: %s", order.Status)
 	}
 
-	valid := false
-	for _, allowedStatus := range validTransitions[order.Status] {
-		if allowedStatus == newStatus {
-			valid = true
-			break
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Снимаем резерв ингредиентов, который держал заказ
+		if err := s.releaseReservation(ctx, id); err != nil {
+			return err
 		}
-	}
 
-	// Разрешаем не менять статус, если он уже установлен
-	if !valid && newStatus != order.Status {
-		s.logger.Error("Invalid status transition from %s to %s", order.Status, newStatus)
-		return domain.ErrInvalidStatusChange
+		return s.orderRepo.Delete(ctx, id)
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete order #%d: %v", id, err)
+		return err
 	}
 
-	// 🔥 ВАЖНО: если переходим new -> in_progress, списываем ингредиенты
-	if order.Status == domain.OrderNew && newStatus == domain.OrderInProgress {
-		if err := s.consumeIngredientsForOrder(ctx, order.ID); err != nil {
-			s.logger.Error("Failed to consume ingredients for order #%d: %v", id, err)
-			// при желании можно вернуть ErrInsufficientStock, если добавим доп. проверки
+	// резерв вернулся на склад — блюда могли снова стать доступными
+	s.events.Publish(domain.Event{Type: domain.EventStockChanged, OrderID: id})
+
+	s.logger.Success("✓ Order #%d deleted", id)
+	return nil
+}
+
+func (s *OrderService) AddItem(ctx context.Context, orderID int, item *domain.OrderItem) error {
+	s.logger.Order("Adding dish #%d (x%d) to order #%d", item.DishID, item.Qty, orderID)
+
+	var order *domain.Order
+	reopened := false
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getUnpaidOrder(ctx, orderID)
+		if err != nil {
 			return err
 		}
+
+		dish, err := s.dishRepo.GetByID(ctx, item.DishID)
+		if err != nil {
+			return err
+		}
+		if dish == nil {
+			return domain.ErrDishNotFound
+		}
+		menus, err := s.menuSchedule(ctx)
+		if err != nil {
+			return err
+		}
+		if err := orderableDish(menus, dish, time.Now()); err != nil {
+			return err
+		}
+
+		item.OrderID = orderID
+		if err := s.priceItem(ctx, item, dish); err != nil {
+			return err
+		}
+
+		if err := s.adjustStockForItems(ctx, order, []domain.OrderItem{*item}); err != nil {
+			return err
+		}
+		if err := s.orderRepo.AddItem(ctx, item); err != nil {
+			s.logger.Error("Failed to add item to order #%d: %v", orderID, err)
+			return err
+		}
+
+		// Новое блюдо к готовому заказу — заказ снова уходит на кухню
+		if order.Status == domain.OrderReady {
+			order.Status = domain.OrderInProgress
+			reopened = true
+		}
+
+		return s.recalculateTotal(ctx, order)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyKitchen(order, fmt.Sprintf("added %s x%d", item.Dish.Name, item.Qty))
+	if reopened {
+		s.publishStatusEvent(order)
 	}
 
-	if err := s.orderRepo.UpdateStatus(ctx, id, newStatus); err != nil {
-		s.logger.Error("Failed to update status: %v", err)
+	s.logger.Success("✓ Item #%d added to order #%d (Total: %.2f ₸)", item.ID, orderID, order.Total)
+	return nil
+}
+
+func (s *OrderService) UpdateItem(ctx context.Context, orderID int, item *domain.OrderItem) error {
+	s.logger.Order("Updating item #%d of order #%d (qty=%d)", item.ID, orderID, item.Qty)
+
+	var order *domain.Order
+	var existing *domain.OrderItem
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getUnpaidOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		existing, err = s.findItem(ctx, orderID, item.ID)
+		if err != nil {
+			return err
+		}
+		if existing.ComboLineID != nil {
+			return domain.ErrComboItemNotEditable
+		}
+
+		// Двигаем склад только на разницу в количестве
+		delta := *existing
+		delta.Qty = item.Qty - existing.Qty
+		if delta.Qty != 0 {
+			if err := s.adjustStockForItems(ctx, order, []domain.OrderItem{delta}); err != nil {
+				return err
+			}
+		}
+
+		// Цена фиксируется в момент добавления, блюдо и модификаторы в позиции не меняются
+		item.OrderID = orderID
+		item.DishID = existing.DishID
+		item.Price = existing.Price
+		item.Dish = existing.Dish
+		item.Modifiers = existing.Modifiers
+		if err := s.orderRepo.UpdateItem(ctx, item); err != nil {
+			s.logger.Error("Failed to update item #%d: %v", item.ID, err)
+			return err
+		}
+
+		return s.recalculateTotal(ctx, order)
+	})
+	if err != nil {
 		return err
 	}
 
-	s.logger.Success("✓ Order #%d status updated: %s → %s", id, order.Status, newStatus)
+	s.notifyKitchen(order, fmt.Sprintf("%s: x%d → x%d", existing.Dish.Name, existing.Qty, item.Qty))
+
+	s.logger.Success("✓ Item #%d of order #%d updated (Total: %.2f ₸)", item.ID, orderID, order.Total)
 	return nil
 }
-func (s *OrderService) CloseOrder(ctx context.Context, id int) error {
-	s.logger.Order("Closing order #%d", id)
 
-	// 1. Берём заказ из репозитория (без items)
-	order, err := s.orderRepo.GetByID(ctx, id)
+func (s *OrderService) RemoveItem(ctx context.Context, orderID, itemID int) error {
+	s.logger.Order("Removing item #%d from order #%d", itemID, orderID)
+
+	var order *domain.Order
+	var existing *domain.OrderItem
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getUnpaidOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		items, err := s.orderRepo.GetItems(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if len(items) == 1 && items[0].ID == itemID {
+			return domain.ErrLastOrderItem
+		}
+
+		existing, err = s.findItem(ctx, orderID, itemID)
+		if err != nil {
+			return err
+		}
+		if existing.ComboLineID != nil {
+			return domain.ErrComboItemNotEditable
+		}
+
+		delta := *existing
+		delta.Qty = -existing.Qty
+		if err := s.adjustStockForItems(ctx, order, []domain.OrderItem{delta}); err != nil {
+			return err
+		}
+
+		if err := s.orderRepo.DeleteItem(ctx, itemID); err != nil {
+			s.logger.Error("Failed to delete item #%d: %v", itemID, err)
+			return err
+		}
+
+		return s.recalculateTotal(ctx, order)
+	})
 	if err != nil {
-		s.logger.Error("Failed to get order #%d: %v", id, err)
 		return err
 	}
+
+	s.notifyKitchen(order, fmt.Sprintf("removed %s x%d", existing.Dish.Name, existing.Qty))
+
+	s.logger.Success("✓ Item #%d removed from order #%d (Total: %.2f ₸)", itemID, orderID, order.Total)
+	return nil
+}
+
+// menuSchedule загружает все меню с их блюдами для проверки доступности.
+// Выключенные меню тоже нужны: блюдо только из выключенного меню не продаётся,
+// а не становится «блюдом вне меню» с базовой ценой.
+func (s *OrderService) menuSchedule(ctx context.Context) (domain.MenuSchedule, error) {
+	menus, err := s.menuRepo.GetAll(ctx, false)
+	if err != nil {
+		s.logger.Error("Failed to get menus: %v", err)
+		return nil, err
+	}
+	return menus, nil
+}
+
+// getEditableOrder возвращает заказ, если в нём ещё можно менять позиции.
+func (s *OrderService) getEditableOrder(ctx context.Context, orderID int) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		s.logger.Error("Failed to get order #%d: %v", orderID, err)
+		return nil, err
+	}
 	if order == nil {
-		s.logger.Error("Order #%d not found", id)
-		return domain.ErrOrderNotFound
+		return nil, domain.ErrOrderNotFound
 	}
 
-	// 2. Проверяем, что заказ в статусе ready
-	if order.Status != domain.OrderReady {
-		s.logger.Error("Order #%d must be in 'ready' status to close, current: %s", id, order.Status)
-		return fmt.Errorf("order must be in ready status to close, current status: %s", order.Status)
+	if !order.Status.IsOpen() {
+		s.logger.Error("Order #%d in status %s cannot be edited", orderID, order.Status)
+		return nil, domain.ErrOrderNotEditable
+	}
+	if err := s.sections.checkOrder(ctx, order); err != nil {
+		return nil, err
 	}
+	return order, nil
+}
 
-	// 3. Обновляем статус заказа на "оплачен"
-	if err := s.orderRepo.UpdateStatus(ctx, id, domain.OrderPaid); err != nil {
-		s.logger.Error("Failed to update order status: %v", err)
-		return err
+// getUnpaidOrder возвращает открытый заказ, по которому ещё не было оплат:
+// после первой оплаты ни позиции, ни скидки менять нельзя, иначе сумма заказа
+// может опуститься ниже уже оплаченной и счёт больше не закроется.
+func (s *OrderService) getUnpaidOrder(ctx context.Context, orderID int) (*domain.Order, error) {
+	if err := s.orderRepo.Lock(ctx, orderID); err != nil {
+		return nil, err
+	}
+	order, err := s.getEditableOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
 	}
-	s.logger.Success("✓ Order #%d marked as paid", id)
 
-	// 4. Освобождаем стол
-	if err := s.tableRepo.UpdateStatus(ctx, order.TableNumber, domain.TableFree); err != nil {
-		s.logger.Error("Failed to free table #%d: %v", order.TableNumber, err)
-		return err
+	paid, err := s.paymentRepo.GetPaidTotal(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if paid > 0 {
+		return nil, domain.ErrBillHasPayments
 	}
-	s.logger.Success("✓ Table #%d freed", order.TableNumber)
+	return order, nil
+}
 
-	// 5. Подтягиваем полный заказ с позициями для чека
-	fullOrder, err := s.GetByID(ctx, id)
+func (s *OrderService) findItem(ctx context.Context, orderID, itemID int) (*domain.OrderItem, error) {
+	items, err := s.orderRepo.GetItems(ctx, orderID)
 	if err != nil {
-		// сам заказ уже закрыт, поэтому только логируем
-		s.logger.Error("Order #%d closed, but failed to reload for receipt: %v", id, err)
-		return nil
+		return nil, err
+	}
+	for i := range items {
+		if items[i].ID == itemID {
+			return &items[i], nil
+		}
 	}
+	return nil, domain.ErrOrderItemNotFound
+}
 
-	// 6. Имя официанта для чека
-	waiterName := "Unknown"
-	if fullOrder != nil && fullOrder.Waiter != nil && fullOrder.Waiter.Username != "" {
-		waiterName = fullOrder.Waiter.Username
+// adjustStockForItems применяет изменение позиций к складу.
+// Пока заказ new — двигаем только резерв; когда кухня уже готовит —
+// списываем или возвращаем сами остатки. Qty < 0 означает уменьшение.
+func (s *OrderService) adjustStockForItems(ctx context.Context, order *domain.Order, items []domain.OrderItem) error {
+	needs, err := s.ingredientNeeds(ctx, items)
+	if err != nil {
+		return err
 	}
 
-	// 7. Генерация PDF-чека в папку "receipts"
-	if fullOrder != nil {
-		if _, err := receipt.GenerateOrderReceiptPDF(fullOrder, waiterName, "receipts"); err != nil {
-			s.logger.Error("Order #%d closed, but failed to generate receipt: %v", id, err)
-			// не ломаем закрытие заказа, просто логируем
-		} else {
-			s.logger.Success("✓ Receipt PDF generated for order #%d", id)
+	more := make(map[int]float64)
+	less := make(map[int]float64)
+	for id, qty := range needs {
+		if qty > 0 {
+			more[id] = qty
+		} else if qty < 0 {
+			less[id] = -qty
 		}
 	}
 
-	s.logger.Order("Order #%d closed successfully (Total: %.2f ₸)", id, order.Total)
+	if order.Status == domain.OrderNew {
+		if err := s.reserveIngredients(ctx, order.ID, more); err != nil {
+			return err
+		}
+		return s.releaseIngredients(ctx, order.ID, less)
+	}
+
+	if err := s.consumeIngredients(ctx, order.ID, more); err != nil {
+		return err
+	}
+	// Возврат на склад — не расход: пишем его отдельным типом движения
+	note := "order item reduced"
+	for _, id := range sortedIngredientIDs(less) {
+		m := domain.StockMovement{IngredientID: id, Type: domain.MovementVoidReturn, Qty: less[id], RefID: &order.ID, Note: &note}
+		if err := s.moveStock(ctx, m); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (s *OrderService) Delete(ctx context.Context, id int) error {
-	s.logger.Warning("Deleting order #%d", id)
+// consumeIngredients сразу списывает ингредиенты со склада с блокировкой строк.
+func (s *OrderService) consumeIngredients(ctx context.Context, orderID int, needs map[int]float64) error {
+	for _, id := range sortedIngredientIDs(needs) {
+		needed := needs[id]
 
-	order, err := s.orderRepo.GetByID(ctx, id)
+		ingredient, err := s.ingredientRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ingredient == nil {
+			return domain.ErrIngredientNotFound
+		}
+		if ingredient.Available() < needed {
+			s.logger.Error("Insufficient stock for ingredient '%s': needed %.2f, available %.2f",
+				ingredient.Name, needed, ingredient.Available())
+			return domain.ErrInsufficientStock
+		}
+
+		m := domain.StockMovement{IngredientID: id, Type: domain.MovementConsumption, Qty: -needed, RefID: &orderID}
+		if err := s.moveStock(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recalculateTotal пересчитывает сумму заказа по его позициям: прогоняет акции,
+// налоги и сервисный сбор через priceOrder, сохраняет скидки и налог позиций и сам заказ.
+// Вызывается только для заказов без оплат, поэтому старую разбивку счёта можно сбросить.
+func (s *OrderService) recalculateTotal(ctx context.Context, order *domain.Order) error {
+	items, err := s.orderRepo.GetItems(ctx, order.ID)
 	if err != nil {
-		s.logger.Error("Failed to get order #%d: %v", id, err)
 		return err
 	}
-	if order == nil {
-		s.logger.Error("Order #%d not found", id)
-		return domain.ErrOrderNotFound
+	promotions, err := s.promotionRepo.GetActive(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get promotions: %v", err)
+		return err
+	}
+	discounts, err := s.promotionRepo.GetDiscounts(ctx, order.ID)
+	if err != nil {
+		return err
 	}
 
-	// Можно добавить проверку: разрешить удалять только новые заказы
-	if order.Status != domain.OrderNew {
-		s.logger.Error("Cannot delete order #%d in status: %s", id, order.Status)
-		return fmt.Errorf("cannot delete order in status: %s", order.Status)
+	var comps []domain.OrderDiscount
+	stored := make(map[int]float64)
+	for _, d := range discounts {
+		if d.Source == domain.DiscountComp {
+			comps = append(comps, d)
+			stored[d.ID] = d.Amount
+		}
 	}
 
-	if err := s.orderRepo.Delete(ctx, id); err != nil {
-		s.logger.Error("Failed to delete order #%d: %v", id, err)
+	priced := priceOrder(order, items, promotions, comps)
+
+	if err := s.promotionRepo.DeletePromotionDiscounts(ctx, order.ID); err != nil {
 		return err
 	}
+	order.Discounts = nil
+	for i := range priced.Promotions {
+		if err := s.promotionRepo.CreateDiscount(ctx, &priced.Promotions[i]); err != nil {
+			s.logger.Error("Failed to save discount for order #%d: %v", order.ID, err)
+			return err
+		}
+		order.Discounts = append(order.Discounts, priced.Promotions[i])
+	}
+	for _, c := range priced.Comps {
+		if c.Amount != stored[c.ID] {
+			if err := s.promotionRepo.UpdateDiscountAmount(ctx, c.ID, c.Amount); err != nil {
+				return err
+			}
+		}
+		order.Discounts = append(order.Discounts, c)
+	}
+	for _, item := range items {
+		discount := roundMoney(priced.ItemDiscounts[item.ID])
+		tax := priced.ItemTaxes[item.ID]
+		if discount == item.Discount && tax == item.Tax {
+			continue
+		}
+		if err := s.orderRepo.UpdateItemPricing(ctx, item.ID, discount, tax); err != nil {
+			return err
+		}
+	}
 
-	s.logger.Success("✓ Order #%d deleted", id)
-	return nil
+	order.Subtotal = priced.Subtotal
+	order.Discount = priced.Discount
+	order.Tax = priced.Tax
+	order.ServiceCharge = priced.ServiceCharge
+	order.Total = priced.Total
+
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		s.logger.Error("Failed to update total for order #%d: %v", order.ID, err)
+		return err
+	}
+
+	// Разбивка считалась от прежнего состава и суммы — гостям нужно разделить счёт заново
+	return s.paymentRepo.DeleteSplits(ctx, order.ID)
+}
+
+// notifyKitchen сообщает кухне об изменении состава заказа.
+func (s *OrderService) notifyKitchen(order *domain.Order, change string) {
+	if order.Status != domain.OrderNew {
+		s.logger.Order("🔔 Kitchen: order #%d (table #%d) changed — %s", order.ID, order.TableNumber, change)
+	}
+
+	event := newOrderEvent(domain.EventOrderItemsChanged, order)
+	event.Message = change
+	s.events.Publish(event)
+}
+
+func (s *OrderService) publishOrderEvent(eventType domain.EventType, order *domain.Order) {
+	s.events.Publish(newOrderEvent(eventType, order))
+}
+
+// publishStatusEvent сообщает о новом статусе заказа; готовность — отдельным событием для официанта.
+func (s *OrderService) publishStatusEvent(order *domain.Order) {
+	if order.Status == domain.OrderReady {
+		s.publishOrderEvent(domain.EventOrderReady, order)
+		return
+	}
+	s.publishOrderEvent(domain.EventOrderStatusChanged, order)
+}
+
+// freeTableIfEmpty освобождает стол, если за ним не осталось открытых заказов.
+// Общая для закрытия, отмены и переноса заказов: за столом их может быть несколько.
+func freeTableIfEmpty(ctx context.Context, orderRepo ports.OrderRepository, tableRepo ports.TableRepository, log *logger.Logger, tableID int) (bool, error) {
+	open, err := orderRepo.GetOpenIDsByTable(ctx, tableID)
+	if err != nil {
+		return false, err
+	}
+	if len(open) > 0 {
+		return false, nil
+	}
+	if err := tableRepo.UpdateStatus(ctx, tableID, domain.TableFree); err != nil {
+		log.Error("Failed to free table #%d: %v", tableID, err)
+		return false, err
+	}
+	return true, nil
 }
 
-// consumeIngredientsForOrder списывает ингредиенты со склада,
-// исходя из позиций заказа и рецептов блюд.
+// generateOrderReceipt печатает итоговый чек закрытого заказа.
+// Заказ к этому моменту уже закрыт, поэтому ошибки только логируются.
+func generateOrderReceipt(ctx context.Context, orderRepo ports.OrderRepository, log *logger.Logger, order *domain.Order) {
+	// Подтягиваем полный заказ с позициями для чека
+	fullOrder, err := orderRepo.GetByID(ctx, order.ID)
+	if err == nil && fullOrder != nil {
+		fullOrder.Items, err = orderRepo.GetItems(ctx, order.ID)
+	}
+	if err == nil && fullOrder != nil {
+		fullOrder.Combos, err = orderRepo.GetCombos(ctx, order.ID)
+	}
+	if err != nil || fullOrder == nil {
+		log.Error("Order #%d closed, but failed to reload for receipt: %v", order.ID, err)
+		return
+	}
+
+	// Имя официанта для чека
+	waiterName := "Unknown"
+	if fullOrder.Waiter != nil && fullOrder.Waiter.Username != "" {
+		waiterName = fullOrder.Waiter.Username
+	}
+
+	if _, err := receipt.GenerateOrderReceiptPDF(fullOrder, waiterName, "receipts"); err != nil {
+		log.Error("Order #%d closed, but failed to generate receipt: %v", order.ID, err)
+		return
+	}
+	log.Success("✓ Receipt PDF generated for order #%d", order.ID)
+}
+
+func newOrderEvent(eventType domain.EventType, order *domain.Order) domain.Event {
+	return domain.Event{
+		Type:     eventType,
+		OrderID:  order.ID,
+		TableID:  order.TableNumber,
+		WaiterID: order.WaiterID,
+		Status:   string(order.Status),
+	}
+}
+
+// consumeIngredientsForOrder списывает ингредиенты со склада по позициям
+// заказа и текущим рецептам блюд и снимает весь резерв заказа — ровно тот,
+// что был сделан, даже если рецепт с тех пор поменялся.
 func (s *OrderService) consumeIngredientsForOrder(ctx context.Context, orderID int) error {
 	s.logger.Order("Consuming ingredients for order #%d", orderID)
 
@@ -340,25 +956,154 @@ func (s *OrderService) consumeIngredientsForOrder(ctx context.Context, orderID i
 		return err
 	}
 
+	needs, err := s.ingredientNeeds(ctx, items)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range sortedIngredientIDs(needs) {
+		needed := needs[id]
+
+		s.logger.Debug("Consuming ingredient #%d: -%.2f for order #%d", id, needed, orderID)
+		m := domain.StockMovement{IngredientID: id, Type: domain.MovementConsumption, Qty: -needed, RefID: &orderID}
+		if err := s.moveStock(ctx, m); err != nil {
+			return err
+		}
+	}
+	if err := s.releaseReservation(ctx, orderID); err != nil {
+		return err
+	}
+
+	s.logger.Success("✓ Ingredients consumed for order #%d", orderID)
+	return nil
+}
+
+// ingredientNeeds считает суммарную потребность в ингредиентах по позициям
+// с учётом модификаторов: добавки увеличивают рецепт порции, "без ..." уменьшают.
+// Заготовки "по заказу" раскладываются на свой состав.
+func (s *OrderService) ingredientNeeds(ctx context.Context, items []domain.OrderItem) (map[int]float64, error) {
+	needs := make(map[int]float64)
 	for _, item := range items {
 		ingredients, err := s.dishRepo.GetIngredients(ctx, item.DishID)
 		if err != nil {
 			s.logger.Error("Failed to get ingredients for dish #%d: %v", item.DishID, err)
-			return err
+			return nil, err
 		}
 
+		portion := make(map[int]float64, len(ingredients))
 		for _, ing := range ingredients {
-			needed := ing.QtyPerDish * float64(item.Qty)
+			portion[ing.IngredientID] += ing.QtyPerDish
+		}
+		for _, m := range item.Modifiers {
+			if m.OptionID == nil {
+				continue
+			}
+			deltas, err := s.modifierRepo.GetOptionIngredients(ctx, *m.OptionID)
+			if err != nil {
+				s.logger.Error("Failed to get ingredients for modifier #%d: %v", *m.OptionID, err)
+				return nil, err
+			}
+			for _, d := range deltas {
+				portion[d.IngredientID] += d.QtyDelta
+			}
+		}
 
-			s.logger.Debug("Consuming ingredient #%d: -%.2f for dish #%d (qty=%d)",
-				ing.IngredientID, needed, item.DishID, item.Qty)
-			if err := s.ingredientRepo.UpdateQuantity(ctx, ing.IngredientID, -needed); err != nil {
-				s.logger.Error("Failed to update ingredient quantity: %v", err)
-				return err
+		// Убрать из порции можно не больше, чем в ней есть
+		for id, qty := range portion {
+			if qty > 0 {
+				needs[id] += qty * float64(item.Qty)
 			}
 		}
 	}
+	if len(needs) == 0 {
+		return needs, nil
+	}
 
-	s.logger.Success("✓ Ingredients consumed for order #%d", orderID)
+	preps, err := s.prepRepo.GetAll(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get prep items: %v", err)
+		return nil, err
+	}
+	return domain.NewPrepRecipes(preps).Resolve(needs), nil
+}
+
+// reserveIngredients блокирует строки ингредиентов (SELECT ... FOR UPDATE),
+// проверяет свободный остаток и резервирует его под заказ. Вызывается внутри транзакции.
+func (s *OrderService) reserveIngredients(ctx context.Context, orderID int, needs map[int]float64) error {
+	// Фиксированный порядок блокировок, чтобы параллельные заказы не ловили deadlock
+	for _, id := range sortedIngredientIDs(needs) {
+		needed := needs[id]
+
+		ingredient, err := s.ingredientRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			s.logger.Error("Failed to lock ingredient #%d: %v", id, err)
+			return err
+		}
+		if ingredient == nil {
+			return domain.ErrIngredientNotFound
+		}
+
+		if ingredient.Available() < needed {
+			s.logger.Error("Insufficient stock for ingredient '%s': needed %.2f, available %.2f",
+				ingredient.Name, needed, ingredient.Available())
+			return domain.ErrInsufficientStock
+		}
+
+		s.logger.Debug("Ingredient '%s': reserving %.2f%s, available %.2f%s",
+			ingredient.Name, needed, ingredient.Unit, ingredient.Available(), ingredient.Unit)
+
+		if err := s.ingredientRepo.Reserve(ctx, orderID, id, needed); err != nil {
+			s.logger.Error("Failed to reserve ingredient #%d: %v", id, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseIngredients снимает часть резерва заказа. needs считаются по текущему
+// рецепту, поэтому снимается не больше, чем заказ держит на самом деле.
+func (s *OrderService) releaseIngredients(ctx context.Context, orderID int, needs map[int]float64) error {
+	held, err := s.ingredientRepo.GetReservations(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range sortedIngredientIDs(needs) {
+		qty := math.Min(needs[id], held[id])
+		if qty <= 0 {
+			continue
+		}
+		if err := s.ingredientRepo.Reserve(ctx, orderID, id, -qty); err != nil {
+			s.logger.Error("Failed to release reserved ingredient #%d: %v", id, err)
+			return err
+		}
+	}
 	return nil
 }
+
+// releaseReservation снимает весь резерв заказа
+func (s *OrderService) releaseReservation(ctx context.Context, orderID int) error {
+	held, err := s.ingredientRepo.GetReservations(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	return s.releaseIngredients(ctx, orderID, held)
+}
+
+// moveStock меняет остаток ингредиента и записывает движение в журнал
+func (s *OrderService) moveStock(ctx context.Context, m domain.StockMovement) error {
+	if err := s.ingredientRepo.ApplyMovement(ctx, &m); err != nil {
+		s.logger.Error("Failed to move stock of ingredient #%d (%s %.2f): %v", m.IngredientID, m.Type, m.Qty, err)
+		return err
+	}
+	return nil
+}
+
+func sortedIngredientIDs(needs map[int]float64) []int {
+	ids := make([]int, 0, len(needs))
+	for id := range needs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
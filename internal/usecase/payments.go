@@ -0,0 +1,518 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/receipt"
+)
+
+type PaymentService struct {
+	orderRepo   ports.OrderRepository
+	paymentRepo ports.PaymentRepository
+	tableRepo   ports.TableRepository
+	txManager   ports.TxManager
+	events      ports.EventPublisher
+	sections    sectionGuard
+	logger      *logger.Logger
+}
+
+func NewPaymentService(
+	orderRepo ports.OrderRepository,
+	paymentRepo ports.PaymentRepository,
+	tableRepo ports.TableRepository,
+	sectionRepo ports.SectionRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+) *PaymentService {
+	return &PaymentService{
+		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
+		tableRepo:   tableRepo,
+		txManager:   txManager,
+		events:      events,
+		sections:    sectionGuard{sectionRepo: sectionRepo},
+		logger:      logger.New("PaymentService"),
+	}
+}
+
+// SplitOrder делит счёт заказа между гостями. Прежнее деление заменяется,
+// поэтому делить заново можно только пока по счёту не было оплат.
+func (s *PaymentService) SplitOrder(ctx context.Context, orderID int, req *domain.SplitRequest) ([]domain.BillSplit, error) {
+	s.logger.Order("Splitting bill of order #%d by %s", orderID, req.Mode)
+
+	var splits []domain.BillSplit
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := s.getOpenOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		paid, err := s.paymentRepo.GetPaidTotal(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if paid > 0 {
+			return domain.ErrBillHasPayments
+		}
+
+		items, err := s.orderRepo.GetItems(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		switch req.Mode {
+		case domain.SplitByItem:
+			splits, err = splitByItem(items, req.Groups)
+		case domain.SplitBySeat:
+			splits, err = splitBySeat(items)
+		case domain.SplitEqual:
+			splits, err = splitEqual(order.Total, req.Parts)
+		default:
+			err = domain.ErrInvalidSplit
+		}
+		if err != nil {
+			return err
+		}
+		fitSplitsToTotal(splits, order.Total)
+
+		if err := s.paymentRepo.DeleteSplits(ctx, orderID); err != nil {
+			return err
+		}
+		for i := range splits {
+			splits[i].OrderID = orderID
+			if err := s.paymentRepo.CreateSplit(ctx, &splits[i]); err != nil {
+				s.logger.Error("Failed to save bill split for order #%d: %v", orderID, err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to split bill of order #%d: %v", orderID, err)
+		return nil, err
+	}
+
+	s.logger.Success("✓ Bill of order #%d split into %d parts", orderID, len(splits))
+	return splits, nil
+}
+
+func (s *PaymentService) GetBill(ctx context.Context, orderID int) (*domain.Bill, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		s.logger.Error("Failed to get order #%d: %v", orderID, err)
+		return nil, err
+	}
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	payments, err := s.paymentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	splits, err := s.paymentRepo.GetSplits(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	bill := &domain.Bill{
+		OrderID:  order.ID,
+		Status:   order.Status,
+		Total:    order.Total,
+		Splits:   splits,
+		Payments: payments,
+	}
+	if bill.Splits == nil {
+		bill.Splits = []domain.BillSplit{}
+	}
+	if bill.Payments == nil {
+		bill.Payments = []domain.Payment{}
+	}
+	for _, p := range payments {
+		bill.Paid += p.Amount
+	}
+	bill.Paid = roundMoney(bill.Paid)
+	bill.Balance = roundMoney(bill.Total - bill.Paid)
+	return bill, nil
+}
+
+// Pay принимает оплату части счёта. Если сумма не указана, оплачивается
+// остаток части (при split_id) или всего счёта. Когда остаток доходит до нуля,
+// заказ закрывается и стол освобождается в той же транзакции.
+func (s *PaymentService) Pay(ctx context.Context, p *domain.Payment) error {
+	if !isValidPayment(p) {
+		return domain.ErrInvalidPayment
+	}
+	s.logger.Order("Accepting %s payment for order #%d", p.Method, p.OrderID)
+
+	var order *domain.Order
+	var split *domain.BillSplit
+	var balance float64
+	var freed bool
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.Lock(ctx, p.OrderID); err != nil {
+			return err
+		}
+
+		var err error
+		order, err = s.orderRepo.GetByID(ctx, p.OrderID)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return domain.ErrOrderNotFound
+		}
+		if err := s.sections.checkOrder(ctx, order); err != nil {
+			return err
+		}
+		if order.Status != domain.OrderReady {
+			s.logger.Error("Order #%d must be in 'ready' status to be paid, current: %s", order.ID, order.Status)
+			return domain.ErrOrderNotReady
+		}
+
+		paid, err := s.paymentRepo.GetPaidTotal(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		balance = roundMoney(order.Total - paid)
+
+		// Счёт полностью скомпенсирован (comp, скидки): платить нечего, и заказ
+		// закрывается без записи в payments — там бывают только суммы > 0
+		if balance <= 0 {
+			if !isZeroPayment(p) {
+				return domain.ErrInvalidPayment
+			}
+			balance = 0
+			freed, err = s.markPaid(ctx, order)
+			return err
+		}
+
+		limit := balance
+		if p.SplitID != nil {
+			split, err = s.findSplit(ctx, order.ID, *p.SplitID)
+			if err != nil {
+				return err
+			}
+			limit = math.Min(roundMoney(split.Remaining()), balance)
+		}
+
+		p.Amount = roundMoney(p.Amount)
+		if p.Amount == 0 {
+			p.Amount = limit
+		}
+		if p.Amount <= 0 {
+			return domain.ErrInvalidPayment
+		}
+		if p.Amount > limit {
+			return domain.ErrPaymentExceedsBalance
+		}
+		p.ServiceCharge = serviceChargeShare(order, paid, p.Amount)
+
+		if err := fillTender(p); err != nil {
+			return err
+		}
+
+		if err := s.paymentRepo.Create(ctx, p); err != nil {
+			s.logger.Error("Failed to save payment for order #%d: %v", order.ID, err)
+			return err
+		}
+		balance = roundMoney(balance - p.Amount)
+		if split != nil {
+			split.Paid += p.Amount
+		}
+
+		if balance > 0 {
+			return nil
+		}
+		freed, err = s.markPaid(ctx, order)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to accept payment for order #%d: %v", p.OrderID, err)
+		return err
+	}
+
+	if p.ID != 0 {
+		s.logger.Success("✓ Payment #%d for order #%d: %.2f ₸ (%s), tip %.2f ₸, change %.2f ₸, balance %.2f ₸",
+			p.ID, order.ID, p.Amount, p.Method, p.Tip, p.Change, balance)
+		s.generatePaymentReceipt(order, p, split, balance)
+	}
+
+	if order.Status == domain.OrderPaid {
+		s.logger.Success("✓ Order #%d fully paid", order.ID)
+		s.events.Publish(newOrderEvent(domain.EventOrderClosed, order))
+		if freed {
+			s.logger.Success("✓ Table #%d freed", order.TableNumber)
+			s.events.Publish(domain.Event{
+				Type:    domain.EventTableStatusChanged,
+				TableID: order.TableNumber,
+				Status:  string(domain.TableFree),
+			})
+		}
+		generateOrderReceipt(ctx, s.orderRepo, s.logger, order)
+	}
+	return nil
+}
+
+// markPaid закрывает полностью оплаченный заказ и освобождает стол, если за
+// ним не осталось других открытых заказов; возвращает, освободился ли стол
+func (s *PaymentService) markPaid(ctx context.Context, order *domain.Order) (bool, error) {
+	if err := s.orderRepo.UpdateStatus(ctx, order.ID, domain.OrderPaid); err != nil {
+		s.logger.Error("Failed to update order status: %v", err)
+		return false, err
+	}
+	order.Status = domain.OrderPaid
+	return freeTableIfEmpty(ctx, s.orderRepo, s.tableRepo, s.logger, order.TableNumber)
+}
+
+// getOpenOrder блокирует и возвращает открытый заказ: деление счёта не должно
+// пересечься с оплатой, которая проводится по заменяемой части
+func (s *PaymentService) getOpenOrder(ctx context.Context, orderID int) (*domain.Order, error) {
+	if err := s.orderRepo.Lock(ctx, orderID); err != nil {
+		return nil, err
+	}
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		s.logger.Error("Failed to get order #%d: %v", orderID, err)
+		return nil, err
+	}
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+	if !order.Status.IsOpen() {
+		return nil, domain.ErrOrderNotEditable
+	}
+	if err := s.sections.checkOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *PaymentService) findSplit(ctx context.Context, orderID, splitID int) (*domain.BillSplit, error) {
+	splits, err := s.paymentRepo.GetSplits(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range splits {
+		if splits[i].ID == splitID {
+			return &splits[i], nil
+		}
+	}
+	return nil, domain.ErrSplitNotFound
+}
+
+// generatePaymentReceipt печатает чек плательщика; оплата уже проведена,
+// поэтому ошибки только логируются.
+func (s *PaymentService) generatePaymentReceipt(order *domain.Order, p *domain.Payment, split *domain.BillSplit, balance float64) {
+	waiterName := "Unknown"
+	if order.Waiter != nil && order.Waiter.Username != "" {
+		waiterName = order.Waiter.Username
+	}
+
+	if _, err := receipt.GeneratePaymentReceiptPDF(order, p, split, balance, waiterName, "receipts"); err != nil {
+		s.logger.Error("Payment #%d saved, but failed to generate receipt: %v", p.ID, err)
+		return
+	}
+	s.logger.Success("✓ Receipt PDF generated for payment #%d", p.ID)
+}
+
+func isValidPayment(p *domain.Payment) bool {
+	return p.Method.IsValid() && p.Amount >= 0 && p.Tendered >= 0 && p.Tip >= 0
+}
+
+// isZeroPayment сообщает, что по платежу не нужно ничего проводить:
+// так закрывается полностью скомпенсированный счёт.
+func isZeroPayment(p *domain.Payment) bool {
+	return roundMoney(p.Charged()) == 0
+}
+
+// serviceChargeShare — доля сервисного сбора заказа в платеже amount, внесённом
+// после уже оплаченных paid. Считается нарастающим итогом, чтобы доли всех
+// платежей в сумме совпали со сбором заказа до копейки.
+func serviceChargeShare(order *domain.Order, paid, amount float64) float64 {
+	if order.ServiceCharge <= 0 || order.Total <= 0 {
+		return 0
+	}
+	upTo := func(sum float64) float64 {
+		return roundMoney(order.ServiceCharge * math.Min(sum, order.Total) / order.Total)
+	}
+	return roundMoney(upTo(paid+amount) - upTo(paid))
+}
+
+// fillTender считает сдачу с суммы платежа вместе с чаевыми.
+// Наличные могут быть с переплатой, карта и перевод проводятся ровно на эту сумму.
+func fillTender(p *domain.Payment) error {
+	p.Tip = roundMoney(p.Tip)
+	charged := roundMoney(p.Charged())
+
+	if p.Method != domain.PaymentCash {
+		p.Tendered = charged
+		p.Change = 0
+		return nil
+	}
+
+	p.Tendered = roundMoney(p.Tendered)
+	if p.Tendered == 0 {
+		p.Tendered = charged
+	}
+	if p.Tendered < charged {
+		return domain.ErrInsufficientTender
+	}
+	p.Change = roundMoney(p.Tendered - charged)
+	return nil
+}
+
+// splitByItem раскладывает позиции по указанным группам.
+// Всё, что не попало ни в одну группу, уходит в общую часть "Rest".
+func splitByItem(items []domain.OrderItem, groups []domain.SplitGroup) ([]domain.BillSplit, error) {
+	if len(groups) == 0 {
+		return nil, domain.ErrInvalidSplit
+	}
+
+	left := make(map[int]int, len(items))
+	byID := make(map[int]domain.OrderItem, len(items))
+	for _, item := range items {
+		left[item.ID] = item.Qty
+		byID[item.ID] = item
+	}
+
+	var splits []domain.BillSplit
+	for i, group := range groups {
+		if len(group.Items) == 0 {
+			return nil, domain.ErrInvalidSplit
+		}
+
+		split := domain.BillSplit{Label: group.Label}
+		if split.Label == "" {
+			split.Label = fmt.Sprintf("Guest %d", i+1)
+		}
+		for _, gi := range group.Items {
+			item, ok := byID[gi.OrderItemID]
+			if !ok {
+				return nil, domain.ErrOrderItemNotFound
+			}
+			qty := gi.Qty
+			if qty == 0 {
+				qty = left[item.ID]
+			}
+			if qty <= 0 || qty > left[item.ID] {
+				return nil, domain.ErrInvalidSplit
+			}
+			left[item.ID] -= qty
+			split.Items = append(split.Items, newSplitItem(item, qty))
+		}
+		splits = append(splits, sumSplit(split))
+	}
+
+	rest := domain.BillSplit{Label: "Rest"}
+	for _, item := range items {
+		if left[item.ID] > 0 {
+			rest.Items = append(rest.Items, newSplitItem(item, left[item.ID]))
+		}
+	}
+	if len(rest.Items) > 0 {
+		splits = append(splits, sumSplit(rest))
+	}
+	return splits, nil
+}
+
+// splitBySeat делит счёт по местам гостей; позиции без места идут в "Shared".
+func splitBySeat(items []domain.OrderItem) ([]domain.BillSplit, error) {
+	bySeat := make(map[int][]domain.BillSplitItem)
+	var shared []domain.BillSplitItem
+	for _, item := range items {
+		if item.Seat == nil {
+			shared = append(shared, newSplitItem(item, item.Qty))
+			continue
+		}
+		bySeat[*item.Seat] = append(bySeat[*item.Seat], newSplitItem(item, item.Qty))
+	}
+	if len(bySeat) == 0 {
+		return nil, domain.ErrInvalidSplit
+	}
+
+	seats := make([]int, 0, len(bySeat))
+	for seat := range bySeat {
+		seats = append(seats, seat)
+	}
+	sort.Ints(seats)
+
+	splits := make([]domain.BillSplit, 0, len(seats)+1)
+	for _, seat := range seats {
+		splits = append(splits, sumSplit(domain.BillSplit{
+			Label: fmt.Sprintf("Seat %d", seat),
+			Items: bySeat[seat],
+		}))
+	}
+	if len(shared) > 0 {
+		splits = append(splits, sumSplit(domain.BillSplit{Label: "Shared", Items: shared}))
+	}
+	return splits, nil
+}
+
+// splitEqual делит сумму на равные доли; копейки от округления достаются последней доле.
+func splitEqual(total float64, parts int) ([]domain.BillSplit, error) {
+	if parts < 2 {
+		return nil, domain.ErrInvalidSplit
+	}
+
+	share := math.Floor(total/float64(parts)*100) / 100
+	splits := make([]domain.BillSplit, parts)
+	for i := range splits {
+		splits[i] = domain.BillSplit{
+			Label:  fmt.Sprintf("Guest %d", i+1),
+			Amount: share,
+		}
+	}
+	splits[parts-1].Amount = roundMoney(total - share*float64(parts-1))
+	return splits, nil
+}
+
+// newSplitItem считает долю строки с учётом скидки на позицию
+func newSplitItem(item domain.OrderItem, qty int) domain.BillSplitItem {
+	line := item.Price*float64(item.Qty) - item.Discount
+	return domain.BillSplitItem{
+		OrderItemID: item.ID,
+		Qty:         qty,
+		Amount:      roundMoney(line * float64(qty) / float64(item.Qty)),
+	}
+}
+
+// fitSplitsToTotal распределяет скидку на весь заказ между частями
+// пропорционально их сумме; копейки от округления достаются последней части.
+func fitSplitsToTotal(splits []domain.BillSplit, total float64) {
+	var sum float64
+	for _, split := range splits {
+		sum += split.Amount
+	}
+	sum = roundMoney(sum)
+	if len(splits) == 0 || sum == 0 || sum == total {
+		return
+	}
+
+	var assigned float64
+	for i := range splits[:len(splits)-1] {
+		splits[i].Amount = roundMoney(splits[i].Amount * total / sum)
+		assigned += splits[i].Amount
+	}
+	splits[len(splits)-1].Amount = roundMoney(total - assigned)
+}
+
+func sumSplit(split domain.BillSplit) domain.BillSplit {
+	split.Amount = 0
+	for _, item := range split.Items {
+		split.Amount += item.Amount
+	}
+	split.Amount = roundMoney(split.Amount)
+	return split
+}
+
+func roundMoney(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+// Фейки реализуют только то, что нужно закрытию заказа; остальные методы
+// интерфейсов не вызываются и паникуют через nil-встраивание.
+
+type fakeOrderRepo struct {
+	ports.OrderRepository
+	order *domain.Order
+}
+
+func (r *fakeOrderRepo) Lock(ctx context.Context, id int) error { return nil }
+
+func (r *fakeOrderRepo) GetByID(ctx context.Context, id int) (*domain.Order, error) {
+	order := *r.order
+	return &order, nil
+}
+
+func (r *fakeOrderRepo) UpdateStatus(ctx context.Context, id int, status domain.OrderStatus) error {
+	r.order.Status = status
+	return nil
+}
+
+func (r *fakeOrderRepo) GetItems(ctx context.Context, orderID int) ([]domain.OrderItem, error) {
+	return nil, nil
+}
+
+func (r *fakeOrderRepo) GetOpenIDsByTable(ctx context.Context, tableID int) ([]int, error) {
+	return nil, nil
+}
+
+func (r *fakeOrderRepo) GetCombos(ctx context.Context, orderID int) ([]domain.OrderCombo, error) {
+	return nil, nil
+}
+
+type fakePaymentRepo struct {
+	ports.PaymentRepository
+	created []domain.Payment
+}
+
+func (r *fakePaymentRepo) GetPaidTotal(ctx context.Context, orderID int) (float64, error) {
+	var total float64
+	for _, p := range r.created {
+		total += p.Amount
+	}
+	return total, nil
+}
+
+func (r *fakePaymentRepo) Create(ctx context.Context, p *domain.Payment) error {
+	p.ID = len(r.created) + 1
+	r.created = append(r.created, *p)
+	return nil
+}
+
+type fakeTableRepo struct {
+	ports.TableRepository
+	status domain.TableStatus
+}
+
+func (r *fakeTableRepo) UpdateStatus(ctx context.Context, id int, status domain.TableStatus) error {
+	r.status = status
+	return nil
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeEvents struct{}
+
+func (fakeEvents) Publish(event domain.Event) {}
+
+// compedOrder — готовый заказ, целиком списанный комплиментом
+func compedOrder() *domain.Order {
+	return &domain.Order{
+		ID:          7,
+		TableNumber: 3,
+		Status:      domain.OrderReady,
+		Subtotal:    5400,
+		Discount:    5400,
+		Total:       0,
+	}
+}
+
+func TestCloseOrderFullyComped(t *testing.T) {
+	t.Chdir(t.TempDir()) // чек заказа пишется в ./receipts
+
+	orders := &fakeOrderRepo{order: compedOrder()}
+	payments := &fakePaymentRepo{}
+	tables := &fakeTableRepo{status: domain.TableBusy}
+	s := &OrderService{
+		orderRepo:   orders,
+		paymentRepo: payments,
+		tableRepo:   tables,
+		txManager:   fakeTxManager{},
+		events:      fakeEvents{},
+		logger:      logger.New("test"),
+	}
+
+	err := s.CloseOrder(context.Background(), &domain.Payment{OrderID: 7, Method: domain.PaymentCash})
+	if err != nil {
+		t.Fatalf("CloseOrder: %v", err)
+	}
+	if len(payments.created) != 0 {
+		t.Errorf("payments created = %d, want 0", len(payments.created))
+	}
+	if orders.order.Status != domain.OrderPaid {
+		t.Errorf("order status = %s, want %s", orders.order.Status, domain.OrderPaid)
+	}
+	if tables.status != domain.TableFree {
+		t.Errorf("table status = %s, want %s", tables.status, domain.TableFree)
+	}
+}
+
+func TestPayFullyComped(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tests := []struct {
+		name       string
+		payment    domain.Payment
+		wantErr    error
+		wantStatus domain.OrderStatus
+	}{
+		{
+			name:       "closes without a payment",
+			payment:    domain.Payment{OrderID: 7, Method: domain.PaymentCard},
+			wantStatus: domain.OrderPaid,
+		},
+		{
+			name:       "rejects a tip",
+			payment:    domain.Payment{OrderID: 7, Method: domain.PaymentCard, Tip: 500},
+			wantErr:    domain.ErrInvalidPayment,
+			wantStatus: domain.OrderReady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &fakeOrderRepo{order: compedOrder()}
+			payments := &fakePaymentRepo{}
+			s := &PaymentService{
+				orderRepo:   orders,
+				paymentRepo: payments,
+				tableRepo:   &fakeTableRepo{status: domain.TableBusy},
+				txManager:   fakeTxManager{},
+				events:      fakeEvents{},
+				logger:      logger.New("test"),
+			}
+
+			p := tt.payment
+			if err := s.Pay(context.Background(), &p); err != tt.wantErr {
+				t.Fatalf("Pay error = %v, want %v", err, tt.wantErr)
+			}
+			if len(payments.created) != 0 {
+				t.Errorf("payments created = %d, want 0", len(payments.created))
+			}
+			if orders.order.Status != tt.wantStatus {
+				t.Errorf("order status = %s, want %s", orders.order.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestServiceChargeShare(t *testing.T) {
+	order := &domain.Order{Total: 1000, ServiceCharge: 100}
+
+	// Три равные части: доли в сумме дают сбор заказа без потери копеек
+	var total float64
+	paid := 0.0
+	for _, amount := range []float64{333.33, 333.33, 333.34} {
+		total += serviceChargeShare(order, paid, amount)
+		paid += amount
+	}
+	if roundMoney(total) != order.ServiceCharge {
+		t.Errorf("service charge shares = %.2f, want %.2f", total, order.ServiceCharge)
+	}
+
+	if got := serviceChargeShare(&domain.Order{Total: 1000}, 0, 1000); got != 0 {
+		t.Errorf("share without service charge = %.2f, want 0", got)
+	}
+}
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+type PrepService struct {
+	prepRepo       ports.PrepRepository
+	ingredientRepo ports.IngredientRepository
+	txManager      ports.TxManager
+	events         ports.EventPublisher
+	logger         *logger.Logger
+}
+
+func NewPrepService(
+	prepRepo ports.PrepRepository,
+	ingredientRepo ports.IngredientRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+) *PrepService {
+	return &PrepService{
+		prepRepo:       prepRepo,
+		ingredientRepo: ingredientRepo,
+		txManager:      txManager,
+		events:         events,
+		logger:         logger.New("PrepService"),
+	}
+}
+
+func (s *PrepService) GetAll(ctx context.Context) ([]domain.PrepItem, error) {
+	return s.prepRepo.GetAll(ctx)
+}
+
+func (s *PrepService) GetByID(ctx context.Context, ingredientID int) (*domain.PrepItem, error) {
+	prep, err := s.prepRepo.GetByID(ctx, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if prep == nil {
+		return nil, domain.ErrPrepItemNotFound
+	}
+	return prep, nil
+}
+
+// Save делает ингредиент заготовкой или меняет её состав. Заготовка не может
+// (даже через другие заготовки) входить в собственный состав.
+func (s *PrepService) Save(ctx context.Context, prep *domain.PrepItem) error {
+	if !prep.IsValid() {
+		return domain.ErrInvalidPrepItem
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		ingredient, err := s.ingredientRepo.GetByID(ctx, prep.IngredientID)
+		if err != nil {
+			return err
+		}
+		if ingredient == nil {
+			return domain.ErrIngredientNotFound
+		}
+
+		recipes, err := s.recipes(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range prep.Components {
+			c := &prep.Components[i]
+			c.Ingredient, err = s.ingredientRepo.GetByID(ctx, c.IngredientID)
+			if err != nil {
+				return err
+			}
+			if c.Ingredient == nil {
+				return domain.ErrIngredientNotFound
+			}
+			if recipes.Uses(c.IngredientID, prep.IngredientID) {
+				return domain.ErrPrepCycle
+			}
+		}
+
+		if err := s.prepRepo.Save(ctx, prep); err != nil {
+			s.logger.Error("Failed to save prep item '%s': %v", ingredient.Name, err)
+			return err
+		}
+
+		// Заготовку "по заказу" не выпускают партиями — её себестоимость
+		// считается по составу. Здесь сохраняем значение для справочника;
+		// отчёты по себестоимости пересчитывают его по текущим ценам.
+		if prep.MadeToOrder {
+			recipes[prep.IngredientID] = prep
+			ingredient.UnitCost = recipes.UnitCost(ingredient)
+			if err := s.ingredientRepo.SetUnitCost(ctx, ingredient.ID, ingredient.UnitCost); err != nil {
+				return err
+			}
+		}
+		prep.Ingredient = ingredient
+
+		s.logger.Info("Prep item '%s' saved: %d components, yield %.2f%s",
+			ingredient.Name, len(prep.Components), prep.BatchYield, ingredient.Unit)
+		return nil
+	})
+}
+
+// Delete снова делает заготовку обычным ингредиентом; остаток не трогаем
+func (s *PrepService) Delete(ctx context.Context, ingredientID int) error {
+	if _, err := s.GetByID(ctx, ingredientID); err != nil {
+		return err
+	}
+	return s.prepRepo.Delete(ctx, ingredientID)
+}
+
+// Produce записывает выпуск партий заготовки: состав списывается со свободного
+// остатка (заготовки "по заказу" раскладываются на свой состав), а заготовка
+// приходуется по себестоимости списанного.
+func (s *PrepService) Produce(ctx context.Context, batch *domain.ProductionBatch) error {
+	if batch.Batches <= 0 || batch.YieldQty < 0 {
+		return domain.ErrInvalidPrepItem
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		recipes, err := s.recipes(ctx)
+		if err != nil {
+			return err
+		}
+		prep, ok := recipes[batch.PrepID]
+		if !ok {
+			return domain.ErrPrepItemNotFound
+		}
+		if prep.MadeToOrder {
+			return domain.ErrPrepMadeToOrder
+		}
+		if batch.YieldQty == 0 {
+			batch.YieldQty = prep.BatchYield * batch.Batches
+		}
+
+		// Сначала блокируем и проверяем состав, считаем себестоимость партии;
+		// движения по журналу пишем уже со ссылкой на партию. Строку самой
+		// заготовки блокируем в том же проходе по возрастанию id, что и заказы
+		// с инвентаризацией, иначе возможна взаимная блокировка.
+		needs := recipes.Explode(prep, batch.Batches)
+		ids := sortedIngredientIDs(needs)
+		locked := map[int]float64{prep.IngredientID: 0}
+		for id, qty := range needs {
+			locked[id] = qty
+		}
+
+		var stock *domain.Ingredient
+		batch.Cost = 0
+		for _, id := range sortedIngredientIDs(locked) {
+			ingredient, err := s.ingredientRepo.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if id == prep.IngredientID {
+				if ingredient == nil {
+					return domain.ErrPrepItemNotFound
+				}
+				stock = ingredient
+			}
+			needed, ok := needs[id]
+			if !ok {
+				continue
+			}
+			if ingredient == nil {
+				return domain.ErrIngredientNotFound
+			}
+			if ingredient.Available() < needed {
+				s.logger.Error("Insufficient stock for '%s' to make a batch: needed %.2f, available %.2f",
+					ingredient.Name, needed, ingredient.Available())
+				return domain.ErrInsufficientStock
+			}
+			batch.Cost += needed * ingredient.UnitCost
+		}
+		batch.Cost = roundMoney(batch.Cost)
+
+		if err := s.prepRepo.CreateBatch(ctx, batch); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			m := &domain.StockMovement{
+				IngredientID: id,
+				Type:         domain.MovementProduction,
+				Qty:          -needs[id],
+				RefID:        &batch.ID,
+				UserID:       batch.UserID,
+			}
+			if err := s.ingredientRepo.ApplyMovement(ctx, m); err != nil {
+				return err
+			}
+		}
+
+		stock.Receive(batch.YieldQty, batch.Cost/batch.YieldQty)
+		m := &domain.StockMovement{
+			IngredientID: stock.ID,
+			Type:         domain.MovementProduction,
+			Qty:          batch.YieldQty,
+			RefID:        &batch.ID,
+			UserID:       batch.UserID,
+		}
+		if err := s.ingredientRepo.ApplyMovement(ctx, m); err != nil {
+			return err
+		}
+		if err := s.ingredientRepo.SetUnitCost(ctx, stock.ID, stock.UnitCost); err != nil {
+			return err
+		}
+
+		batch.Prep = stock
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Publish(domain.Event{Type: domain.EventStockChanged})
+	s.logger.Success("✓ Produced %.2f%s of '%s' (%.2f batches, cost %.2f ₸)",
+		batch.YieldQty, batch.Prep.Unit, batch.Prep.Name, batch.Batches, batch.Cost)
+	return nil
+}
+
+func (s *PrepService) GetBatches(ctx context.Context, prepID *int, limit int) ([]domain.ProductionBatch, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.prepRepo.GetBatches(ctx, prepID, limit)
+}
+
+func (s *PrepService) recipes(ctx context.Context) (domain.PrepRecipes, error) {
+	preps, err := s.prepRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPrepRecipes(preps), nil
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+// pricedOrder is the result of running the pricing rules over an order
+type pricedOrder struct {
+	Subtotal      float64
+	Discount      float64
+	Tax           float64
+	ServiceCharge float64
+	Total         float64                // Subtotal - Discount + Tax + ServiceCharge
+	ItemDiscounts map[int]float64        // order item ID -> discount on the whole line
+	ItemTaxes     map[int]float64        // order item ID -> VAT on the line
+	Promotions    []domain.OrderDiscount // promotion discounts to store
+	Comps         []domain.OrderDiscount // comps with recomputed amounts
+}
+
+// priceOrder применяет скидки к заказу в фиксированном порядке:
+//  1. акции на позиции (скидка на категорию, buy X get Y) — на строку берётся
+//     самая выгодная из подходящих, акции между собой не суммируются;
+//     блюда из комбо уже идут по цене набора и под акции на позиции не попадают;
+//  2. comp менеджера на позицию — от того, что осталось после акции;
+//  3. акция на весь заказ — самая выгодная из подходящих;
+//  4. comp менеджера на весь заказ.
+//
+// Время акции проверяется по моменту добавления позиции (для скидок на позиции)
+// и по моменту создания заказа (для скидок на заказ), поэтому happy hour
+// не «слетает» при пересчёте заказа после окончания окна.
+//
+// НДС начисляется сверху на цену после всех скидок: скидка на заказ
+// раскладывается по строкам пропорционально их сумме, а налог считается
+// по ставке, зафиксированной в позиции. Сервисный сбор берётся процентом
+// от суммы после скидок и налогом не облагается.
+func priceOrder(order *domain.Order, items []domain.OrderItem, promotions []domain.Promotion, comps []domain.OrderDiscount) pricedOrder {
+	result := pricedOrder{
+		ItemDiscounts: make(map[int]float64, len(items)),
+		ItemTaxes:     make(map[int]float64, len(items)),
+	}
+
+	net := make(map[int]float64, len(items))
+	for _, item := range items {
+		line := roundMoney(item.Price * float64(item.Qty))
+		net[item.ID] = line
+		result.Subtotal += line
+	}
+	result.Subtotal = roundMoney(result.Subtotal)
+
+	var eligible []domain.Promotion
+	for _, p := range promotions {
+		if p.MatchesCode(order.PromoCode) && result.Subtotal >= p.MinSubtotal {
+			eligible = append(eligible, p)
+		}
+	}
+
+	// 1. Акции на позиции
+	best := make(map[int]domain.OrderDiscount)
+	for _, p := range eligible {
+		if !p.Type.IsItemLevel() {
+			continue
+		}
+		for itemID, amount := range itemPromotionAmounts(p, items) {
+			amount = math.Min(roundMoney(amount), net[itemID])
+			if amount <= 0 || amount <= best[itemID].Amount {
+				continue
+			}
+			best[itemID] = promotionDiscount(order.ID, p, &itemID, amount)
+		}
+	}
+	for _, item := range items {
+		d, ok := best[item.ID]
+		if !ok {
+			continue
+		}
+		net[item.ID] = roundMoney(net[item.ID] - d.Amount)
+		result.ItemDiscounts[item.ID] += d.Amount
+		result.Promotions = append(result.Promotions, d)
+	}
+
+	// 2. Comp на позиции
+	for _, c := range comps {
+		if c.OrderItemID == nil {
+			continue
+		}
+		itemID := *c.OrderItemID
+		c.Amount = discountAmount(c.Type, c.Value, net[itemID])
+		net[itemID] = roundMoney(net[itemID] - c.Amount)
+		result.ItemDiscounts[itemID] += c.Amount
+		result.Comps = append(result.Comps, c)
+	}
+
+	remaining := 0.0
+	for _, amount := range net {
+		remaining += amount
+	}
+	remaining = roundMoney(remaining)
+	beforeOrderDiscounts := remaining
+
+	// 3. Акция на заказ
+	var orderPromo *domain.OrderDiscount
+	for _, p := range eligible {
+		if p.Type.IsItemLevel() || !p.AppliesAt(order.CreatedAt) {
+			continue
+		}
+		dtype := domain.DiscountPercent
+		if p.Type == domain.PromoOrderFixed {
+			dtype = domain.DiscountFixed
+		}
+		amount := discountAmount(dtype, p.Value, remaining)
+		if amount > 0 && (orderPromo == nil || amount > orderPromo.Amount) {
+			d := promotionDiscount(order.ID, p, nil, amount)
+			orderPromo = &d
+		}
+	}
+	if orderPromo != nil {
+		remaining = roundMoney(remaining - orderPromo.Amount)
+		result.Promotions = append(result.Promotions, *orderPromo)
+	}
+
+	// 4. Comp на заказ
+	for _, c := range comps {
+		if c.OrderItemID != nil {
+			continue
+		}
+		c.Amount = discountAmount(c.Type, c.Value, remaining)
+		remaining = roundMoney(remaining - c.Amount)
+		result.Comps = append(result.Comps, c)
+	}
+
+	for _, d := range result.Promotions {
+		result.Discount += d.Amount
+	}
+	for _, c := range result.Comps {
+		result.Discount += c.Amount
+	}
+	result.Discount = roundMoney(result.Discount)
+
+	// Налог по строкам с учётом доли скидки на заказ
+	share := 0.0
+	if beforeOrderDiscounts > 0 {
+		share = remaining / beforeOrderDiscounts
+	}
+	for _, item := range items {
+		if item.VatRate <= 0 {
+			continue
+		}
+		tax := roundMoney(net[item.ID] * share * item.VatRate / 100)
+		result.ItemTaxes[item.ID] = tax
+		result.Tax += tax
+	}
+	result.Tax = roundMoney(result.Tax)
+
+	discounted := math.Max(result.Subtotal-result.Discount, 0)
+	result.ServiceCharge = roundMoney(discounted * order.ServiceChargeRate / 100)
+	result.Total = roundMoney(discounted + result.Tax + result.ServiceCharge)
+	return result
+}
+
+// itemPromotionAmounts возвращает скидку акции по строкам заказа
+func itemPromotionAmounts(p domain.Promotion, items []domain.OrderItem) map[int]float64 {
+	amounts := make(map[int]float64)
+
+	switch p.Type {
+	case domain.PromoCategoryPercent:
+		for _, item := range items {
+			if item.ComboLineID != nil {
+				continue
+			}
+			if item.Dish == nil || p.CategoryID == nil || item.Dish.CategoryID != *p.CategoryID {
+				continue
+			}
+			if !p.AppliesAt(item.CreatedAt) {
+				continue
+			}
+			amounts[item.ID] = item.Price * float64(item.Qty) * p.Value / 100
+		}
+
+	case domain.PromoBuyXGetY:
+		var lines []domain.OrderItem
+		qty := 0
+		for _, item := range items {
+			if item.ComboLineID != nil || p.DishID == nil || item.DishID != *p.DishID || !p.AppliesAt(item.CreatedAt) {
+				continue
+			}
+			lines = append(lines, item)
+			qty += item.Qty
+		}
+
+		// Бесплатными становятся самые дешёвые порции
+		free := qty / (p.BuyQty + p.GetQty) * p.GetQty
+		sort.SliceStable(lines, func(i, j int) bool { return lines[i].Price < lines[j].Price })
+		for _, item := range lines {
+			if free == 0 {
+				break
+			}
+			n := item.Qty
+			if n > free {
+				n = free
+			}
+			amounts[item.ID] = item.Price * float64(n)
+			free -= n
+		}
+	}
+
+	return amounts
+}
+
+// discountAmount считает скидку от base, не больше самой base
+func discountAmount(dtype domain.DiscountType, value, base float64) float64 {
+	if base <= 0 {
+		return 0
+	}
+	amount := value
+	if dtype == domain.DiscountPercent {
+		amount = base * value / 100
+	}
+	return roundMoney(math.Min(amount, base))
+}
+
+func promotionDiscount(orderID int, p domain.Promotion, itemID *int, amount float64) domain.OrderDiscount {
+	promotionID := p.ID
+	d := domain.OrderDiscount{
+		OrderID:     orderID,
+		PromotionID: &promotionID,
+		Source:      domain.DiscountPromotion,
+		Name:        p.Name,
+		Type:        domain.DiscountPercent,
+		Value:       p.Value,
+		Amount:      amount,
+	}
+	if itemID != nil {
+		id := *itemID
+		d.OrderItemID = &id
+	}
+	if p.Type == domain.PromoOrderFixed || p.Type == domain.PromoBuyXGetY {
+		d.Type = domain.DiscountFixed
+		d.Value = amount
+	}
+	return d
+}
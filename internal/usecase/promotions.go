@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+)
+
+type PromotionService struct {
+	promotionRepo ports.PromotionRepository
+}
+
+func NewPromotionService(promotionRepo ports.PromotionRepository) *PromotionService {
+	return &PromotionService{promotionRepo: promotionRepo}
+}
+
+func (s *PromotionService) GetAll(ctx context.Context) ([]domain.Promotion, error) {
+	return s.promotionRepo.GetAll(ctx)
+}
+
+func (s *PromotionService) GetByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	promotion, err := s.promotionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if promotion == nil {
+		return nil, domain.ErrPromotionNotFound
+	}
+	return promotion, nil
+}
+
+func (s *PromotionService) Create(ctx context.Context, promotion *domain.Promotion) error {
+	if err := s.validate(ctx, promotion); err != nil {
+		return err
+	}
+	return s.promotionRepo.Create(ctx, promotion)
+}
+
+func (s *PromotionService) Update(ctx context.Context, promotion *domain.Promotion) error {
+	if _, err := s.GetByID(ctx, promotion.ID); err != nil {
+		return err
+	}
+	if err := s.validate(ctx, promotion); err != nil {
+		return err
+	}
+	return s.promotionRepo.Update(ctx, promotion)
+}
+
+func (s *PromotionService) Delete(ctx context.Context, id int) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.promotionRepo.Delete(ctx, id)
+}
+
+// validate нормализует промокод и проверяет, что он не занят другой акцией
+func (s *PromotionService) validate(ctx context.Context, promotion *domain.Promotion) error {
+	if promotion.Code != nil {
+		code := strings.TrimSpace(*promotion.Code)
+		promotion.Code = &code
+		if code == "" {
+			promotion.Code = nil
+		}
+	}
+
+	if !promotion.IsValid() {
+		return domain.ErrInvalidPromotion
+	}
+
+	if promotion.Code != nil {
+		existing, err := s.promotionRepo.GetByCode(ctx, *promotion.Code)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != promotion.ID {
+			return domain.ErrPromoCodeExists
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+type PurchaseOrderService struct {
+	purchaseOrderRepo ports.PurchaseOrderRepository
+	supplierRepo      ports.SupplierRepository
+	supplyRepo        ports.SupplyRepository
+	ingredientRepo    ports.IngredientRepository
+	txManager         ports.TxManager
+	events            ports.EventPublisher
+	logger            *logger.Logger
+}
+
+func NewPurchaseOrderService(
+	purchaseOrderRepo ports.PurchaseOrderRepository,
+	supplierRepo ports.SupplierRepository,
+	supplyRepo ports.SupplyRepository,
+	ingredientRepo ports.IngredientRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+) *PurchaseOrderService {
+	return &PurchaseOrderService{
+		purchaseOrderRepo: purchaseOrderRepo,
+		supplierRepo:      supplierRepo,
+		supplyRepo:        supplyRepo,
+		ingredientRepo:    ingredientRepo,
+		txManager:         txManager,
+		events:            events,
+		logger:            logger.New("PurchaseOrderService"),
+	}
+}
+
+// Create заводит черновик заказа; цены без явного значения берутся из
+// каталога поставщика
+func (s *PurchaseOrderService) Create(ctx context.Context, po *domain.PurchaseOrder) error {
+	if !po.IsValid() {
+		return domain.ErrInvalidPurchaseOrder
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.prepareDraft(ctx, po); err != nil {
+			return err
+		}
+		po.Status = domain.PurchaseOrderDraft
+		return s.purchaseOrderRepo.Create(ctx, po)
+	})
+	if err != nil {
+		return err
+	}
+
+	po.CalculateTotal()
+	s.logger.Info("Purchase order #%d drafted for '%s': %d items, %.2f ₸",
+		po.ID, po.SupplierName, len(po.Items), po.Total)
+	return nil
+}
+
+// Update меняет черновик; отправленный заказ уже не правится
+func (s *PurchaseOrderService) Update(ctx context.Context, po *domain.PurchaseOrder) error {
+	if !po.IsValid() {
+		return domain.ErrInvalidPurchaseOrder
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.lock(ctx, po.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != domain.PurchaseOrderDraft {
+			return domain.ErrPurchaseOrderStatus
+		}
+
+		if err := s.prepareDraft(ctx, po); err != nil {
+			return err
+		}
+		po.Status = current.Status
+		po.CreatedBy = current.CreatedBy
+		po.CreatedAt = current.CreatedAt
+		return s.purchaseOrderRepo.Update(ctx, po)
+	})
+	if err != nil {
+		return err
+	}
+
+	po.CalculateTotal()
+	return nil
+}
+
+func (s *PurchaseOrderService) GetAll(ctx context.Context, filter domain.PurchaseOrderFilter) ([]domain.PurchaseOrder, error) {
+	orders, err := s.purchaseOrderRepo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].CalculateTotal()
+	}
+	return orders, nil
+}
+
+func (s *PurchaseOrderService) GetByID(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	po, err := s.purchaseOrderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if po == nil {
+		return nil, domain.ErrPurchaseOrderNotFound
+	}
+	po.CalculateTotal()
+	return po, nil
+}
+
+func (s *PurchaseOrderService) Send(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	return s.transition(ctx, id, domain.PurchaseOrderSent, func(po *domain.PurchaseOrder) bool {
+		return po.Status == domain.PurchaseOrderDraft
+	})
+}
+
+func (s *PurchaseOrderService) Cancel(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	return s.transition(ctx, id, domain.PurchaseOrderCancelled, (*domain.PurchaseOrder).CanCancel)
+}
+
+// Receive приходует поставку по заказу: каждая строка становится Supply с
+// номером накладной и увеличивает остаток, заказ переходит в
+// partially_received или received. Цена строки берётся из заказа, если в
+// накладной не указана другая.
+func (s *PurchaseOrderService) Receive(ctx context.Context, id int, receipt domain.Receipt, userID int) (*domain.PurchaseOrder, error) {
+	if len(receipt.Lines) == 0 {
+		return nil, domain.ErrInvalidReceipt
+	}
+	if receipt.Currency == "" {
+		receipt.Currency = domain.DefaultCurrency
+	}
+	if !domain.IsValidCurrency(receipt.Currency) {
+		return nil, domain.ErrInvalidCurrency
+	}
+
+	// Фиксированный порядок блокировок ингредиентов, как и при списании по заказам
+	lines := append([]domain.ReceiveLine(nil), receipt.Lines...)
+	sort.Slice(lines, func(i, j int) bool { return lines[i].IngredientID < lines[j].IngredientID })
+
+	var po *domain.PurchaseOrder
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		po, err = s.lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !po.CanReceive() {
+			return domain.ErrPurchaseOrderStatus
+		}
+
+		for i, line := range lines {
+			if line.Qty <= 0 || (line.UnitPrice != nil && *line.UnitPrice < 0) {
+				return domain.ErrInvalidReceipt
+			}
+			if i > 0 && lines[i-1].IngredientID == line.IngredientID {
+				return domain.ErrInvalidReceipt
+			}
+			item := po.Item(line.IngredientID)
+			if item == nil {
+				return domain.ErrInvalidReceipt
+			}
+
+			price := item.UnitPrice
+			if line.UnitPrice != nil {
+				price = line.UnitPrice
+			}
+			supply := &domain.Supply{
+				IngredientID:    line.IngredientID,
+				Qty:             line.Qty,
+				SupplierName:    po.SupplierName,
+				SupplierID:      &po.SupplierID,
+				PurchaseOrderID: &po.ID,
+				UnitPrice:       price,
+				Currency:        receipt.Currency,
+				InvoiceNumber:   receipt.InvoiceNumber,
+				ReceivedBy:      &userID,
+			}
+			if err := receiveSupply(ctx, s.supplyRepo, s.ingredientRepo, supply); err != nil {
+				return err
+			}
+			if err := s.purchaseOrderRepo.AddReceived(ctx, item.ID, line.Qty); err != nil {
+				return err
+			}
+			item.QtyReceived += line.Qty
+		}
+
+		po.Status = po.ReceivedStatus()
+		return s.purchaseOrderRepo.SetStatus(ctx, po)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	po.CalculateTotal()
+	s.events.Publish(domain.Event{Type: domain.EventStockChanged})
+	s.logger.Success("✓ Purchase order #%d: received %d lines from '%s', now %s",
+		po.ID, len(lines), po.SupplierName, po.Status)
+	return po, nil
+}
+
+func (s *PurchaseOrderService) transition(
+	ctx context.Context,
+	id int,
+	to domain.PurchaseOrderStatus,
+	allowed func(*domain.PurchaseOrder) bool,
+) (*domain.PurchaseOrder, error) {
+	var po *domain.PurchaseOrder
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		po, err = s.lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !allowed(po) {
+			return domain.ErrPurchaseOrderStatus
+		}
+
+		po.Status = to
+		return s.purchaseOrderRepo.SetStatus(ctx, po)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	po.CalculateTotal()
+	s.logger.Info("Purchase order #%d is now %s", po.ID, po.Status)
+	return po, nil
+}
+
+// prepareDraft проверяет поставщика и ингредиенты и подставляет цены из каталога
+func (s *PurchaseOrderService) prepareDraft(ctx context.Context, po *domain.PurchaseOrder) error {
+	supplier, err := s.supplierRepo.GetByID(ctx, po.SupplierID)
+	if err != nil {
+		return err
+	}
+	if supplier == nil {
+		return domain.ErrSupplierNotFound
+	}
+	if !supplier.IsActive {
+		return domain.ErrSupplierInactive
+	}
+	po.SupplierName = supplier.Name
+
+	for i := range po.Items {
+		item := &po.Items[i]
+		item.Ingredient, err = s.ingredientRepo.GetByID(ctx, item.IngredientID)
+		if err != nil {
+			return err
+		}
+		if item.Ingredient == nil {
+			return domain.ErrIngredientNotFound
+		}
+		item.QtyReceived = 0
+		if item.UnitPrice == nil {
+			if price, ok := supplier.CatalogPrice(item.IngredientID); ok {
+				item.UnitPrice = &price
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PurchaseOrderService) lock(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	po, err := s.purchaseOrderRepo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if po == nil {
+		return nil, domain.ErrPurchaseOrderNotFound
+	}
+	return po, nil
+}
@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+type ReservationService struct {
+	reservationRepo ports.ReservationRepository
+	tableRepo       ports.TableRepository
+	txManager       ports.TxManager
+	events          ports.EventPublisher
+	policy          domain.ReservationPolicy
+	logger          *logger.Logger
+}
+
+func NewReservationService(
+	reservationRepo ports.ReservationRepository,
+	tableRepo ports.TableRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+	policy domain.ReservationPolicy,
+) *ReservationService {
+	return &ReservationService{
+		reservationRepo: reservationRepo,
+		tableRepo:       tableRepo,
+		txManager:       txManager,
+		events:          events,
+		policy:          policy,
+		logger:          logger.New("ReservationService"),
+	}
+}
+
+func (s *ReservationService) GetAll(ctx context.Context, filter domain.ReservationFilter) ([]domain.Reservation, error) {
+	return s.reservationRepo.GetAll(ctx, filter)
+}
+
+func (s *ReservationService) GetByID(ctx context.Context, id int) (*domain.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, domain.ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// FindAvailable возвращает столы, свободные от броней на слот from..from+Duration
+// и вмещающие компанию; самые подходящие по размеру — первыми.
+func (s *ReservationService) FindAvailable(ctx context.Context, from time.Time, partySize int) ([]domain.Table, error) {
+	if partySize <= 0 {
+		return nil, domain.ErrInvalidReservation
+	}
+	return s.reservationRepo.GetAvailableTables(ctx, from, from.Add(s.policy.Duration), partySize)
+}
+
+func (s *ReservationService) Create(ctx context.Context, reservation *domain.Reservation) error {
+	if reservation.EndsAt.IsZero() {
+		reservation.EndsAt = reservation.StartsAt.Add(s.policy.Duration)
+	}
+	if !reservation.IsValid() {
+		return domain.ErrInvalidReservation
+	}
+	if reservation.StartsAt.Before(time.Now().Add(-s.policy.NoShowAfter)) {
+		return domain.ErrReservationInThePast
+	}
+
+	s.logger.Info("Booking table #%d for %s (%d guests) at %s",
+		reservation.TableID, reservation.GuestName, reservation.PartySize, reservation.StartsAt.Format("02.01 15:04"))
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkSlot(ctx, reservation); err != nil {
+			return err
+		}
+		reservation.Status = domain.ReservationBooked
+		return s.reservationRepo.Create(ctx, reservation)
+	})
+	if err != nil {
+		s.logger.Error("Failed to book table #%d: %v", reservation.TableID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Reservation #%d created", reservation.ID)
+	s.publishReservationEvent(reservation)
+
+	// Бронь на ближайшее время сразу держит стол
+	s.holdTables(ctx, time.Now())
+	return nil
+}
+
+// Update переносит бронь на другое время или стол; менять можно только ожидающую гостей бронь.
+func (s *ReservationService) Update(ctx context.Context, reservation *domain.Reservation) error {
+	if reservation.EndsAt.IsZero() {
+		reservation.EndsAt = reservation.StartsAt.Add(s.policy.Duration)
+	}
+	if !reservation.IsValid() {
+		return domain.ErrInvalidReservation
+	}
+
+	var previous *domain.Reservation
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		previous, err = s.getBooked(ctx, reservation.ID)
+		if err != nil {
+			return err
+		}
+		if !reservation.StartsAt.Equal(previous.StartsAt) && reservation.StartsAt.Before(time.Now()) {
+			return domain.ErrReservationInThePast
+		}
+		if err := s.checkSlot(ctx, reservation); err != nil {
+			return err
+		}
+		reservation.Status = previous.Status
+		reservation.CreatedBy = previous.CreatedBy
+		reservation.CreatedAt = previous.CreatedAt
+		return s.reservationRepo.Update(ctx, reservation)
+	})
+	if err != nil {
+		s.logger.Error("Failed to update reservation #%d: %v", reservation.ID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Reservation #%d moved to table #%d at %s",
+		reservation.ID, reservation.TableID, reservation.StartsAt.Format("02.01 15:04"))
+	s.publishReservationEvent(reservation)
+
+	// Стол, который держали под старый слот, может освободиться
+	if err := s.releaseTable(ctx, previous.TableID, time.Now()); err != nil {
+		s.logger.Error("Failed to release table #%d: %v", previous.TableID, err)
+	}
+	s.holdTables(ctx, time.Now())
+	return nil
+}
+
+func (s *ReservationService) Cancel(ctx context.Context, id int) error {
+	return s.finish(ctx, id, domain.ReservationCancelled)
+}
+
+// Seat отмечает, что гости пришли: бронь закрывается, стол занят.
+func (s *ReservationService) Seat(ctx context.Context, id int) error {
+	var reservation *domain.Reservation
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		reservation, err = s.getBooked(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.reservationRepo.UpdateStatus(ctx, id, domain.ReservationSeated); err != nil {
+			return err
+		}
+		return s.tableRepo.UpdateStatus(ctx, reservation.TableID, domain.TableBusy)
+	})
+	if err != nil {
+		s.logger.Error("Failed to seat reservation #%d: %v", id, err)
+		return err
+	}
+
+	reservation.Status = domain.ReservationSeated
+	s.logger.Success("✓ %s seated at table #%d", reservation.GuestName, reservation.TableID)
+	s.publishReservationEvent(reservation)
+	s.publishTableStatus(reservation.TableID, domain.TableBusy)
+	return nil
+}
+
+// Run раз в interval переводит столы в "reserve" перед бронью и снимает неявки,
+// пока ctx не отменён.
+func (s *ReservationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.ProcessSchedule(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessSchedule отмечает неявки и держит столы под ближайшие брони на момент now.
+func (s *ReservationService) ProcessSchedule(ctx context.Context, now time.Time) {
+	s.releaseNoShows(ctx, now)
+	s.holdTables(ctx, now)
+}
+
+// releaseNoShows переводит брони, гости которых опоздали больше NoShowAfter, в no_show
+func (s *ReservationService) releaseNoShows(ctx context.Context, now time.Time) {
+	overdue, err := s.reservationRepo.GetBookedBefore(ctx, now.Add(-s.policy.NoShowAfter))
+	if err != nil {
+		s.logger.Error("Failed to get overdue reservations: %v", err)
+		return
+	}
+
+	for _, reservation := range overdue {
+		s.logger.Warning("Reservation #%d (%s) is a no-show", reservation.ID, reservation.GuestName)
+		if err := s.finish(ctx, reservation.ID, domain.ReservationNoShow); err != nil {
+			s.logger.Error("Failed to release no-show reservation #%d: %v", reservation.ID, err)
+		}
+	}
+}
+
+// holdTables переводит свободные столы в "reserve", если до брони осталось меньше HoldBefore
+func (s *ReservationService) holdTables(ctx context.Context, now time.Time) {
+	upcoming, err := s.reservationRepo.GetBookedBefore(ctx, now.Add(s.policy.HoldBefore))
+	if err != nil {
+		s.logger.Error("Failed to get upcoming reservations: %v", err)
+		return
+	}
+
+	for _, reservation := range upcoming {
+		held := false
+		err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			table, err := s.tableRepo.GetByIDForUpdate(ctx, reservation.TableID)
+			if err != nil || table == nil || table.Status != domain.TableFree {
+				return err
+			}
+			held = true
+			return s.tableRepo.UpdateStatus(ctx, table.ID, domain.TableReserve)
+		})
+		if err != nil {
+			s.logger.Error("Failed to hold table #%d: %v", reservation.TableID, err)
+			continue
+		}
+		if held {
+			s.logger.Info("Table #%d reserved for %s at %s",
+				reservation.TableID, reservation.GuestName, reservation.StartsAt.Format("15:04"))
+			s.publishTableStatus(reservation.TableID, domain.TableReserve)
+		}
+	}
+}
+
+// finish закрывает ожидающую бронь с итоговым статусом и освобождает стол, если его держали под неё
+func (s *ReservationService) finish(ctx context.Context, id int, status domain.ReservationStatus) error {
+	var reservation *domain.Reservation
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		reservation, err = s.getBooked(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.reservationRepo.UpdateStatus(ctx, id, status)
+	})
+	if err != nil {
+		return err
+	}
+
+	reservation.Status = status
+	s.logger.Info("Reservation #%d is %s", id, status)
+	s.publishReservationEvent(reservation)
+
+	return s.releaseTable(ctx, reservation.TableID, time.Now())
+}
+
+// releaseTable освобождает стол в статусе "reserve", если его больше не держит ни одна бронь.
+// Занятые столы не трогаем — их освобождает закрытие заказа.
+func (s *ReservationService) releaseTable(ctx context.Context, tableID int, now time.Time) error {
+	released := false
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		table, err := s.tableRepo.GetByIDForUpdate(ctx, tableID)
+		if err != nil || table == nil || table.Status != domain.TableReserve {
+			return err
+		}
+
+		holding, err := s.reservationRepo.GetConflicts(ctx, tableID, now.Add(-s.policy.NoShowAfter), now.Add(s.policy.HoldBefore), 0)
+		if err != nil {
+			return err
+		}
+		for _, reservation := range holding {
+			if reservation.Status == domain.ReservationBooked {
+				return nil
+			}
+		}
+
+		released = true
+		return s.tableRepo.UpdateStatus(ctx, tableID, domain.TableFree)
+	})
+	if err != nil {
+		return err
+	}
+
+	if released {
+		s.logger.Info("Table #%d released", tableID)
+		s.publishTableStatus(tableID, domain.TableFree)
+	}
+	return nil
+}
+
+// checkSlot проверяет стол и пересечения с другими бронями; строка стола блокируется,
+// чтобы две параллельные брони не заняли один слот.
+func (s *ReservationService) checkSlot(ctx context.Context, reservation *domain.Reservation) error {
+	table, err := s.tableRepo.GetByIDForUpdate(ctx, reservation.TableID)
+	if err != nil {
+		return err
+	}
+	if table == nil {
+		return domain.ErrTableNotFound
+	}
+	if table.Capacity < reservation.PartySize {
+		return domain.ErrTableTooSmall
+	}
+
+	conflicts, err := s.reservationRepo.GetConflicts(ctx, reservation.TableID, reservation.StartsAt, reservation.EndsAt, reservation.ID)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		s.logger.Warning("Table #%d is already booked by reservation #%d", reservation.TableID, conflicts[0].ID)
+		return domain.ErrReservationConflict
+	}
+	return nil
+}
+
+func (s *ReservationService) getBooked(ctx context.Context, id int) (*domain.Reservation, error) {
+	reservation, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != domain.ReservationBooked {
+		return nil, domain.ErrReservationNotBooked
+	}
+	return reservation, nil
+}
+
+func (s *ReservationService) publishReservationEvent(reservation *domain.Reservation) {
+	s.events.Publish(domain.Event{
+		Type:    domain.EventReservationChanged,
+		TableID: reservation.TableID,
+		Status:  string(reservation.Status),
+		Message: reservation.GuestName,
+	})
+}
+
+func (s *ReservationService) publishTableStatus(tableID int, status domain.TableStatus) {
+	s.events.Publish(domain.Event{
+		Type:    domain.EventTableStatusChanged,
+		TableID: tableID,
+		Status:  string(status),
+	})
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+// sectionGuard проверяет, что официант работает только со своими столами.
+// Менеджер и админ могут действовать за любого; вызовы без пользователя
+// (фоновые задачи) и столы вне секций не ограничиваются.
+type sectionGuard struct {
+	sectionRepo ports.SectionRepository
+}
+
+func (g sectionGuard) restrictedActor(ctx context.Context) (domain.Actor, bool) {
+	actor, ok := domain.ActorFrom(ctx)
+	if !ok || actor.CanOverride() || actor.Role != domain.RoleWaiter {
+		return actor, false
+	}
+	return actor, true
+}
+
+func (g sectionGuard) checkTable(ctx context.Context, tableID int) error {
+	actor, restricted := g.restrictedActor(ctx)
+	if !restricted {
+		return nil
+	}
+
+	waiterID, err := g.sectionRepo.GetTableWaiter(ctx, domain.ShiftDate(time.Now()), tableID)
+	if err != nil {
+		return err
+	}
+	if waiterID != nil && *waiterID != actor.UserID {
+		return domain.ErrNotYourTable
+	}
+	return nil
+}
+
+// checkOrder пускает к заказу официанта, который его открыл, и того, в чьей секции стол
+func (g sectionGuard) checkOrder(ctx context.Context, order *domain.Order) error {
+	actor, restricted := g.restrictedActor(ctx)
+	if !restricted || order.WaiterID == actor.UserID {
+		return nil
+	}
+
+	waiterID, err := g.sectionRepo.GetTableWaiter(ctx, domain.ShiftDate(time.Now()), order.TableNumber)
+	if err != nil {
+		return err
+	}
+	if waiterID == nil || *waiterID != actor.UserID {
+		return domain.ErrNotYourOrder
+	}
+	return nil
+}
+
+type SectionService struct {
+	sectionRepo ports.SectionRepository
+	tableRepo   ports.TableRepository
+	userRepo    ports.UserRepository
+	orderRepo   ports.OrderRepository
+	txManager   ports.TxManager
+	logger      *logger.Logger
+}
+
+func NewSectionService(
+	sectionRepo ports.SectionRepository,
+	tableRepo ports.TableRepository,
+	userRepo ports.UserRepository,
+	orderRepo ports.OrderRepository,
+	txManager ports.TxManager,
+) *SectionService {
+	return &SectionService{
+		sectionRepo: sectionRepo,
+		tableRepo:   tableRepo,
+		userRepo:    userRepo,
+		orderRepo:   orderRepo,
+		txManager:   txManager,
+		logger:      logger.New("SectionService"),
+	}
+}
+
+func (s *SectionService) GetSections(ctx context.Context, shiftDate time.Time) ([]domain.Section, error) {
+	return s.sectionRepo.GetByDate(ctx, domain.ShiftDate(shiftDate))
+}
+
+// Assign задаёт секцию официанта на смену целиком. Столы, которые были
+// в чужой секции, переходят к нему.
+func (s *SectionService) Assign(ctx context.Context, section *domain.Section, assignedBy int) error {
+	section.ShiftDate = domain.ShiftDate(section.ShiftDate)
+	s.logger.Info("Assigning tables %v to waiter #%d for %s",
+		section.TableIDs, section.WaiterID, section.ShiftDate.Format("02.01.2006"))
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		waiter, err := s.getWaiter(ctx, section.WaiterID)
+		if err != nil {
+			return err
+		}
+		if waiter == nil {
+			return domain.ErrInvalidSection
+		}
+		section.WaiterName = waiter.Username
+
+		seen := make(map[int]bool, len(section.TableIDs))
+		for _, tableID := range section.TableIDs {
+			if seen[tableID] {
+				return domain.ErrInvalidSection
+			}
+			seen[tableID] = true
+
+			table, err := s.tableRepo.GetByID(ctx, tableID)
+			if err != nil {
+				return err
+			}
+			if table == nil {
+				return domain.ErrTableNotFound
+			}
+		}
+
+		return s.sectionRepo.Assign(ctx, section.ShiftDate, section.WaiterID, section.TableIDs, &assignedBy)
+	})
+	if err != nil {
+		s.logger.Error("Failed to assign section of waiter #%d: %v", section.WaiterID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Waiter %s serves %d table(s)", section.WaiterName, len(section.TableIDs))
+	return nil
+}
+
+// Handover передаёт столы и открытые заказы другому официанту при пересменке.
+// Официант может передать только свои столы, менеджер — чьи угодно.
+func (s *SectionService) Handover(ctx context.Context, handover *domain.Handover) error {
+	if handover.FromWaiterID == handover.ToWaiterID {
+		return domain.ErrInvalidHandover
+	}
+	if actor, ok := domain.ActorFrom(ctx); ok && !actor.CanOverride() && actor.UserID != handover.FromWaiterID {
+		return domain.ErrHandoverNotAllowed
+	}
+
+	s.logger.Info("Handing over tables of waiter #%d to waiter #%d", handover.FromWaiterID, handover.ToWaiterID)
+
+	shiftDate := domain.ShiftDate(time.Now())
+	var moved int
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		waiter, err := s.getWaiter(ctx, handover.ToWaiterID)
+		if err != nil {
+			return err
+		}
+		if waiter == nil {
+			return domain.ErrInvalidHandover
+		}
+
+		if len(handover.TableIDs) == 0 {
+			if err := s.sectionRepo.Reassign(ctx, shiftDate, handover.FromWaiterID, handover.ToWaiterID, nil); err != nil {
+				return err
+			}
+			moved, err = s.orderRepo.ReassignOpen(ctx, handover.FromWaiterID, handover.ToWaiterID, nil)
+			return err
+		}
+
+		for _, tableID := range handover.TableIDs {
+			tableID := tableID
+			if err := s.sectionRepo.Reassign(ctx, shiftDate, handover.FromWaiterID, handover.ToWaiterID, &tableID); err != nil {
+				return err
+			}
+			n, err := s.orderRepo.ReassignOpen(ctx, handover.FromWaiterID, handover.ToWaiterID, &tableID)
+			if err != nil {
+				return err
+			}
+			moved += n
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to hand over tables of waiter #%d: %v", handover.FromWaiterID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Waiter #%d handed over %d open order(s) to waiter #%d",
+		handover.FromWaiterID, moved, handover.ToWaiterID)
+	return nil
+}
+
+// getWaiter возвращает активного официанта или nil
+func (s *SectionService) getWaiter(ctx context.Context, id int) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive || user.Role != domain.RoleWaiter {
+		return nil, nil
+	}
+	return user, nil
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+type StocktakeService struct {
+	stocktakeRepo  ports.StocktakeRepository
+	ingredientRepo ports.IngredientRepository
+	txManager      ports.TxManager
+	events         ports.EventPublisher
+	logger         *logger.Logger
+}
+
+func NewStocktakeService(
+	stocktakeRepo ports.StocktakeRepository,
+	ingredientRepo ports.IngredientRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+) *StocktakeService {
+	return &StocktakeService{
+		stocktakeRepo:  stocktakeRepo,
+		ingredientRepo: ingredientRepo,
+		txManager:      txManager,
+		events:         events,
+		logger:         logger.New("StocktakeService"),
+	}
+}
+
+// Start открывает инвентаризацию; одновременно может быть открыта только одна
+func (s *StocktakeService) Start(ctx context.Context, note *string, userID int) (*domain.Stocktake, error) {
+	stocktake := &domain.Stocktake{Note: note, Status: domain.StocktakeOpen, StartedBy: &userID}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		open, err := s.stocktakeRepo.GetOpen(ctx)
+		if err != nil {
+			return err
+		}
+		if open != nil {
+			return domain.ErrStocktakeOpen
+		}
+		return s.stocktakeRepo.Create(ctx, stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Stocktake #%d started by user %d", stocktake.ID, userID)
+	return stocktake, nil
+}
+
+func (s *StocktakeService) GetAll(ctx context.Context) ([]domain.Stocktake, error) {
+	return s.stocktakeRepo.GetAll(ctx)
+}
+
+// GetByID возвращает инвентаризацию с подсчётами и расхождениями от остатка
+// на момент подсчёта
+func (s *StocktakeService) GetByID(ctx context.Context, id int) (*domain.Stocktake, error) {
+	stocktake, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake == nil {
+		return nil, domain.ErrStocktakeNotFound
+	}
+	stocktake.Summarize()
+	return stocktake, nil
+}
+
+// SaveCounts вносит подсчитанные остатки вместе с учётным остатком и
+// себестоимостью на этот момент; повторный подсчёт ингредиента заменяет
+// предыдущий, так что считать могут несколько человек по очереди
+func (s *StocktakeService) SaveCounts(ctx context.Context, id int, counts []domain.StocktakeLine, userID int) (*domain.Stocktake, error) {
+	for _, c := range counts {
+		if c.CountedQty < 0 {
+			return nil, domain.ErrInvalidStocktakeCount
+		}
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOpen(ctx, id); err != nil {
+			return err
+		}
+
+		for i := range counts {
+			c := &counts[i]
+			ingredient, err := s.ingredientRepo.GetByID(ctx, c.IngredientID)
+			if err != nil {
+				return err
+			}
+			if ingredient == nil {
+				return domain.ErrIngredientNotFound
+			}
+
+			c.StocktakeID = id
+			c.CountedBy = &userID
+			c.SystemQty = ingredient.Qty
+			c.UnitCost = ingredient.UnitCost
+			if err := s.stocktakeRepo.SaveCount(ctx, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *StocktakeService) RemoveCount(ctx context.Context, id, ingredientID int) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOpen(ctx, id); err != nil {
+			return err
+		}
+		return s.stocktakeRepo.DeleteCount(ctx, id, ingredientID)
+	})
+}
+
+// Post проводит инвентаризацию одной транзакцией: пишет в журнал расхождения
+// «подсчитано − учётный остаток на момент подсчёта» корректировками со ссылкой
+// на инвентаризацию и закрывает её. Приходы и расходы после подсчёта остаются
+// в остатке, а не гасятся корректировкой.
+func (s *StocktakeService) Post(ctx context.Context, id int, userID int) (*domain.Stocktake, error) {
+	var stocktake *domain.Stocktake
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		stocktake, err = s.lockOpen(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Блокируем ингредиенты в порядке id, как и при списании по заказам
+		sort.Slice(stocktake.Lines, func(i, j int) bool {
+			return stocktake.Lines[i].IngredientID < stocktake.Lines[j].IngredientID
+		})
+
+		note := fmt.Sprintf("stocktake #%d", id)
+		for i := range stocktake.Lines {
+			l := &stocktake.Lines[i]
+			ingredient, err := s.ingredientRepo.GetByIDForUpdate(ctx, l.IngredientID)
+			if err != nil {
+				return err
+			}
+			if ingredient == nil {
+				return domain.ErrIngredientNotFound
+			}
+
+			// Если после подсчёта успели списать больше, чем осталось, остаток
+			// не уходит в минус
+			delta := roundMoney(l.CountedQty - l.SystemQty)
+			if ingredient.Qty+delta < 0 {
+				delta = -ingredient.Qty
+			}
+			if delta == 0 {
+				continue
+			}
+
+			m := &domain.StockMovement{
+				IngredientID: l.IngredientID,
+				Type:         domain.MovementAdjustment,
+				Qty:          delta,
+				RefID:        &id,
+				UserID:       &userID,
+				Note:         &note,
+			}
+			if err := s.ingredientRepo.ApplyMovement(ctx, m); err != nil {
+				return err
+			}
+		}
+
+		stocktake.Status = domain.StocktakePosted
+		stocktake.ClosedBy = &userID
+		return s.stocktakeRepo.Close(ctx, stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stocktake.Summarize()
+	s.events.Publish(domain.Event{Type: domain.EventStockChanged})
+	s.logger.Success("✓ Stocktake #%d posted: %d counted, %d adjusted, variance %.2f ₸",
+		id, stocktake.CountedItems, stocktake.VarianceItems, stocktake.VarianceCost)
+	return stocktake, nil
+}
+
+// Cancel закрывает инвентаризацию без изменения остатков
+func (s *StocktakeService) Cancel(ctx context.Context, id int, userID int) (*domain.Stocktake, error) {
+	var stocktake *domain.Stocktake
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		stocktake, err = s.lockOpen(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		stocktake.Status = domain.StocktakeCancelled
+		stocktake.ClosedBy = &userID
+		return s.stocktakeRepo.Close(ctx, stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stocktake.Summarize()
+	s.logger.Info("Stocktake #%d cancelled by user %d", id, userID)
+	return stocktake, nil
+}
+
+func (s *StocktakeService) lockOpen(ctx context.Context, id int) (*domain.Stocktake, error) {
+	stocktake, err := s.stocktakeRepo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake == nil {
+		return nil, domain.ErrStocktakeNotFound
+	}
+	if stocktake.Status != domain.StocktakeOpen {
+		return nil, domain.ErrStocktakeClosed
+	}
+	return stocktake, nil
+}
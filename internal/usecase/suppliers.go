@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+)
+
+type SupplierService struct {
+	supplierRepo   ports.SupplierRepository
+	ingredientRepo ports.IngredientRepository
+}
+
+func NewSupplierService(supplierRepo ports.SupplierRepository, ingredientRepo ports.IngredientRepository) *SupplierService {
+	return &SupplierService{supplierRepo: supplierRepo, ingredientRepo: ingredientRepo}
+}
+
+func (s *SupplierService) GetAll(ctx context.Context, activeOnly bool) ([]domain.Supplier, error) {
+	return s.supplierRepo.GetAll(ctx, activeOnly)
+}
+
+func (s *SupplierService) GetByID(ctx context.Context, id int) (*domain.Supplier, error) {
+	supplier, err := s.supplierRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, domain.ErrSupplierNotFound
+	}
+	return supplier, nil
+}
+
+func (s *SupplierService) Create(ctx context.Context, supplier *domain.Supplier) error {
+	if !supplier.IsValid() {
+		return domain.ErrInvalidSupplier
+	}
+	supplier.IsActive = true
+	return s.supplierRepo.Create(ctx, supplier)
+}
+
+func (s *SupplierService) Update(ctx context.Context, supplier *domain.Supplier) error {
+	if !supplier.IsValid() {
+		return domain.ErrInvalidSupplier
+	}
+	if _, err := s.GetByID(ctx, supplier.ID); err != nil {
+		return err
+	}
+	return s.supplierRepo.Update(ctx, supplier)
+}
+
+// Delete деактивирует поставщика: история поставок и заказов остаётся
+func (s *SupplierService) Delete(ctx context.Context, id int) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.supplierRepo.Delete(ctx, id)
+}
+
+// SaveCatalogItem добавляет ингредиент в каталог поставщика или меняет его цену
+func (s *SupplierService) SaveCatalogItem(ctx context.Context, item *domain.SupplierIngredient) error {
+	if !item.IsValid() {
+		return domain.ErrInvalidSupplier
+	}
+	if _, err := s.GetByID(ctx, item.SupplierID); err != nil {
+		return err
+	}
+
+	ingredient, err := s.ingredientRepo.GetByID(ctx, item.IngredientID)
+	if err != nil {
+		return err
+	}
+	if ingredient == nil {
+		return domain.ErrIngredientNotFound
+	}
+
+	if err := s.supplierRepo.SaveCatalogItem(ctx, item); err != nil {
+		return err
+	}
+	item.Ingredient = ingredient
+	return nil
+}
+
+func (s *SupplierService) RemoveCatalogItem(ctx context.Context, supplierID, ingredientID int) error {
+	if _, err := s.GetByID(ctx, supplierID); err != nil {
+		return err
+	}
+	return s.supplierRepo.DeleteCatalogItem(ctx, supplierID, ingredientID)
+}
@@ -8,15 +8,100 @@ import (
 )
 
 type SupplyService struct {
-	supplyRepo ports.SupplyRepository
+	supplyRepo     ports.SupplyRepository
+	supplierRepo   ports.SupplierRepository
+	ingredientRepo ports.IngredientRepository
+	txManager      ports.TxManager
+	events         ports.EventPublisher
 }
 
-func NewSupplyService(supplyRepo ports.SupplyRepository) *SupplyService {
-	return &SupplyService{supplyRepo: supplyRepo}
+func NewSupplyService(
+	supplyRepo ports.SupplyRepository,
+	supplierRepo ports.SupplierRepository,
+	ingredientRepo ports.IngredientRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+) *SupplyService {
+	return &SupplyService{
+		supplyRepo:     supplyRepo,
+		supplierRepo:   supplierRepo,
+		ingredientRepo: ingredientRepo,
+		txManager:      txManager,
+		events:         events,
+	}
 }
 
+// Create приходует поставку вне заказа поставщику. Если указан supplier_id,
+// имя поставщика берётся из справочника.
 func (s *SupplyService) Create(ctx context.Context, supply *domain.Supply) error {
-	return s.supplyRepo.Create(ctx, supply)
+	if supply.Currency == "" {
+		supply.Currency = domain.DefaultCurrency
+	}
+	if !domain.IsValidCurrency(supply.Currency) {
+		return domain.ErrInvalidCurrency
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if supply.SupplierID != nil {
+			supplier, err := s.supplierRepo.GetByID(ctx, *supply.SupplierID)
+			if err != nil {
+				return err
+			}
+			if supplier == nil {
+				return domain.ErrSupplierNotFound
+			}
+			supply.SupplierName = supplier.Name
+		}
+		return receiveSupply(ctx, s.supplyRepo, s.ingredientRepo, supply)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Publish(domain.Event{Type: domain.EventStockChanged})
+	return nil
+}
+
+// receiveSupply приходует поставку внутри транзакции: остаток растёт через
+// журнал движений, поставка с ценой сдвигает среднюю себестоимость ингредиента
+func receiveSupply(
+	ctx context.Context,
+	supplyRepo ports.SupplyRepository,
+	ingredientRepo ports.IngredientRepository,
+	supply *domain.Supply,
+) error {
+	ingredient, err := ingredientRepo.GetByIDForUpdate(ctx, supply.IngredientID)
+	if err != nil {
+		return err
+	}
+	if ingredient == nil {
+		return domain.ErrIngredientNotFound
+	}
+
+	if err := supplyRepo.Create(ctx, supply); err != nil {
+		return err
+	}
+
+	m := &domain.StockMovement{
+		IngredientID: supply.IngredientID,
+		Type:         domain.MovementSupply,
+		Qty:          supply.Qty,
+		RefID:        &supply.ID,
+		UserID:       supply.ReceivedBy,
+		Note:         &supply.SupplierName,
+	}
+	if err := ingredientRepo.ApplyMovement(ctx, m); err != nil {
+		return err
+	}
+
+	// Себестоимость ведётся в валюте меню; цены в другой валюте идут только в отчёты
+	if supply.UnitPrice != nil && supply.Currency == domain.DefaultCurrency {
+		ingredient.Receive(supply.Qty, *supply.UnitPrice)
+		if err := ingredientRepo.SetUnitCost(ctx, ingredient.ID, ingredient.UnitCost); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *SupplyService) GetAll(ctx context.Context) ([]domain.Supply, error) {
@@ -26,3 +111,27 @@ func (s *SupplyService) GetAll(ctx context.Context) ([]domain.Supply, error) {
 func (s *SupplyService) GetByIngredientID(ctx context.Context, ingredientID int) ([]domain.Supply, error) {
 	return s.supplyRepo.GetByIngredientID(ctx, ingredientID)
 }
+
+func (s *SupplyService) GetByID(ctx context.Context, id int) (*domain.Supply, error) {
+	supply, err := s.supplyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if supply == nil {
+		return nil, domain.ErrSupplyNotFound
+	}
+	return supply, nil
+}
+
+// AttachInvoice сохраняет ссылку на загруженную накладную поставки
+func (s *SupplyService) AttachInvoice(ctx context.Context, id int, url string) (*domain.Supply, error) {
+	supply, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.supplyRepo.SetInvoiceURL(ctx, id, url); err != nil {
+		return nil, err
+	}
+	supply.InvoiceURL = &url
+	return supply, nil
+}
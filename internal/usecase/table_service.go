@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
 	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
@@ -9,10 +11,23 @@ import (
 
 type TableService struct {
 	tableRepo ports.TableRepository
+	txManager ports.TxManager
+	events    ports.EventPublisher
+	sections  sectionGuard
 }
 
-func NewTableService(tableRepo ports.TableRepository) *TableService {
-	return &TableService{tableRepo: tableRepo}
+func NewTableService(
+	tableRepo ports.TableRepository,
+	sectionRepo ports.SectionRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+) *TableService {
+	return &TableService{
+		tableRepo: tableRepo,
+		txManager: txManager,
+		events:    events,
+		sections:  sectionGuard{sectionRepo: sectionRepo},
+	}
 }
 
 func (s *TableService) GetAll(ctx context.Context) ([]domain.Table, error) {
@@ -23,15 +38,97 @@ func (s *TableService) GetByID(ctx context.Context, id int) (*domain.Table, erro
 	return s.tableRepo.GetByID(ctx, id)
 }
 
+// GetFloor возвращает план зала: столы с текущим заказом, официантом и временем с посадки
+func (s *TableService) GetFloor(ctx context.Context) ([]domain.FloorTable, error) {
+	floor, err := s.tableRepo.GetFloor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range floor {
+		if floor[i].SeatedAt != nil {
+			floor[i].ElapsedMinutes = int(now.Sub(*floor[i].SeatedAt).Minutes())
+		}
+	}
+	return floor, nil
+}
+
 func (s *TableService) Create(ctx context.Context, table *domain.Table) error {
 	table.Status = domain.TableFree
+	if table.Capacity <= 0 {
+		table.Capacity = domain.DefaultTableCapacity
+	}
+	if !isValidTable(table) {
+		return domain.ErrInvalidTable
+	}
 	return s.tableRepo.Create(ctx, table)
 }
 
+// Update меняет название, вместимость и расположение стола; статус не трогает
+func (s *TableService) Update(ctx context.Context, table *domain.Table) error {
+	if !isValidTable(table) || table.Capacity <= 0 {
+		return domain.ErrInvalidTable
+	}
+
+	existing, err := s.tableRepo.GetByID(ctx, table.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return domain.ErrTableNotFound
+	}
+
+	table.Status = existing.Status
+	return s.tableRepo.Update(ctx, table)
+}
+
+// UpdateLayout сохраняет расстановку столов целиком: либо все, либо ни одного
+func (s *TableService) UpdateLayout(ctx context.Context, positions []domain.TablePosition) error {
+	for i := range positions {
+		if !positions[i].IsValid() {
+			return domain.ErrInvalidTable
+		}
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for _, p := range positions {
+			table, err := s.tableRepo.GetByID(ctx, p.ID)
+			if err != nil {
+				return err
+			}
+			if table == nil {
+				return domain.ErrTableNotFound
+			}
+			if err := s.tableRepo.UpdateLayout(ctx, p.ID, p.TableLayout); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (s *TableService) UpdateStatus(ctx context.Context, id int, status domain.TableStatus) error {
-	return s.tableRepo.UpdateStatus(ctx, id, status)
+	if err := s.sections.checkTable(ctx, id); err != nil {
+		return err
+	}
+	if err := s.tableRepo.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+
+	s.events.Publish(domain.Event{
+		Type:    domain.EventTableStatusChanged,
+		TableID: id,
+		Status:  string(status),
+	})
+	return nil
 }
 
 func (s *TableService) Delete(ctx context.Context, id int) error {
 	return s.tableRepo.Delete(ctx, id)
 }
+
+func isValidTable(table *domain.Table) bool {
+	table.Name = strings.TrimSpace(table.Name)
+	return table.Name != "" && table.TableLayout.IsValid()
+}
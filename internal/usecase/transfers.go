@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+// MoveOrder пересаживает гостей: заказ переходит на свободный стол,
+// старый стол освобождается, новый становится занятым.
+func (s *OrderService) MoveOrder(ctx context.Context, orderID, tableID int) error {
+	s.logger.Order("Moving order #%d to table #%d", orderID, tableID)
+
+	var order *domain.Order
+	var fromTable int
+	var freed bool
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.Lock(ctx, orderID); err != nil {
+			return err
+		}
+		var err error
+		order, err = s.getEditableOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		fromTable = order.TableNumber
+		if fromTable == tableID {
+			return nil
+		}
+
+		table, err := s.tableRepo.GetByIDForUpdate(ctx, tableID)
+		if err != nil {
+			return err
+		}
+		if table == nil {
+			return domain.ErrTableNotFound
+		}
+		if err := s.sections.checkTable(ctx, tableID); err != nil {
+			return err
+		}
+		if table.Status != domain.TableFree {
+			s.logger.Error("Table #%d is %s", tableID, table.Status)
+			return domain.ErrTableBusy
+		}
+
+		order.TableNumber = tableID
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			s.logger.Error("Failed to move order #%d: %v", orderID, err)
+			return err
+		}
+		if err := s.tableRepo.UpdateStatus(ctx, tableID, domain.TableBusy); err != nil {
+			return err
+		}
+
+		freed, err = freeTableIfEmpty(ctx, s.orderRepo, s.tableRepo, s.logger, fromTable)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to move order #%d: %v", orderID, err)
+		return err
+	}
+	if fromTable == tableID {
+		return nil
+	}
+
+	s.logger.Success("✓ Order #%d moved: table #%d → table #%d", orderID, fromTable, tableID)
+
+	event := newOrderEvent(domain.EventOrderMoved, order)
+	event.Message = fmt.Sprintf("moved from table #%d", fromTable)
+	s.events.Publish(event)
+	s.publishTableStatus(tableID, domain.TableBusy)
+	if freed {
+		s.publishTableStatus(fromTable, domain.TableFree)
+	}
+	return nil
+}
+
+// MergeOrders сводит заказы (или открытые заказы столов) в один счёт targetID:
+// позиции, comp и закрытые void переезжают в него, остальные заказы получают
+// статус merged, а их столы освобождаются. Склад выравнивается так, чтобы
+// все позиции объединённого заказа были в одном состоянии: если хотя бы часть
+// уже списана кухней, списывается и остальное.
+func (s *OrderService) MergeOrders(ctx context.Context, targetID int, req domain.MergeRequest) error {
+	s.logger.Order("Merging orders %v and tables %v into order #%d", req.OrderIDs, req.TableIDs, targetID)
+
+	var target *domain.Order
+	var sources []*domain.Order
+	var freedTables []int
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		sourceIDs, err := s.mergeSources(ctx, targetID, req)
+		if err != nil {
+			return err
+		}
+
+		// Блокируем заказы по возрастанию ID, чтобы параллельные слияния не ловили deadlock
+		locked := append([]int{targetID}, sourceIDs...)
+		sort.Ints(locked)
+		for _, id := range locked {
+			if err := s.orderRepo.Lock(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		target, err = s.getMergeableOrder(ctx, targetID)
+		if err != nil {
+			return err
+		}
+
+		sources = nil
+		for _, id := range sourceIDs {
+			source, err := s.getMergeableOrder(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := s.alignStock(ctx, target, source); err != nil {
+				return err
+			}
+
+			if err := s.promotionRepo.DeletePromotionDiscounts(ctx, source.ID); err != nil {
+				return err
+			}
+			if err := s.paymentRepo.DeleteSplits(ctx, source.ID); err != nil {
+				return err
+			}
+			if err := s.orderRepo.MergeInto(ctx, source.ID, target.ID); err != nil {
+				s.logger.Error("Failed to move items of order #%d: %v", source.ID, err)
+				return err
+			}
+			// Если оба заказа ещё new, резерв переходит вместе с позициями
+			if err := s.ingredientRepo.MoveReservations(ctx, source.ID, target.ID); err != nil {
+				return err
+			}
+			if err := s.orderRepo.UpdateStatus(ctx, source.ID, domain.OrderMerged); err != nil {
+				return err
+			}
+			source.Status = domain.OrderMerged
+
+			if source.Guests != nil {
+				guests := *source.Guests
+				if target.Guests != nil {
+					guests += *target.Guests
+				}
+				target.Guests = &guests
+			}
+			sources = append(sources, source)
+		}
+
+		for _, source := range sources {
+			if source.TableNumber == target.TableNumber {
+				continue
+			}
+			freed, err := freeTableIfEmpty(ctx, s.orderRepo, s.tableRepo, s.logger, source.TableNumber)
+			if err != nil {
+				return err
+			}
+			if freed {
+				freedTables = append(freedTables, source.TableNumber)
+			}
+		}
+
+		target.ServiceChargeRate = math.Max(target.ServiceChargeRate, s.serviceCharge.RateFor(target.Guests))
+
+		if err := s.syncOrderStatus(ctx, target); err != nil {
+			return err
+		}
+		return s.recalculateTotal(ctx, target)
+	})
+	if err != nil {
+		s.logger.Error("Failed to merge into order #%d: %v", targetID, err)
+		return err
+	}
+
+	s.logger.Success("✓ %d order(s) merged into order #%d (Total: %.2f ₸)", len(sources), target.ID, target.Total)
+
+	for _, source := range sources {
+		event := newOrderEvent(domain.EventOrderMoved, source)
+		event.Message = fmt.Sprintf("merged into order #%d", target.ID)
+		s.events.Publish(event)
+	}
+	s.notifyKitchen(target, fmt.Sprintf("merged %d order(s) from other tables", len(sources)))
+	for _, tableID := range freedTables {
+		s.publishTableStatus(tableID, domain.TableFree)
+	}
+	return nil
+}
+
+// mergeSources собирает ID заказов для слияния: явно переданные и открытые заказы столов
+func (s *OrderService) mergeSources(ctx context.Context, targetID int, req domain.MergeRequest) ([]int, error) {
+	seen := map[int]bool{targetID: true}
+	var ids []int
+	add := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range req.OrderIDs {
+		add(id)
+	}
+	for _, tableID := range req.TableIDs {
+		open, err := s.orderRepo.GetOpenIDsByTable(ctx, tableID)
+		if err != nil {
+			return nil, err
+		}
+		if len(open) == 0 {
+			return nil, domain.ErrNoOpenOrder
+		}
+		for _, id := range open {
+			add(id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, domain.ErrInvalidMerge
+	}
+	return ids, nil
+}
+
+// getMergeableOrder возвращает открытый заказ без оплат и ожидающих void
+func (s *OrderService) getMergeableOrder(ctx context.Context, orderID int) (*domain.Order, error) {
+	order, err := s.getEditableOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	paid, err := s.paymentRepo.GetPaidTotal(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if paid > 0 {
+		return nil, domain.ErrBillHasPayments
+	}
+
+	voids, err := s.voidRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range voids {
+		if v.Status == domain.VoidPending {
+			return nil, domain.ErrHasPendingVoids
+		}
+	}
+	return order, nil
+}
+
+// alignStock приводит склад двух заказов к одному состоянию перед слиянием.
+// Заказ new держит только резерв, начатый — уже списал ингредиенты.
+func (s *OrderService) alignStock(ctx context.Context, target, source *domain.Order) error {
+	targetConsumed := target.Status != domain.OrderNew
+	sourceConsumed := source.Status != domain.OrderNew
+
+	switch {
+	case targetConsumed && !sourceConsumed:
+		return s.consumeIngredientsForOrder(ctx, source.ID)
+
+	case sourceConsumed && !targetConsumed:
+		if err := s.consumeIngredientsForOrder(ctx, target.ID); err != nil {
+			return err
+		}
+		if err := s.orderRepo.UpdateStatus(ctx, target.ID, domain.OrderInProgress); err != nil {
+			return err
+		}
+		target.Status = domain.OrderInProgress
+	}
+	return nil
+}
+
+func (s *OrderService) publishTableStatus(tableID int, status domain.TableStatus) {
+	s.events.Publish(domain.Event{
+		Type:    domain.EventTableStatusChanged,
+		TableID: tableID,
+		Status:  string(status),
+	})
+}
@@ -0,0 +1,402 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+)
+
+// CancelOrder отменяет весь заказ. До начала готовки отмена проходит сразу,
+// после — только менеджером, иначе ждёт его подтверждения.
+func (s *OrderService) CancelOrder(ctx context.Context, v *domain.OrderVoid, byManager bool) error {
+	s.logger.Warning("Cancelling order #%d (%s)", v.OrderID, v.ReasonCode)
+
+	v.OrderItemID = nil
+	return s.submitVoid(ctx, v, byManager)
+}
+
+// VoidItem отменяет позицию заказа целиком или частично (v.Qty).
+func (s *OrderService) VoidItem(ctx context.Context, v *domain.OrderVoid, byManager bool) error {
+	if v.OrderItemID == nil {
+		return domain.ErrOrderItemNotFound
+	}
+	s.logger.Warning("Voiding item #%d of order #%d (%s)", *v.OrderItemID, v.OrderID, v.ReasonCode)
+
+	return s.submitVoid(ctx, v, byManager)
+}
+
+func (s *OrderService) ApproveVoid(ctx context.Context, voidID, managerID int) error {
+	s.logger.Order("Approving void #%d by user #%d", voidID, managerID)
+
+	var v *domain.OrderVoid
+	var order *domain.Order
+	var freed bool
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		v, err = s.getPendingVoid(ctx, voidID)
+		if err != nil {
+			return err
+		}
+
+		order, err = s.getVoidableOrder(ctx, v.OrderID)
+		if err != nil {
+			return err
+		}
+
+		freed, err = s.applyVoid(ctx, order, v)
+		if err != nil {
+			return err
+		}
+
+		return s.voidRepo.Resolve(ctx, voidID, domain.VoidApproved, managerID)
+	})
+	if err != nil {
+		s.logger.Error("Failed to approve void #%d: %v", voidID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Void #%d approved", voidID)
+	s.publishVoidApplied(order, v, freed)
+	return nil
+}
+
+func (s *OrderService) RejectVoid(ctx context.Context, voidID, managerID int) error {
+	s.logger.Order("Rejecting void #%d by user #%d", voidID, managerID)
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.getPendingVoid(ctx, voidID); err != nil {
+			return err
+		}
+		return s.voidRepo.Resolve(ctx, voidID, domain.VoidRejected, managerID)
+	})
+	if err != nil {
+		s.logger.Error("Failed to reject void #%d: %v", voidID, err)
+		return err
+	}
+
+	s.logger.Success("✓ Void #%d rejected", voidID)
+	return nil
+}
+
+func (s *OrderService) GetVoids(ctx context.Context, orderID int) ([]domain.OrderVoid, error) {
+	return s.voidRepo.GetByOrderID(ctx, orderID)
+}
+
+func (s *OrderService) GetPendingVoids(ctx context.Context) ([]domain.OrderVoid, error) {
+	return s.voidRepo.GetPending(ctx)
+}
+
+// submitVoid записывает отмену и сразу применяет её, если подтверждение не нужно.
+func (s *OrderService) submitVoid(ctx context.Context, v *domain.OrderVoid, byManager bool) error {
+	if !v.ReasonCode.IsValid() || !v.StockAction.IsValid() {
+		return domain.ErrInvalidVoidInput
+	}
+
+	var order *domain.Order
+	var freed bool
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.getVoidableOrder(ctx, v.OrderID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.fillVoid(ctx, order, v); err != nil {
+			return err
+		}
+
+		v.Status = domain.VoidPending
+		apply := order.Status == domain.OrderNew || byManager
+		if apply {
+			now := time.Now()
+			v.Status = domain.VoidApproved
+			v.ResolvedAt = &now
+			if byManager {
+				v.ApprovedBy = &v.VoidedBy
+			}
+		}
+
+		// Запись создаём до применения: на неё ссылаются списания
+		if err := s.voidRepo.Create(ctx, v); err != nil {
+			return err
+		}
+		if !apply {
+			return nil
+		}
+		freed, err = s.applyVoid(ctx, order, v)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("Failed to void order #%d: %v", v.OrderID, err)
+		return err
+	}
+
+	if v.Status == domain.VoidPending {
+		s.logger.Warning("Void #%d for order #%d is waiting for manager approval", v.ID, v.OrderID)
+		event := newOrderEvent(domain.EventVoidRequested, order)
+		event.Message = string(v.ReasonCode)
+		s.events.Publish(event)
+		return nil
+	}
+
+	s.logger.Success("✓ Void #%d for order #%d applied (%.2f ₸)", v.ID, v.OrderID, v.Amount)
+	s.publishVoidApplied(order, v, freed)
+	return nil
+}
+
+// fillVoid дополняет запись об отмене данными заказа и проверяет количество.
+func (s *OrderService) fillVoid(ctx context.Context, order *domain.Order, v *domain.OrderVoid) error {
+	if v.IsOrderCancel() {
+		v.Qty = 0
+		v.DishID = nil
+		v.Amount = order.Total
+		return nil
+	}
+
+	item, err := s.findItem(ctx, order.ID, *v.OrderItemID)
+	if err != nil {
+		return err
+	}
+
+	if v.Qty <= 0 {
+		v.Qty = item.Qty
+	}
+	if v.Qty > item.Qty {
+		return domain.ErrVoidQtyExceeded
+	}
+
+	dishID := item.DishID
+	v.DishID = &dishID
+	v.Amount = item.Price * float64(v.Qty)
+	return nil
+}
+
+// applyVoid применяет отмену: двигает склад, меняет позиции и статусы.
+// Возвращает, освободился ли стол отменённого заказа.
+func (s *OrderService) applyVoid(ctx context.Context, order *domain.Order, v *domain.OrderVoid) (bool, error) {
+	if v.IsOrderCancel() {
+		items, err := s.orderRepo.GetItems(ctx, order.ID)
+		if err != nil {
+			return false, err
+		}
+		if err := s.returnVoidedStock(ctx, order, items, v); err != nil {
+			return false, err
+		}
+
+		if err := s.orderRepo.UpdateStatus(ctx, order.ID, domain.OrderCancelled); err != nil {
+			s.logger.Error("Failed to cancel order #%d: %v", order.ID, err)
+			return false, err
+		}
+		order.Status = domain.OrderCancelled
+
+		// За столом могут остаться другие открытые заказы
+		return freeTableIfEmpty(ctx, s.orderRepo, s.tableRepo, s.logger, order.TableNumber)
+	}
+
+	items, err := s.orderRepo.GetItems(ctx, order.ID)
+	if err != nil {
+		return false, err
+	}
+	item, err := s.findItem(ctx, order.ID, *v.OrderItemID)
+	if err != nil {
+		return false, err
+	}
+	if v.Qty > item.Qty {
+		return false, domain.ErrVoidQtyExceeded
+	}
+	if v.Qty == item.Qty && len(items) == 1 {
+		return false, domain.ErrLastOrderItem
+	}
+
+	voided := *item
+	voided.Qty = v.Qty
+	if err := s.returnVoidedStock(ctx, order, []domain.OrderItem{voided}, v); err != nil {
+		return false, err
+	}
+
+	if v.Qty == item.Qty {
+		if err := s.orderRepo.DeleteItem(ctx, item.ID); err != nil {
+			return false, err
+		}
+	} else {
+		item.Qty -= v.Qty
+		if err := s.orderRepo.UpdateItem(ctx, item); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.recalculateTotal(ctx, order); err != nil {
+		return false, err
+	}
+	return false, s.syncOrderStatus(ctx, order)
+}
+
+// returnVoidedStock решает судьбу ингредиентов отменённых позиций.
+// Пока заказ new — снимается только резерв; дальше ингредиенты уже списаны
+// и либо возвращаются на склад, либо остаются списанными как отходы.
+func (s *OrderService) returnVoidedStock(ctx context.Context, order *domain.Order, items []domain.OrderItem, v *domain.OrderVoid) error {
+	needs, err := s.ingredientNeeds(ctx, items)
+	if err != nil {
+		return err
+	}
+
+	if order.Status == domain.OrderNew {
+		if v.IsOrderCancel() {
+			return s.releaseReservation(ctx, order.ID)
+		}
+		return s.releaseIngredients(ctx, order.ID, needs)
+	}
+
+	userID := &v.VoidedBy
+	if v.ApprovedBy != nil {
+		userID = v.ApprovedBy
+	}
+	if v.StockAction == domain.StockWaste {
+		return s.wasteVoidedStock(ctx, items, v, userID)
+	}
+
+	note := string(v.ReasonCode)
+	for _, id := range sortedIngredientIDs(needs) {
+		m := domain.StockMovement{
+			IngredientID: id,
+			Type:         domain.MovementVoidReturn,
+			Qty:          needs[id],
+			RefID:        &order.ID,
+			UserID:       userID,
+			Note:         &note,
+		}
+		if err := s.moveStock(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wasteVoidedStock заносит отменённые позиции в журнал списаний. Со склада
+// их ингредиенты уже ушли расходом, поэтому в журнале движения они
+// возвращаются по отмене (void_return) и тут же списываются (waste) —
+// остаток не меняется, а расход переносится в потери.
+func (s *OrderService) wasteVoidedStock(ctx context.Context, items []domain.OrderItem, v *domain.OrderVoid, userID *int) error {
+	note := fmt.Sprintf("void #%d: %s", v.ID, v.ReasonCode)
+	for _, item := range items {
+		needs, err := s.ingredientNeeds(ctx, []domain.OrderItem{item})
+		if err != nil {
+			return err
+		}
+
+		dishID := item.DishID
+		entry := &domain.WasteEntry{
+			DishID: &dishID,
+			Qty:    float64(item.Qty),
+			Reason: domain.WasteReturned,
+			Note:   &note,
+			UserID: userID,
+		}
+		for _, id := range sortedIngredientIDs(needs) {
+			ingredient, err := s.ingredientRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if ingredient == nil {
+				return domain.ErrIngredientNotFound
+			}
+			entry.Items = append(entry.Items, domain.WasteItem{
+				IngredientID: id,
+				Qty:          needs[id],
+				UnitCost:     ingredient.UnitCost,
+			})
+			entry.Cost += needs[id] * ingredient.UnitCost
+		}
+		entry.Cost = roundMoney(entry.Cost)
+
+		if err := s.wasteRepo.Create(ctx, entry); err != nil {
+			s.logger.Error("Failed to log waste for void #%d: %v", v.ID, err)
+			return err
+		}
+
+		for _, wi := range entry.Items {
+			back := domain.StockMovement{
+				IngredientID: wi.IngredientID,
+				Type:         domain.MovementVoidReturn,
+				Qty:          wi.Qty,
+				RefID:        &v.ID,
+				UserID:       userID,
+				Note:         &note,
+			}
+			if err := s.moveStock(ctx, back); err != nil {
+				return err
+			}
+			waste := back
+			waste.Type = domain.MovementWaste
+			waste.Qty = -wi.Qty
+			waste.RefID = &entry.ID
+			if err := s.moveStock(ctx, waste); err != nil {
+				return err
+			}
+		}
+		s.logger.Warning("Void #%d: %d × dish #%d written off as waste (%.2f ₸)", v.ID, item.Qty, item.DishID, entry.Cost)
+	}
+	return nil
+}
+
+// getVoidableOrder возвращает заказ, в котором можно отменять позиции. После
+// первой оплаты отмена уменьшила бы сумму ниже оплаченной, поэтому сначала
+// нужен возврат денег.
+func (s *OrderService) getVoidableOrder(ctx context.Context, orderID int) (*domain.Order, error) {
+	if err := s.orderRepo.Lock(ctx, orderID); err != nil {
+		return nil, err
+	}
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		s.logger.Error("Failed to get order #%d: %v", orderID, err)
+		return nil, err
+	}
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+	// Объединённый (merged) заказ уже не обслуживается — его позиции у другого заказа
+	if !order.Status.IsOpen() {
+		s.logger.Error("Order #%d in status %s cannot be voided", orderID, order.Status)
+		return nil, domain.ErrOrderNotEditable
+	}
+	if err := s.sections.checkOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	paid, err := s.paymentRepo.GetPaidTotal(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if paid > 0 {
+		return nil, domain.ErrBillHasPayments
+	}
+	return order, nil
+}
+
+func (s *OrderService) getPendingVoid(ctx context.Context, voidID int) (*domain.OrderVoid, error) {
+	v, err := s.voidRepo.GetByID(ctx, voidID)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, domain.ErrVoidNotFound
+	}
+	if v.Status != domain.VoidPending {
+		return nil, domain.ErrVoidNotPending
+	}
+	return v, nil
+}
+
+func (s *OrderService) publishVoidApplied(order *domain.Order, v *domain.OrderVoid, tableFreed bool) {
+	if v.IsOrderCancel() {
+		s.publishOrderEvent(domain.EventOrderStatusChanged, order)
+		if tableFreed {
+			s.publishTableStatus(order.TableNumber, domain.TableFree)
+		}
+		return
+	}
+
+	s.notifyKitchen(order, fmt.Sprintf("voided dish #%d x%d (%s)", *v.DishID, v.Qty, v.ReasonCode))
+}
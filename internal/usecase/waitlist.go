@@ -0,0 +1,381 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+type WaitlistService struct {
+	waitlistRepo  ports.WaitlistRepository
+	tableRepo     ports.TableRepository
+	analyticsRepo ports.AnalyticsRepository
+	txManager     ports.TxManager
+	events        ports.EventPublisher
+	policy        domain.WaitlistPolicy
+	logger        *logger.Logger
+}
+
+func NewWaitlistService(
+	waitlistRepo ports.WaitlistRepository,
+	tableRepo ports.TableRepository,
+	analyticsRepo ports.AnalyticsRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+	policy domain.WaitlistPolicy,
+) *WaitlistService {
+	return &WaitlistService{
+		waitlistRepo:  waitlistRepo,
+		tableRepo:     tableRepo,
+		analyticsRepo: analyticsRepo,
+		txManager:     txManager,
+		events:        events,
+		policy:        policy,
+		logger:        logger.New("WaitlistService"),
+	}
+}
+
+// GetQueue возвращает очередь с местом каждой компании и текущей оценкой ожидания
+func (s *WaitlistService) GetQueue(ctx context.Context) ([]domain.WaitlistEntry, error) {
+	queue, err := s.waitlistRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(queue) == 0 {
+		return queue, nil
+	}
+
+	turns, err := s.newTurnModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range queue {
+		queue[i].Position = i + 1
+		if queue[i].Status == domain.WaitlistNotified {
+			continue
+		}
+		estimate, err := turns.estimate(queue[i].PartySize, queue[:i])
+		if err != nil && err != domain.ErrNoTableForParty {
+			return nil, err
+		}
+		if estimate != nil {
+			queue[i].EstimatedWait = estimate.Minutes
+		}
+	}
+	return queue, nil
+}
+
+// Estimate считает, сколько ждать новой компании, если поставить её в конец очереди
+func (s *WaitlistService) Estimate(ctx context.Context, partySize int) (*domain.WaitEstimate, error) {
+	if partySize <= 0 {
+		return nil, domain.ErrInvalidWaitlistEntry
+	}
+
+	queue, err := s.waitlistRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	turns, err := s.newTurnModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return turns.estimate(partySize, queue)
+}
+
+func (s *WaitlistService) Add(ctx context.Context, entry *domain.WaitlistEntry) error {
+	if !entry.IsValid() {
+		return domain.ErrInvalidWaitlistEntry
+	}
+
+	estimate, err := s.Estimate(ctx, entry.PartySize)
+	if err != nil {
+		return err
+	}
+
+	entry.Status = domain.WaitlistWaiting
+	entry.QuotedWait = estimate.Minutes
+	if err := s.waitlistRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to add %s to the waitlist: %v", entry.GuestName, err)
+		return err
+	}
+
+	s.logger.Success("✓ %s (%d guests) added to the waitlist, quoted %d min",
+		entry.GuestName, entry.PartySize, entry.QuotedWait)
+	s.publishWaitlistEvent(entry, domain.EventWaitlistChanged, 0)
+	return nil
+}
+
+// Cancel убирает компанию из очереди; стол, который держали для неё, предлагается следующей
+func (s *WaitlistService) Cancel(ctx context.Context, id int) error {
+	entry, err := s.getActive(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.waitlistRepo.UpdateStatus(ctx, id, domain.WaitlistCancelled, nil); err != nil {
+		s.logger.Error("Failed to cancel waitlist entry #%d: %v", id, err)
+		return err
+	}
+
+	entry.Status = domain.WaitlistCancelled
+	s.logger.Info("%s left the waitlist", entry.GuestName)
+	s.publishWaitlistEvent(entry, domain.EventWaitlistChanged, 0)
+
+	if entry.TableID != nil {
+		s.offerTable(ctx, *entry.TableID)
+	}
+	return nil
+}
+
+// Seat сажает компанию из очереди за свободный стол, не обязательно тот, о котором её известили
+func (s *WaitlistService) Seat(ctx context.Context, id, tableID int) error {
+	var entry *domain.WaitlistEntry
+	var releasedTable *int
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		table, err := s.tableRepo.GetByIDForUpdate(ctx, tableID)
+		if err != nil {
+			return err
+		}
+		if table == nil {
+			return domain.ErrTableNotFound
+		}
+
+		entry, err = s.getActive(ctx, id)
+		if err != nil {
+			return err
+		}
+		if table.Capacity < entry.PartySize {
+			return domain.ErrTableTooSmall
+		}
+		if table.Status != domain.TableFree {
+			return domain.ErrTableBusy
+		}
+
+		// Стол, придержанный для другой компании, отдавать нельзя
+		holder, err := s.waitlistRepo.GetNotifiedForTable(ctx, tableID)
+		if err != nil {
+			return err
+		}
+		if holder != nil && holder.ID != entry.ID {
+			return domain.ErrTableBusy
+		}
+
+		if entry.TableID != nil && *entry.TableID != tableID {
+			releasedTable = entry.TableID
+		}
+		if err := s.waitlistRepo.UpdateStatus(ctx, id, domain.WaitlistSeated, &tableID); err != nil {
+			return err
+		}
+		return s.tableRepo.UpdateStatus(ctx, tableID, domain.TableBusy)
+	})
+	if err != nil {
+		s.logger.Error("Failed to seat waitlist entry #%d at table #%d: %v", id, tableID, err)
+		return err
+	}
+
+	entry.Status = domain.WaitlistSeated
+	entry.TableID = &tableID
+	s.logger.Success("✓ %s seated at table #%d", entry.GuestName, tableID)
+	s.publishWaitlistEvent(entry, domain.EventWaitlistChanged, tableID)
+	s.events.Publish(domain.Event{
+		Type:    domain.EventTableStatusChanged,
+		TableID: tableID,
+		Status:  string(domain.TableBusy),
+	})
+
+	if releasedTable != nil {
+		s.offerTable(ctx, *releasedTable)
+	}
+	return nil
+}
+
+// Run слушает события столов и предлагает каждый освободившийся стол (закрытие
+// заказа, пересадка) первой подходящей компании из очереди, пока ctx не отменён.
+func (s *WaitlistService) Run(ctx context.Context, subscriber ports.EventSubscriber) {
+	events, unsubscribe := subscriber.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Type == domain.EventTableStatusChanged && event.Status == string(domain.TableFree) {
+				s.offerTable(ctx, event.TableID)
+			}
+		}
+	}
+}
+
+// offerTable держит свободный стол за первой подходящей по размеру компанией и извещает зал
+func (s *WaitlistService) offerTable(ctx context.Context, tableID int) {
+	var entry *domain.WaitlistEntry
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		table, err := s.tableRepo.GetByIDForUpdate(ctx, tableID)
+		if err != nil || table == nil || table.Status != domain.TableFree {
+			return err
+		}
+
+		holder, err := s.waitlistRepo.GetNotifiedForTable(ctx, tableID)
+		if err != nil || holder != nil {
+			return err
+		}
+
+		entry, err = s.waitlistRepo.GetNextFitting(ctx, table.Capacity)
+		if err != nil || entry == nil {
+			return err
+		}
+		return s.waitlistRepo.UpdateStatus(ctx, entry.ID, domain.WaitlistNotified, &tableID)
+	})
+	if err != nil {
+		s.logger.Error("Failed to offer table #%d to the waitlist: %v", tableID, err)
+		return
+	}
+	if entry == nil {
+		return
+	}
+
+	entry.Status = domain.WaitlistNotified
+	entry.TableID = &tableID
+	s.logger.Success("✓ Table #%d is ready for %s (%d guests)", tableID, entry.GuestName, entry.PartySize)
+	s.publishWaitlistEvent(entry, domain.EventWaitlistTableReady, tableID)
+}
+
+func (s *WaitlistService) getActive(ctx context.Context, id int) (*domain.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, domain.ErrWaitlistEntryNotFound
+	}
+	if !entry.Status.IsActive() {
+		return nil, domain.ErrWaitlistEntryClosed
+	}
+	return entry, nil
+}
+
+func (s *WaitlistService) publishWaitlistEvent(entry *domain.WaitlistEntry, eventType domain.EventType, tableID int) {
+	s.events.Publish(domain.Event{
+		Type:    eventType,
+		TableID: tableID,
+		Status:  string(entry.Status),
+		Message: fmt.Sprintf("%s (%d guests)", entry.GuestName, entry.PartySize),
+	})
+}
+
+// turnModel описывает, когда освободится каждый стол и как быстро он оборачивается
+type turnModel struct {
+	tables []tableTurn
+}
+
+type tableTurn struct {
+	capacity int
+	freeIn   float64 // minutes until the table is free
+	turn     float64 // minutes a party usually spends at the table
+}
+
+// newTurnModel строит модель по текущему залу и истории за Lookback: время оборота
+// стола берётся из его загрузки (TableUtilization), а без истории — из средней
+// длительности заказа (GetOrderStats) или политики по умолчанию.
+func (s *WaitlistService) newTurnModel(ctx context.Context) (*turnModel, error) {
+	now := time.Now()
+	from := now.Add(-s.policy.Lookback)
+
+	floor, err := s.tableRepo.GetFloor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utilization, err := s.analyticsRepo.GetTableUtilization(ctx, from, now)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.analyticsRepo.GetOrderStats(ctx, from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	averageTurn := s.policy.DefaultTurn.Minutes()
+	if stats.AverageTime > 0 {
+		averageTurn = stats.AverageTime
+	}
+
+	tableTurns := make(map[int]float64, len(utilization))
+	for _, u := range utilization {
+		if u.TimesUsed == 0 {
+			continue
+		}
+		// UtilizationRate — доля времени периода, которую стол был занят
+		busyMinutes := u.UtilizationRate / 100 * s.policy.Lookback.Minutes()
+		tableTurns[u.TableID] = busyMinutes / float64(u.TimesUsed)
+	}
+
+	model := &turnModel{}
+	for _, ft := range floor {
+		turn, ok := tableTurns[ft.ID]
+		if !ok || turn <= 0 {
+			turn = averageTurn
+		}
+
+		var freeIn float64
+		switch {
+		case ft.Status == domain.TableFree:
+			freeIn = 0
+		case ft.SeatedAt != nil:
+			freeIn = math.Max(turn-now.Sub(*ft.SeatedAt).Minutes(), 0)
+		default:
+			// Стол держат под бронь или заняли без заказа — считаем, что впереди целый оборот
+			freeIn = turn
+		}
+		model.tables = append(model.tables, tableTurn{capacity: ft.Capacity, freeIn: freeIn, turn: turn})
+	}
+	return model, nil
+}
+
+// estimate рассаживает компании из очереди ahead по мере освобождения подходящих
+// столов и возвращает, через сколько минут дойдёт очередь до новой компании.
+func (m *turnModel) estimate(partySize int, ahead []domain.WaitlistEntry) (*domain.WaitEstimate, error) {
+	var fitting []tableTurn
+	maxCapacity := 0
+	for _, t := range m.tables {
+		if t.capacity >= partySize {
+			fitting = append(fitting, t)
+			if t.capacity > maxCapacity {
+				maxCapacity = t.capacity
+			}
+		}
+	}
+	if len(fitting) == 0 {
+		return nil, domain.ErrNoTableForParty
+	}
+
+	// Впереди — компании, которые могут занять те же столы
+	competing := 0
+	for _, e := range ahead {
+		if e.Status.IsActive() && e.PartySize <= maxCapacity {
+			competing++
+		}
+	}
+
+	var minutes float64
+	for i := 0; i <= competing; i++ {
+		sort.Slice(fitting, func(a, b int) bool { return fitting[a].freeIn < fitting[b].freeIn })
+		minutes = fitting[0].freeIn
+		fitting[0].freeIn += fitting[0].turn
+	}
+
+	return &domain.WaitEstimate{
+		PartySize:    partySize,
+		PartiesAhead: competing,
+		Minutes:      int(math.Ceil(minutes)),
+	}, nil
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/YelzhanWeb/uno-spicchio/internal/domain"
+	"github.com/YelzhanWeb/uno-spicchio/internal/ports"
+	"github.com/YelzhanWeb/uno-spicchio/pkg/logger"
+)
+
+type WasteService struct {
+	wasteRepo      ports.WasteRepository
+	dishRepo       ports.DishRepository
+	ingredientRepo ports.IngredientRepository
+	prepRepo       ports.PrepRepository
+	txManager      ports.TxManager
+	events         ports.EventPublisher
+	logger         *logger.Logger
+}
+
+func NewWasteService(
+	wasteRepo ports.WasteRepository,
+	dishRepo ports.DishRepository,
+	ingredientRepo ports.IngredientRepository,
+	prepRepo ports.PrepRepository,
+	txManager ports.TxManager,
+	events ports.EventPublisher,
+) *WasteService {
+	return &WasteService{
+		wasteRepo:      wasteRepo,
+		dishRepo:       dishRepo,
+		ingredientRepo: ingredientRepo,
+		prepRepo:       prepRepo,
+		txManager:      txManager,
+		events:         events,
+		logger:         logger.New("WasteService"),
+	}
+}
+
+// Log списывает ингредиент или готовые порции блюда (по рецепту, заготовки
+// "по заказу" раскладываются на состав). Остаток уменьшается движением waste
+// со ссылкой на запись, себестоимость фиксируется по текущим средним ценам.
+func (s *WasteService) Log(ctx context.Context, entry *domain.WasteEntry) error {
+	if !entry.IsValid() {
+		return domain.ErrInvalidWasteEntry
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		needs, err := s.wasteNeeds(ctx, entry)
+		if err != nil {
+			return err
+		}
+
+		ids := sortedIngredientIDs(needs)
+		entry.Items = make([]domain.WasteItem, 0, len(ids))
+		entry.Cost = 0
+		for _, id := range ids {
+			needed := needs[id]
+
+			ingredient, err := s.ingredientRepo.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if ingredient == nil {
+				return domain.ErrIngredientNotFound
+			}
+			// Списать можно только то, что физически есть на складе
+			if ingredient.Qty < needed {
+				s.logger.Error("Cannot write off %.2f%s of '%s': only %.2f in stock",
+					needed, ingredient.Unit, ingredient.Name, ingredient.Qty)
+				return domain.ErrInsufficientStock
+			}
+
+			entry.Items = append(entry.Items, domain.WasteItem{
+				IngredientID: id,
+				Qty:          needed,
+				UnitCost:     ingredient.UnitCost,
+				Ingredient:   ingredient,
+			})
+			entry.Cost += needed * ingredient.UnitCost
+		}
+		entry.Cost = roundMoney(entry.Cost)
+
+		if err := s.wasteRepo.Create(ctx, entry); err != nil {
+			return err
+		}
+
+		note := string(entry.Reason)
+		for _, item := range entry.Items {
+			m := &domain.StockMovement{
+				IngredientID: item.IngredientID,
+				Type:         domain.MovementWaste,
+				Qty:          -item.Qty,
+				RefID:        &entry.ID,
+				UserID:       entry.UserID,
+				Note:         &note,
+			}
+			if err := s.ingredientRepo.ApplyMovement(ctx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Publish(domain.Event{Type: domain.EventStockChanged})
+	s.logger.Info("Waste #%d logged: %.2f × '%s' (%s), cost %.2f ₸",
+		entry.ID, entry.Qty, entry.ItemName, entry.Reason, entry.Cost)
+	return nil
+}
+
+func (s *WasteService) GetAll(ctx context.Context, filter domain.WasteFilter) ([]domain.WasteEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	return s.wasteRepo.GetAll(ctx, filter)
+}
+
+func (s *WasteService) GetByID(ctx context.Context, id int) (*domain.WasteEntry, error) {
+	entry, err := s.wasteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, domain.ErrWasteEntryNotFound
+	}
+	return entry, nil
+}
+
+// wasteNeeds считает, сколько каких ингредиентов уходит со склада по записи
+func (s *WasteService) wasteNeeds(ctx context.Context, entry *domain.WasteEntry) (map[int]float64, error) {
+	needs := make(map[int]float64)
+
+	if entry.IngredientID != nil {
+		ingredient, err := s.ingredientRepo.GetByID(ctx, *entry.IngredientID)
+		if err != nil {
+			return nil, err
+		}
+		if ingredient == nil {
+			return nil, domain.ErrIngredientNotFound
+		}
+		entry.ItemName = ingredient.Name
+		needs[ingredient.ID] = entry.Qty
+	} else {
+		dish, err := s.dishRepo.GetByID(ctx, *entry.DishID)
+		if err != nil {
+			return nil, err
+		}
+		if dish == nil {
+			return nil, domain.ErrDishNotFound
+		}
+		entry.ItemName = dish.Name
+
+		recipe, err := s.dishRepo.GetIngredients(ctx, dish.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, ing := range recipe {
+			needs[ing.IngredientID] += ing.QtyPerDish * entry.Qty
+		}
+	}
+
+	preps, err := s.prepRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPrepRecipes(preps).Resolve(needs), nil
+}
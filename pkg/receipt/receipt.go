@@ -72,7 +72,17 @@ func GenerateOrderReceiptPDF(order *domain.Order, waiterName, folder string) (st
 
 	pdf.SetFont("Helvetica", "", 11)
 
+	// блюда из комбо печатаются под строкой своего набора
+	combos := make(map[int]bool, len(order.Combos))
+	for _, line := range order.Combos {
+		combos[line.ID] = true
+	}
+
+	itemDiscounts := 0.0
 	for _, item := range order.Items {
+		if item.ComboLineID != nil && combos[*item.ComboLineID] {
+			continue
+		}
 		itemName := fmt.Sprintf("x%d  %s", item.Qty, item.Dish.Name)
 
 		// LEFT: name
@@ -81,6 +91,45 @@ func GenerateOrderReceiptPDF(order *domain.Order, waiterName, folder string) (st
 		pdf.CellFormat(50, 6,
 			fmt.Sprintf("%.2f", item.Price*float64(item.Qty)),
 			"", 1, "R", false, 0, "")
+
+		// модификаторы — под позицией, их надбавка уже в цене
+		for _, m := range item.Modifiers {
+			modifier := "      + " + m.Name
+			if m.PriceDelta != 0 {
+				modifier += fmt.Sprintf(" (%+.2f)", m.PriceDelta)
+			}
+			pdf.CellFormat(0, 5, modifier, "", 1, "L", false, 0, "")
+		}
+
+		// скидка на позицию — отдельной строкой под ней
+		if item.Discount > 0 {
+			itemDiscounts += item.Discount
+			pdf.CellFormat(120, 5, "      discount", "", 0, "L", false, 0, "")
+			pdf.CellFormat(50, 5, fmt.Sprintf("-%.2f", item.Discount), "", 1, "R", false, 0, "")
+		}
+	}
+
+	for _, line := range order.Combos {
+		pdf.CellFormat(120, 6, fmt.Sprintf("x%d  %s", line.Qty, line.Name), "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 6, fmt.Sprintf("%.2f", line.Price*float64(line.Qty)), "", 1, "R", false, 0, "")
+
+		lineDiscount := 0.0
+		for _, item := range order.Items {
+			if item.ComboLineID == nil || *item.ComboLineID != line.ID {
+				continue
+			}
+			pdf.CellFormat(0, 5, "      - "+item.Dish.Name, "", 1, "L", false, 0, "")
+			for _, m := range item.Modifiers {
+				pdf.CellFormat(0, 5, "          + "+m.Name, "", 1, "L", false, 0, "")
+			}
+			lineDiscount += item.Discount
+		}
+
+		if lineDiscount > 0 {
+			itemDiscounts += lineDiscount
+			pdf.CellFormat(120, 5, "      discount", "", 0, "L", false, 0, "")
+			pdf.CellFormat(50, 5, fmt.Sprintf("-%.2f", lineDiscount), "", 1, "R", false, 0, "")
+		}
 	}
 
 	// line before TOTAL
@@ -88,6 +137,38 @@ func GenerateOrderReceiptPDF(order *domain.Order, waiterName, folder string) (st
 	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
 	pdf.Ln(2)
 
+	// --- SUBTOTAL, DISCOUNTS, SERVICE, VAT ---
+	pdf.SetFont("Helvetica", "", 11)
+	if order.Discount > 0 || order.Tax > 0 || order.ServiceCharge > 0 {
+		pdf.CellFormat(120, 6, "Subtotal", "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", order.Subtotal), "", 1, "R", false, 0, "")
+	}
+	if order.Discount > 0 {
+
+		if itemDiscounts > 0 {
+			pdf.CellFormat(120, 6, "Item discounts", "", 0, "L", false, 0, "")
+			pdf.CellFormat(50, 6, fmt.Sprintf("-%.2f KZT", itemDiscounts), "", 1, "R", false, 0, "")
+		}
+		if orderDiscount := order.Discount - itemDiscounts; orderDiscount > 0.005 {
+			label := "Order discount"
+			if order.PromoCode != nil {
+				label = fmt.Sprintf("Order discount (%s)", *order.PromoCode)
+			}
+			pdf.CellFormat(120, 6, label, "", 0, "L", false, 0, "")
+			pdf.CellFormat(50, 6, fmt.Sprintf("-%.2f KZT", orderDiscount), "", 1, "R", false, 0, "")
+		}
+	}
+	if order.ServiceCharge > 0 {
+		pdf.CellFormat(120, 6, fmt.Sprintf("Service charge (%.2f%%)", order.ServiceChargeRate), "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", order.ServiceCharge), "", 1, "R", false, 0, "")
+	}
+	if order.Tax > 0 {
+		for _, line := range vatLines(order.Items) {
+			pdf.CellFormat(120, 6, fmt.Sprintf("VAT %.2f%%", line.rate), "", 0, "L", false, 0, "")
+			pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", line.amount), "", 1, "R", false, 0, "")
+		}
+	}
+
 	// --- TOTAL ---
 	pdf.SetFont("Helvetica", "B", 12)
 	pdf.CellFormat(120, 8, "Total", "", 0, "L", false, 0, "")
@@ -114,3 +195,144 @@ func GenerateOrderReceiptPDF(order *domain.Order, waiterName, folder string) (st
 
 	return filename, nil
 }
+
+// GeneratePaymentReceiptPDF печатает чек одного плательщика при разделённом счёте.
+// split может быть nil, если гость платил без привязки к части счёта.
+type vatLine struct {
+	rate   float64
+	amount float64
+}
+
+// vatLines группирует налог позиций по ставкам — в чеке НДС печатается по каждой ставке
+func vatLines(items []domain.OrderItem) []vatLine {
+	var lines []vatLine
+	for _, item := range items {
+		if item.Tax <= 0 {
+			continue
+		}
+		found := false
+		for i := range lines {
+			if lines[i].rate == item.VatRate {
+				lines[i].amount += item.Tax
+				found = true
+			}
+		}
+		if !found {
+			lines = append(lines, vatLine{rate: item.VatRate, amount: item.Tax})
+		}
+	}
+	return lines
+}
+
+func GeneratePaymentReceiptPDF(order *domain.Order, payment *domain.Payment, split *domain.BillSplit, balance float64, waiterName, folder string) (string, error) {
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", err
+	}
+
+	filename := fmt.Sprintf("%s/order_%d_payment_%d.pdf", folder, order.ID, payment.ID)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(25, 15, 25)
+	pdf.AddPage()
+
+	// --- HEADER ---
+	pdf.SetFont("Helvetica", "B", 24)
+	pdf.CellFormat(0, 12, "UNO Spicchio", "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 14)
+	pdf.CellFormat(0, 10, "Rakhmet!", "", 1, "C", false, 0, "")
+
+	pdf.Ln(5)
+	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
+	pdf.Ln(5)
+
+	// --- TICKET INFO ---
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(90, 5, "TICKET ID", "", 0, "L", false, 0, "")
+	pdf.CellFormat(90, 5, "Payer", "", 1, "R", false, 0, "")
+
+	payer := "Guest"
+	if split != nil {
+		payer = split.Label
+	}
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(90, 6, fmt.Sprintf("%012d-%d", order.ID, payment.ID), "", 0, "L", false, 0, "")
+	pdf.CellFormat(90, 6, payer, "", 1, "R", false, 0, "")
+
+	pdf.Ln(3)
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(90, 5, "DATE & TIME", "", 0, "L", false, 0, "")
+	pdf.CellFormat(90, 5, "Table / Waiter", "", 1, "R", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(90, 6, payment.CreatedAt.Format("02 Jan 2006 at 15:04"), "", 0, "L", false, 0, "")
+	pdf.CellFormat(90, 6,
+		fmt.Sprintf("Table #%d  |  %s", order.TableNumber, waiterName),
+		"", 1, "R", false, 0, "",
+	)
+
+	pdf.Ln(4)
+	pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
+	pdf.Ln(6)
+
+	// --- ITEMS OF THIS SHARE ---
+	if split != nil && len(split.Items) > 0 {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(0, 6, "Your items", "", 1, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "", 11)
+		for _, item := range split.Items {
+			pdf.CellFormat(120, 6, fmt.Sprintf("x%d  %s", item.Qty, item.DishName), "", 0, "L", false, 0, "")
+			pdf.CellFormat(50, 6, fmt.Sprintf("%.2f", item.Amount), "", 1, "R", false, 0, "")
+		}
+
+		pdf.Ln(3)
+		pdf.Line(25, pdf.GetY(), 185, pdf.GetY())
+		pdf.Ln(2)
+	}
+
+	// --- PAYMENT ---
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(120, 6, "Order total", "", 0, "L", false, 0, "")
+	pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", order.Total), "", 1, "R", false, 0, "")
+	if payment.ServiceCharge > 0 {
+		pdf.CellFormat(120, 6, "incl. service charge", "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", payment.ServiceCharge), "", 1, "R", false, 0, "")
+	}
+	if payment.Tip > 0 {
+		pdf.CellFormat(120, 6, "Tip", "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", payment.Tip), "", 1, "R", false, 0, "")
+	}
+	pdf.CellFormat(120, 6, fmt.Sprintf("Paid by %s", payment.Method), "", 0, "L", false, 0, "")
+	pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", payment.Tendered), "", 1, "R", false, 0, "")
+	if payment.Change > 0 {
+		pdf.CellFormat(120, 6, "Change", "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", payment.Change), "", 1, "R", false, 0, "")
+	}
+
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(120, 8, "Amount", "", 0, "L", false, 0, "")
+	pdf.CellFormat(50, 8, fmt.Sprintf("%.2f KZT", payment.Amount), "", 1, "R", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(120, 6, "Remaining on table", "", 0, "L", false, 0, "")
+	pdf.CellFormat(50, 6, fmt.Sprintf("%.2f KZT", balance), "", 1, "R", false, 0, "")
+
+	pdf.Ln(10)
+
+	// --- FOOTER TEXT ---
+	pdf.SetFont("Helvetica", "", 10)
+	footerText := fmt.Sprintf("Order #%d   /   %s", order.ID, payment.CreatedAt.Format("02.01.2006 15:04"))
+	pdf.CellFormat(0, 6, footerText, "", 1, "C", false, 0, "")
+
+	// Save
+	err := pdf.OutputFileAndClose(filename)
+	if err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}